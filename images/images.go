@@ -0,0 +1,21 @@
+package images
+
+import (
+    _ "embed"
+    "fyne.io/fyne/v2"
+)
+
+//go:embed carro.png
+var carroPNG []byte
+
+//go:embed bg_night.png
+var bgNightPNG []byte
+
+//go:embed bg_day.png
+var bgDayPNG []byte
+
+var Car = fyne.NewStaticResource("carro.png", carroPNG)
+
+var BackgroundNight = fyne.NewStaticResource("bg_night.png", bgNightPNG)
+
+var BackgroundDay = fyne.NewStaticResource("bg_day.png", bgDayPNG)
@@ -1,15 +1,69 @@
-package main
-
-import (
-    "holafyne/scenes"
-    "fyne.io/fyne/v2/app"
-)
-
-func main() {
-    myApp := app.New()
-    window := myApp.NewWindow("Simulador de Estacionamiento")
-    
-    scenes.NewParkingScene(window)
-    
-    window.ShowAndRun()
+package main
+
+import (
+    "context"
+    "flag"
+    "log"
+    "net/http"
+    "os"
+    "os/signal"
+    "syscall"
+    "holafyne/scenes"
+    "holafyne/services"
+    "fyne.io/fyne/v2/app"
+)
+
+// appID identifica a la aplicación ante Fyne; sin él las preferencias (el
+// tour visto, las notificaciones, el fondo) no se guardan entre sesiones.
+const appID = "com.github.isaactoledo123.simuladorestacionamiento"
+
+func main() {
+    metricsAddr := flag.String("metrics", "", "dirección para exponer /debug/vars y /prediction (ej. :6060)")
+    vehicleLog := flag.String("vehicle-log", "", "archivo CSV al que se anexa una fila por vehículo (ej. vehiculos.csv)")
+    debug := flag.Bool("debug", false, "muestra la cola interna del estacionamiento junto a la de la simulación")
+    flag.Parse()
+
+    myApp := app.NewWithID(appID)
+    window := myApp.NewWindow("Simulador de Estacionamiento")
+    
+    scene := scenes.NewParkingScene(window)
+    if *debug {
+        scene.EnableQueueDebug()
+    }
+    if *vehicleLog != "" {
+        if err := scene.EnableVehicleLog(*vehicleLog); err != nil {
+            log.Printf("no se pudo configurar el registro de vehículos: %v", err)
+        }
+    }
+
+    lifecycle := services.NewLifecycle()
+    if *metricsAddr != "" {
+        services.PublishExpvar(scene.GetSimulation())
+        var server *http.Server
+        lifecycle.Register("servidor de métricas", func() error {
+            var err error
+            server, err = services.StartMetricsServer(*metricsAddr)
+            return err
+        }, func(ctx context.Context) error {
+            return server.Shutdown(ctx)
+        })
+    }
+    for _, err := range lifecycle.Start() {
+        log.Printf("no se pudo iniciar un componente: %v", err)
+    }
+
+    interrupt := make(chan os.Signal, 1)
+    signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+    go func() {
+        <-interrupt
+        myApp.Quit()
+    }()
+    
+    window.ShowAndRun()
+
+    scene.Close()
+    scene.GetSimulation().Stop()
+    for _, err := range lifecycle.Stop() {
+        log.Printf("error al detener un componente: %v", err)
+    }
 }
\ No newline at end of file
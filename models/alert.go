@@ -0,0 +1,46 @@
+package models
+
+// SetOccupancyAlertThreshold registra una alerta que se dispara una sola vez
+// cuando la ocupación llega a pct (entre 0 y 1). Para que vuelva a
+// dispararse hay que llamar a ClearAlert.
+func (p *ParkingLot) SetOccupancyAlertThreshold(pct float64, callback func(float64)) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.alertThreshold = pct
+    p.onAlert = callback
+    p.alertFired = false
+}
+
+func (p *ParkingLot) ClearAlert() {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.alertFired = false
+}
+
+func (p *ParkingLot) OccupancyRate() float64 {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return p.occupancyRate()
+}
+
+func (p *ParkingLot) occupancyRate() float64 {
+    if p.Capacity == 0 {
+        return 0
+    }
+    return float64(p.occupiedSpaces) / float64(p.Capacity)
+}
+
+// checkAlert debe llamarse con mu tomado; devuelve la función a llamar, ya
+// sin el lock, si la alerta acaba de dispararse.
+func (p *ParkingLot) checkAlert() func() {
+    if p.onAlert == nil || p.alertThreshold <= 0 || p.alertFired {
+        return nil
+    }
+    rate := p.occupancyRate()
+    if rate < p.alertThreshold {
+        return nil
+    }
+    p.alertFired = true
+    callback := p.onAlert
+    return func() { callback(rate) }
+}
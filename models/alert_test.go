@@ -0,0 +1,57 @@
+package models
+
+import "testing"
+
+func TestOccupancyAlertFiresOnceOnCrossing(t *testing.T) {
+    // Cada paso es "e" (entra un vehículo), "x" (sale el último) o "c"
+    // (ClearAlert).
+    tests := []struct {
+        name      string
+        threshold float64
+        steps     []string
+        want      []float64
+    }{
+        {"no llega al umbral", 0.5, []string{"e", "e", "e", "e"}, nil},
+        {"dispara al cruzar", 0.5, []string{"e", "e", "e", "e", "e"}, []float64{0.5}},
+        {"una sola vez por encima", 0.5, []string{"e", "e", "e", "e", "e", "e", "e"}, []float64{0.5}},
+        {"bajar y volver a cruzar sin limpiar", 0.5, []string{"e", "e", "e", "e", "e", "x", "e"}, []float64{0.5}},
+        {"limpiar y volver a cruzar", 0.5, []string{"e", "e", "e", "e", "e", "x", "c", "e"}, []float64{0.5, 0.5}},
+        {"limpiar por encima del umbral", 0.5, []string{"e", "e", "e", "e", "e", "c", "e"}, []float64{0.5, 0.6}},
+        {"sin umbral", 0, []string{"e", "e", "e", "e", "e"}, nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lot := NewParkingLot(10, func(int, string) {})
+            var got []float64
+            lot.SetOccupancyAlertThreshold(tt.threshold, func(rate float64) {
+                got = append(got, rate)
+            })
+
+            var parked []*Vehicle
+            for i, step := range tt.steps {
+                switch step {
+                case "e":
+                    vehicle := NewVehicle(i + 1)
+                    if !lot.TryEnter(vehicle) {
+                        t.Fatalf("paso %d: el vehículo %d no pudo entrar", i, vehicle.ID)
+                    }
+                    parked = append(parked, vehicle)
+                case "x":
+                    lot.Exit(parked[len(parked)-1])
+                    parked = parked[:len(parked)-1]
+                case "c":
+                    lot.ClearAlert()
+                }
+            }
+
+            if len(got) != len(tt.want) {
+                t.Fatalf("alertas = %v, want %v", got, tt.want)
+            }
+            for i := range got {
+                if got[i] != tt.want[i] {
+                    t.Errorf("alerta %d con ocupación %.2f, want %.2f", i, got[i], tt.want[i])
+                }
+            }
+        })
+    }
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+const (
+    // MAX_SPACE_CONFLICTS limita cuántos conflictos conserva el historial.
+    MAX_SPACE_CONFLICTS = 1000
+    // SPACE_CONFLICT_WINDOW es cuánto dura la selección de un espacio: si
+    // otro TryEnter elige el mismo espacio antes de que pase y sin que el
+    // primero lo haya soltado, es un conflicto.
+    SPACE_CONFLICT_WINDOW = 50 * time.Millisecond
+
+    CONFLICT_REJECTED   = "rechazado"
+    CONFLICT_REASSIGNED = "reasignado"
+)
+
+// SpaceConflict registra dos vehículos que seleccionaron el mismo espacio
+// dentro de SPACE_CONFLICT_WINDOW. Si el espacio seguía ocupado el segundo
+// se rechaza; si ya estaba libre sin que el primero lo soltara, se le
+// reasigna. Con el lock funcionando el historial queda vacío.
+type SpaceConflict struct {
+    SpaceID    int
+    Vehicle1ID int
+    Vehicle2ID int
+    At         time.Time
+    Resolution string
+}
+
+// spaceClaim es la última selección de un espacio, desde TryEnter hasta que
+// el vehículo sale o se deshace su entrada.
+type spaceClaim struct {
+    vehicleID int
+    at        time.Time
+}
+
+// claimSpace anota que vehicle seleccionó spaceID y registra un conflicto si
+// otro vehículo lo seleccionó dentro de la ventana y no lo soltó. Devuelve
+// falso si el espacio sigue ocupado. Debe llamarse con mu tomado.
+func (p *ParkingLot) claimSpace(spaceID int, vehicle *Vehicle) bool {
+    now := time.Now()
+    if previous, ok := p.claims[spaceID]; ok && previous.vehicleID != vehicle.ID && now.Sub(previous.at) < SPACE_CONFLICT_WINDOW {
+        conflict := SpaceConflict{SpaceID: spaceID, Vehicle1ID: previous.vehicleID, Vehicle2ID: vehicle.ID, At: now, Resolution: CONFLICT_REASSIGNED}
+        occupied := p.spaces[spaceID].OccupiedBy != nil
+        if occupied {
+            conflict.Resolution = CONFLICT_REJECTED
+        }
+        p.recordConflict(conflict)
+        if occupied {
+            return false
+        }
+    }
+    p.claims[spaceID] = spaceClaim{vehicleID: vehicle.ID, at: now}
+    return true
+}
+
+// releaseSpaceClaim suelta la selección de spaceID. Debe llamarse con mu
+// tomado.
+func (p *ParkingLot) releaseSpaceClaim(spaceID int) {
+    delete(p.claims, spaceID)
+}
+
+func (p *ParkingLot) recordConflict(conflict SpaceConflict) {
+    p.conflicts = append(p.conflicts, conflict)
+    if len(p.conflicts) > MAX_SPACE_CONFLICTS {
+        p.conflicts = p.conflicts[len(p.conflicts)-MAX_SPACE_CONFLICTS:]
+    }
+}
+
+// GetConflictHistory devuelve los conflictos registrados, del más antiguo al
+// más reciente.
+func (p *ParkingLot) GetConflictHistory() []SpaceConflict {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return append([]SpaceConflict(nil), p.conflicts...)
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+    "sync"
+    "testing"
+    "time"
+)
+
+func TestTryEnterUnderConcurrencyRecordsNoConflicts(t *testing.T) {
+    tests := []struct {
+        name     string
+        capacity int
+        workers  int
+        rounds   int
+    }{
+        {"un espacio por trabajador", 8, 8, 50},
+        {"muchos trabajadores", 64, 64, 20},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lot := NewParkingLot(tt.capacity, func(int, string) {})
+            var wg sync.WaitGroup
+            for w := 0; w < tt.workers; w++ {
+                wg.Add(1)
+                go func(w int) {
+                    defer wg.Done()
+                    for r := 0; r < tt.rounds; r++ {
+                        vehicle := NewVehicle(w*tt.rounds + r + 1)
+                        if !lot.TryEnter(vehicle) {
+                            t.Errorf("vehículo %d no pudo entrar con espacios libres", vehicle.ID)
+                            return
+                        }
+                        lot.Exit(vehicle)
+                    }
+                }(w)
+            }
+            wg.Wait()
+
+            if conflicts := lot.GetConflictHistory(); len(conflicts) != 0 {
+                t.Errorf("conflictos = %d, want 0: %+v", len(conflicts), conflicts[0])
+            }
+            if occupancy := lot.GetOccupancy(); occupancy != 0 {
+                t.Errorf("ocupación final = %d, want 0", occupancy)
+            }
+            if history := len(lot.GetSpaceHistory()); history != tt.workers*tt.rounds {
+                t.Errorf("salidas = %d, want %d", history, tt.workers*tt.rounds)
+            }
+        })
+    }
+}
+
+func TestClaimSpaceDetectsSameSpaceWithinWindow(t *testing.T) {
+    tests := []struct {
+        name       string
+        claimAge   time.Duration
+        claimer    int
+        occupied   bool
+        wantOK     bool
+        resolution string
+    }{
+        {"espacio ocupado dentro de la ventana", time.Millisecond, 1, true, false, CONFLICT_REJECTED},
+        {"espacio libre sin soltar la selección", time.Millisecond, 1, false, true, CONFLICT_REASSIGNED},
+        {"selección vencida", 2 * SPACE_CONFLICT_WINDOW, 1, false, true, ""},
+        {"mismo vehículo", time.Millisecond, 2, false, true, ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lot := NewParkingLot(1, func(int, string) {})
+            lot.claims[0] = spaceClaim{vehicleID: tt.claimer, at: time.Now().Add(-tt.claimAge)}
+            if tt.occupied {
+                lot.spaces[0].OccupiedBy = NewVehicle(tt.claimer)
+            }
+
+            lot.mu.Lock()
+            ok := lot.claimSpace(0, NewVehicle(2))
+            lot.mu.Unlock()
+            if ok != tt.wantOK {
+                t.Errorf("claimSpace = %v, want %v", ok, tt.wantOK)
+            }
+            conflicts := lot.GetConflictHistory()
+            if tt.resolution == "" {
+                if len(conflicts) != 0 {
+                    t.Errorf("conflictos = %+v, want ninguno", conflicts)
+                }
+                return
+            }
+            if len(conflicts) != 1 {
+                t.Fatalf("conflictos = %d, want 1", len(conflicts))
+            }
+            if got := conflicts[0]; got.SpaceID != 0 || got.Vehicle1ID != tt.claimer || got.Vehicle2ID != 2 || got.Resolution != tt.resolution {
+                t.Errorf("conflicto = %+v, want espacio 0, vehículos %d y 2, %q", got, tt.claimer, tt.resolution)
+            }
+        })
+    }
+}
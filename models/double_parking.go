@@ -0,0 +1,105 @@
+package models
+
+import (
+    "fmt"
+    "math/rand"
+    "time"
+)
+
+const DoubleParkingProbability = 0.1
+
+// spaceClosure es un intervalo en que un espacio estuvo bloqueado. to queda
+// en cero mientras siga bloqueado.
+type spaceClosure struct {
+    spaceID int
+    from    time.Time
+    to      time.Time
+}
+
+// SetDoubleParking permite que un vehículo, al estacionarse, bloquee también
+// un espacio vecino libre durante penalty.
+func (p *ParkingLot) SetDoubleParking(allowed bool, penalty time.Duration) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.doubleParking = allowed
+    p.doublePenalty = penalty
+}
+
+// SetDoubleParkingCallback registra la función que se llama cuando un espacio
+// queda bloqueado por doble estacionamiento y cuando se libera.
+func (p *ParkingLot) SetDoubleParkingCallback(callback func(spaceID int, blocked bool)) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.onDoublePark = callback
+}
+
+func (p *ParkingLot) maybeDoublePark(vehicle *Vehicle, spaceID int) {
+    p.mu.Lock()
+    if !p.doubleParking || p.doublePenalty <= 0 || rand.Float64() >= DoubleParkingProbability {
+        p.mu.Unlock()
+        return
+    }
+
+    blockedID := -1
+    for _, adjacentID := range p.adjacentSpaces(spaceID) {
+        if p.spaces[adjacentID].IsFree() {
+            blockedID = adjacentID
+            break
+        }
+    }
+    if blockedID < 0 || !p.acquireSpace() {
+        p.mu.Unlock()
+        return
+    }
+
+    p.spaces[blockedID].Blocked = true
+    p.recordClosure(blockedID, time.Now())
+    p.occupiedSpaces++
+    penalty := p.doublePenalty
+    callback := p.onDoublePark
+    spaces := p.availableSpaces()
+    p.UpdateUI(int(spaces), fmt.Sprintf("%s estacionó en doble fila y bloquea %s", vehicle, p.spaces[blockedID].Label))
+    p.mu.Unlock()
+
+    if callback != nil {
+        callback(blockedID, true)
+    }
+    time.AfterFunc(penalty, func() { p.releaseBlocked(blockedID) })
+}
+
+func (p *ParkingLot) releaseBlocked(spaceID int) {
+    p.mu.Lock()
+    if !p.spaces[spaceID].Blocked {
+        p.mu.Unlock()
+        return
+    }
+    p.spaces[spaceID].Blocked = false
+    p.endClosure(spaceID, time.Now())
+    p.occupiedSpaces--
+    p.releaseSpace()
+    callback := p.onDoublePark
+    spaces := p.availableSpaces()
+    p.UpdateUI(int(spaces), fmt.Sprintf("%s quedó libre. Espacios disponibles: %d", p.spaces[spaceID].Label, spaces))
+    p.mu.Unlock()
+
+    if callback != nil {
+        callback(spaceID, false)
+    }
+}
+
+// recordClosure y endClosure deben llamarse con mu tomado.
+func (p *ParkingLot) recordClosure(spaceID int, at time.Time) {
+    p.closures = append(p.closures, spaceClosure{spaceID: spaceID, from: at})
+    if len(p.closures) > MAX_SPACE_HISTORY {
+        p.closures = p.closures[len(p.closures)-MAX_SPACE_HISTORY:]
+    }
+}
+
+func (p *ParkingLot) endClosure(spaceID int, at time.Time) {
+    for i := len(p.closures) - 1; i >= 0; i-- {
+        if p.closures[i].spaceID == spaceID && p.closures[i].to.IsZero() {
+            p.closures[i].to = at
+            return
+        }
+    }
+}
@@ -0,0 +1,40 @@
+package models
+
+// CloseEntry impide que entren más vehículos y espera a que terminen las
+// entradas que ya habían pasado el control. Los vehículos rechazados mientras
+// la entrada está cerrada no se agregan a la cola interna.
+func (p *ParkingLot) CloseEntry() {
+    p.mu.Lock()
+    p.entryClosed = true
+    p.mu.Unlock()
+    p.entries.Wait()
+}
+
+func (p *ParkingLot) OpenEntry() {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.entryClosed = false
+}
+
+// UseExternalQueue indica que quien usa el estacionamiento lleva su propia
+// cola: un TryEnter fallido ya no agrega al vehículo a la cola interna, así
+// que Exit no le cede el espacio a alguien que ya está esperando afuera.
+func (p *ParkingLot) UseExternalQueue() {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.externalQueue = true
+    p.waitingQueue = p.waitingQueue[:0]
+}
+
+// addWaiting debe llamarse con mu tomado.
+func (p *ParkingLot) addWaiting(vehicle *Vehicle) {
+    if !p.externalQueue {
+        p.waitingQueue = append(p.waitingQueue, vehicle)
+    }
+}
+
+func (p *ParkingLot) IsEntryOpen() bool {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return !p.entryClosed
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+    "testing"
+    "time"
+)
+
+func TestExitHandsOffOnlyWithInternalQueue(t *testing.T) {
+    tests := []struct {
+        name          string
+        external      bool
+        wantWaiting   int
+        wantOccupancy int
+    }{
+        {"cola interna", false, 1, 1},
+        {"cola externa", true, 0, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lot := NewParkingLot(1, func(int, string) {})
+            if tt.external {
+                lot.UseExternalQueue()
+            }
+            first := NewVehicle(1)
+            if !lot.TryEnter(first) {
+                t.Fatal("el primer vehículo no pudo entrar")
+            }
+            if lot.TryEnter(NewVehicle(2)) {
+                t.Fatal("el segundo vehículo entró con el estacionamiento lleno")
+            }
+            if waiting := len(lot.GetWaitingVehicles()); waiting != tt.wantWaiting {
+                t.Errorf("cola interna = %d, want %d", waiting, tt.wantWaiting)
+            }
+
+            lot.Exit(first)
+            deadline := time.Now().Add(time.Second)
+            for lot.GetOccupancy() != tt.wantOccupancy && time.Now().Before(deadline) {
+                time.Sleep(time.Millisecond)
+            }
+            if occupancy := lot.GetOccupancy(); occupancy != tt.wantOccupancy {
+                t.Errorf("ocupación después de la salida = %d, want %d", occupancy, tt.wantOccupancy)
+            }
+        })
+    }
+}
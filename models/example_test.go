@@ -0,0 +1,32 @@
+package models_test
+
+import (
+    "fmt"
+    "holafyne/models"
+)
+
+func ExampleParkingLot() {
+    lot := models.NewParkingLot(2, func(spaces int, message string) {})
+    first, second, third := models.NewVehicle(1), models.NewVehicle(2), models.NewVehicle(3)
+
+    fmt.Println(lot.TryEnter(first), lot.TryEnter(second), lot.TryEnter(third))
+    fmt.Println("ocupación:", lot.GetOccupancy(), "libres:", lot.GetAvailableSpaces())
+
+    lot.Exit(first)
+    fmt.Println("ocupación:", lot.GetOccupancy(), "libres:", lot.GetAvailableSpaces())
+    // Output:
+    // true true false
+    // ocupación: 2 libres: 0
+    // ocupación: 1 libres: 1
+}
+
+func ExampleVehicle_SetState() {
+    vehicle := models.NewVehicle(7)
+    for _, state := range []models.VehicleState{models.Entering, models.Parked} {
+        vehicle.SetState(state)
+        fmt.Println(vehicle.ID, vehicle.GetStateString())
+    }
+    // Output:
+    // 7 entrando
+    // 7 estacionado
+}
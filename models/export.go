@@ -0,0 +1,180 @@
+package models
+
+import (
+    "encoding/csv"
+    "io"
+    "strconv"
+    "time"
+)
+
+const MAX_SPACE_HISTORY = 10000
+
+type SpaceHistoryEntry struct {
+    SpaceID   int
+    VehicleID int
+    EntryTime time.Time
+    ExitTime  time.Time
+}
+
+func (e SpaceHistoryEntry) Duration() time.Duration {
+    return e.ExitTime.Sub(e.EntryTime)
+}
+
+type countingWriter struct {
+    w io.Writer
+    n int64
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+    n, err := cw.w.Write(b)
+    cw.n += int64(n)
+    return n, err
+}
+
+// WriteTo escribe un CSV con una fila por espacio ocupado, lo que permite
+// exportar el estado actual con io.Copy(archivo, lote).
+func (p *ParkingLot) WriteTo(w io.Writer) (int64, error) {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+
+    cw := &countingWriter{w: w}
+    writer := csv.NewWriter(cw)
+    writer.Write([]string{"spaceID", "vehicleID", "entryTime", "expectedExitTime", "parkDuration"})
+
+    for _, space := range p.spaces {
+        vehicle := space.OccupiedBy
+        if vehicle == nil {
+            continue
+        }
+        expectedExit := ""
+        if t := vehicle.GetExpectedExitTime(); !t.IsZero() {
+            expectedExit = t.Format(time.RFC3339)
+        }
+        writer.Write([]string{
+            strconv.Itoa(space.ID),
+            strconv.Itoa(vehicle.ID),
+            vehicle.GetEntryTime().Format(time.RFC3339),
+            expectedExit,
+            vehicle.GetParkingDuration().String(),
+        })
+    }
+
+    writer.Flush()
+    return cw.n, writer.Error()
+}
+
+func (p *ParkingLot) WriteHistoryTo(w io.Writer) (int64, error) {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+
+    cw := &countingWriter{w: w}
+    writer := csv.NewWriter(cw)
+    writer.Write([]string{"spaceID", "vehicleID", "entryTime", "exitTime", "parkDuration"})
+
+    for _, entry := range p.history {
+        writer.Write([]string{
+            strconv.Itoa(entry.SpaceID),
+            strconv.Itoa(entry.VehicleID),
+            entry.EntryTime.Format(time.RFC3339),
+            entry.ExitTime.Format(time.RFC3339),
+            entry.Duration().String(),
+        })
+    }
+
+    writer.Flush()
+    return cw.n, writer.Error()
+}
+
+// WriteGateCrossingsTo escribe un CSV con los cruces de la pluma en orden
+// de adquisición.
+func (p *ParkingLot) WriteGateCrossingsTo(w io.Writer) (int64, error) {
+    cw := &countingWriter{w: w}
+    writer := csv.NewWriter(cw)
+    writer.Write([]string{"seq", "vehicleID", "direction", "acquiredAt", "hold"})
+
+    for _, crossing := range p.gate.Crossings() {
+        direction := "entrada"
+        if crossing.Direction == GateExit {
+            direction = "salida"
+        }
+        writer.Write([]string{
+            strconv.FormatUint(crossing.Seq, 10),
+            strconv.Itoa(crossing.VehicleID),
+            direction,
+            crossing.AcquiredAt.Format(time.RFC3339Nano),
+            crossing.Hold.String(),
+        })
+    }
+
+    writer.Flush()
+    return cw.n, writer.Error()
+}
+
+func (p *ParkingLot) GetSpaceHistory() []SpaceHistoryEntry {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return append([]SpaceHistoryEntry(nil), p.history...)
+}
+
+// GetSpaceTurnover cuenta, por espacio, los vehículos que salieron después
+// de since.
+func (p *ParkingLot) GetSpaceTurnover(since time.Time) []int {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+
+    return p.spaceTurnover(since)
+}
+
+func (p *ParkingLot) spaceTurnover(since time.Time) []int {
+    counts := make([]int, len(p.spaces))
+    for _, entry := range p.history {
+        if entry.SpaceID >= 0 && entry.SpaceID < len(counts) && entry.ExitTime.After(since) {
+            counts[entry.SpaceID]++
+        }
+    }
+    return counts
+}
+
+// GetSpaceTurnoverRates devuelve, por espacio, los vehículos que salieron
+// entre since y now por hora que el espacio estuvo abierto: el tiempo que
+// pasó bloqueado no cuenta. Un espacio que no estuvo abierto da 0.
+func (p *ParkingLot) GetSpaceTurnoverRates(since, now time.Time) []float64 {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+
+    counts := p.spaceTurnover(since)
+    open := make([]time.Duration, len(counts))
+    for i := range open {
+        open[i] = now.Sub(since)
+    }
+    for _, closure := range p.closures {
+        if closure.spaceID < 0 || closure.spaceID >= len(open) {
+            continue
+        }
+        from, to := closure.from, closure.to
+        if to.IsZero() || to.After(now) {
+            to = now
+        }
+        if from.Before(since) {
+            from = since
+        }
+        if to.After(from) {
+            open[closure.spaceID] -= to.Sub(from)
+        }
+    }
+
+    rates := make([]float64, len(counts))
+    for i, count := range counts {
+        if hours := open[i].Hours(); hours > 0 {
+            rates[i] = float64(count) / hours
+        }
+    }
+    return rates
+}
+
+func (p *ParkingLot) recordHistory(entry SpaceHistoryEntry) {
+    p.history = append(p.history, entry)
+    if len(p.history) > MAX_SPACE_HISTORY {
+        p.history = p.history[len(p.history)-MAX_SPACE_HISTORY:]
+    }
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+    "bytes"
+    "encoding/csv"
+    "testing"
+)
+
+func TestWriteToRowsMatchOccupancy(t *testing.T) {
+    tests := []struct {
+        name     string
+        capacity int
+        entered  int
+        exited   int
+    }{
+        {"vacío", 5, 0, 0},
+        {"parcialmente ocupado", 5, 3, 0},
+        {"lleno", 5, 5, 0},
+        {"con salidas", 5, 5, 2},
+        {"todos salieron", 3, 3, 3},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lot := NewParkingLot(tt.capacity, func(int, string) {})
+            var parked []*Vehicle
+            for i := 0; i < tt.entered; i++ {
+                vehicle := NewVehicle(i + 1)
+                if !lot.TryEnter(vehicle) {
+                    t.Fatalf("el vehículo %d no pudo entrar", vehicle.ID)
+                }
+                parked = append(parked, vehicle)
+            }
+            for _, vehicle := range parked[:tt.exited] {
+                lot.Exit(vehicle)
+            }
+
+            writers := []struct {
+                name  string
+                write func(*bytes.Buffer) (int64, error)
+                rows  int
+            }{
+                {"WriteTo", func(buf *bytes.Buffer) (int64, error) { return lot.WriteTo(buf) }, lot.GetOccupancy()},
+                {"WriteHistoryTo", func(buf *bytes.Buffer) (int64, error) { return lot.WriteHistoryTo(buf) }, tt.exited},
+            }
+            for _, writer := range writers {
+                var buf bytes.Buffer
+                n, err := writer.write(&buf)
+                if err != nil {
+                    t.Fatalf("%s: %v", writer.name, err)
+                }
+                if n != int64(buf.Len()) {
+                    t.Errorf("%s devolvió %d bytes, want %d", writer.name, n, buf.Len())
+                }
+                rows, err := csv.NewReader(&buf).ReadAll()
+                if err != nil {
+                    t.Fatalf("%s no escribió un CSV válido: %v", writer.name, err)
+                }
+                if len(rows)-1 != writer.rows {
+                    t.Errorf("%s escribió %d filas, want %d", writer.name, len(rows)-1, writer.rows)
+                }
+            }
+        })
+    }
+}
@@ -0,0 +1,276 @@
+package models
+
+import (
+    "context"
+    "sync"
+    "time"
+)
+
+type GateDirection int
+
+const (
+    GateEntry GateDirection = iota
+    GateExit
+)
+
+// MAX_GATE_CROSSINGS limita cuántos cruces conserva la bitácora de la pluma.
+const MAX_GATE_CROSSINGS = 1000
+
+type GatePolicy int
+
+const (
+    GatePolicyAlternate GatePolicy = iota
+    GatePolicyFIFO
+    GatePolicyExitsFirst
+)
+
+// GateCrossing es un cruce de la pluma. Seq se asigna al ceder la pluma,
+// dentro de la sección crítica, así que refleja el orden real de adquisición.
+type GateCrossing struct {
+    Seq        uint64
+    VehicleID  int
+    Direction  GateDirection
+    AcquiredAt time.Time
+    Hold       time.Duration
+}
+
+type gateRequest struct {
+    vehicleID   int
+    direction   GateDirection
+    requestedAt time.Time
+    ready       chan struct{}
+}
+
+// Gate reemplaza al semáforo de peso 1 de la pluma: las solicitudes se
+// encolan explícitamente y la política decide a quién se le cede la pluma
+// al liberarse, para que un flujo constante de entradas no deje esperando
+// a las salidas (que son las que liberan espacios).
+type Gate struct {
+    policy        GatePolicy
+    busy          bool
+    lastDirection GateDirection
+    waiting       []*gateRequest
+    maxWait       [2]time.Duration
+    holder        GateCrossing
+    nextSeq       uint64
+    crossings     []GateCrossing
+    outOfService  bool
+    failedAt      time.Time
+    downtime      time.Duration
+    mu            sync.Mutex
+}
+
+func NewGate(policy GatePolicy) *Gate {
+    return &Gate{policy: policy}
+}
+
+func (g *Gate) SetPolicy(policy GatePolicy) {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    g.policy = policy
+}
+
+func (g *Gate) GetPolicy() GatePolicy {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    return g.policy
+}
+
+func (g *Gate) Acquire(ctx context.Context, direction GateDirection, vehicleID int) error {
+    g.mu.Lock()
+    if !g.busy && !g.outOfService && len(g.waiting) == 0 {
+        g.busy = true
+        g.grant(direction, vehicleID)
+        g.mu.Unlock()
+        return nil
+    }
+
+    req := &gateRequest{
+        vehicleID:   vehicleID,
+        direction:   direction,
+        requestedAt: time.Now(),
+        ready:       make(chan struct{}),
+    }
+    g.waiting = append(g.waiting, req)
+    g.mu.Unlock()
+
+    select {
+    case <-req.ready:
+        g.recordWait(req)
+        return nil
+    case <-ctx.Done():
+        g.mu.Lock()
+        select {
+        case <-req.ready:
+            g.mu.Unlock()
+            g.Release()
+            return ctx.Err()
+        default:
+        }
+        for i, waiting := range g.waiting {
+            if waiting == req {
+                g.waiting = append(g.waiting[:i], g.waiting[i+1:]...)
+                break
+            }
+        }
+        g.mu.Unlock()
+        return ctx.Err()
+    }
+}
+
+func (g *Gate) Release() {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+
+    g.recordCrossing()
+    g.busy = false
+    g.grantNext()
+}
+
+// grantNext le cede la pluma libre a la siguiente solicitud según la
+// política. Debe llamarse con mu tomado.
+func (g *Gate) grantNext() {
+    if g.busy || g.outOfService || len(g.waiting) == 0 {
+        return
+    }
+
+    g.busy = true
+    i := g.nextRequest()
+    req := g.waiting[i]
+    g.waiting = append(g.waiting[:i], g.waiting[i+1:]...)
+    g.grant(req.direction, req.vehicleID)
+    close(req.ready)
+}
+
+// grant y recordCrossing deben llamarse con mu tomado.
+func (g *Gate) grant(direction GateDirection, vehicleID int) {
+    g.lastDirection = direction
+    g.nextSeq++
+    g.holder = GateCrossing{
+        Seq:        g.nextSeq,
+        VehicleID:  vehicleID,
+        Direction:  direction,
+        AcquiredAt: time.Now(),
+    }
+}
+
+func (g *Gate) recordCrossing() {
+    crossing := g.holder
+    crossing.Hold = time.Since(crossing.AcquiredAt)
+    g.crossings = append(g.crossings, crossing)
+    if len(g.crossings) > MAX_GATE_CROSSINGS {
+        g.crossings = g.crossings[len(g.crossings)-MAX_GATE_CROSSINGS:]
+    }
+}
+
+// Fail deja la pluma fuera de servicio: quien la cruza termina, pero nadie
+// más la adquiere hasta Repair. Devuelve falso si ya estaba descompuesta.
+func (g *Gate) Fail() bool {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    if g.outOfService {
+        return false
+    }
+    g.outOfService = true
+    g.failedAt = time.Now()
+    return true
+}
+
+// Repair vuelve a poner la pluma en servicio y atiende a quien esperaba.
+func (g *Gate) Repair() bool {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    if !g.outOfService {
+        return false
+    }
+    g.outOfService = false
+    g.downtime += time.Since(g.failedAt)
+    g.grantNext()
+    return true
+}
+
+func (g *Gate) OutOfService() bool {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    return g.outOfService
+}
+
+// Downtime suma el tiempo fuera de servicio, incluida la falla en curso.
+func (g *Gate) Downtime() time.Duration {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    if g.outOfService {
+        return g.downtime + time.Since(g.failedAt)
+    }
+    return g.downtime
+}
+
+// ResetHistory borra los cruces, las esperas máximas y el tiempo fuera de
+// servicio acumulados. Una falla en curso se cuenta desde ahora.
+func (g *Gate) ResetHistory() {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    g.crossings = nil
+    g.nextSeq = 0
+    g.maxWait = [2]time.Duration{}
+    g.downtime = 0
+    if g.outOfService {
+        g.failedAt = time.Now()
+    }
+}
+
+// Crossings devuelve los últimos cruces completados, en orden de adquisición.
+func (g *Gate) Crossings() []GateCrossing {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    return append([]GateCrossing(nil), g.crossings...)
+}
+
+func (g *Gate) nextRequest() int {
+    switch g.policy {
+    case GatePolicyExitsFirst:
+        return g.firstWaiting(GateExit)
+    case GatePolicyAlternate:
+        if g.lastDirection == GateEntry {
+            return g.firstWaiting(GateExit)
+        }
+        return g.firstWaiting(GateEntry)
+    }
+    return 0
+}
+
+func (g *Gate) firstWaiting(direction GateDirection) int {
+    for i, req := range g.waiting {
+        if req.direction == direction {
+            return i
+        }
+    }
+    return 0
+}
+
+func (g *Gate) recordWait(req *gateRequest) {
+    wait := time.Since(req.requestedAt)
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    if wait > g.maxWait[req.direction] {
+        g.maxWait[req.direction] = wait
+    }
+}
+
+func (g *Gate) MaxWait(direction GateDirection) time.Duration {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    return g.maxWait[direction]
+}
+
+func (g *Gate) Waiting(direction GateDirection) int {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    count := 0
+    for _, req := range g.waiting {
+        if req.direction == direction {
+            count++
+        }
+    }
+    return count
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+    "context"
+    "reflect"
+    "testing"
+    "time"
+)
+
+// waitForGateQueue espera hasta que haya want solicitudes de direction en la
+// cola de la pluma.
+func waitForGateQueue(t *testing.T, gate *Gate, direction GateDirection, want int) {
+    deadline := time.Now().Add(time.Second)
+    for gate.Waiting(direction) < want {
+        if time.Now().After(deadline) {
+            t.Fatalf("solicitudes en cola = %d, want %d", gate.Waiting(direction), want)
+        }
+        time.Sleep(time.Millisecond)
+    }
+}
+
+func TestGatePolicyDoesNotStarveExits(t *testing.T) {
+    // Mientras una entrada tiene la pluma llegan cinco entradas y, al final,
+    // las salidas: el caso en que un flujo de entradas deja esperando a
+    // quienes liberarían espacios.
+    tests := []struct {
+        name     string
+        policy   GatePolicy
+        requests []GateDirection
+        want     []int
+    }{
+        {"alternar atiende la salida primero", GatePolicyAlternate,
+            []GateDirection{GateEntry, GateEntry, GateEntry, GateEntry, GateEntry, GateExit},
+            []int{6, 1, 2, 3, 4, 5}},
+        {"alternar intercala dos salidas", GatePolicyAlternate,
+            []GateDirection{GateEntry, GateEntry, GateEntry, GateEntry, GateExit, GateExit},
+            []int{5, 1, 6, 2, 3, 4}},
+        {"salidas primero", GatePolicyExitsFirst,
+            []GateDirection{GateEntry, GateEntry, GateEntry, GateEntry, GateExit, GateExit},
+            []int{5, 6, 1, 2, 3, 4}},
+        {"FIFO deja la salida al final", GatePolicyFIFO,
+            []GateDirection{GateEntry, GateEntry, GateEntry, GateEntry, GateEntry, GateExit},
+            []int{1, 2, 3, 4, 5, 6}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            gate := NewGate(tt.policy)
+            if err := gate.Acquire(context.Background(), GateEntry, 0); err != nil {
+                t.Fatal(err)
+            }
+
+            granted := make(chan int)
+            queued := map[GateDirection]int{}
+            for i, direction := range tt.requests {
+                vehicleID := i + 1
+                go func(direction GateDirection) {
+                    if err := gate.Acquire(context.Background(), direction, vehicleID); err == nil {
+                        granted <- vehicleID
+                    }
+                }(direction)
+                queued[direction]++
+                waitForGateQueue(t, gate, direction, queued[direction])
+            }
+
+            var order []int
+            gate.Release()
+            for range tt.requests {
+                select {
+                case vehicleID := <-granted:
+                    order = append(order, vehicleID)
+                    gate.Release()
+                case <-time.After(time.Second):
+                    t.Fatalf("la pluma dejó de atender después de %v", order)
+                }
+            }
+            if !reflect.DeepEqual(order, tt.want) {
+                t.Errorf("orden = %v, want %v", order, tt.want)
+            }
+            if exitWait, entryWait := gate.MaxWait(GateExit), gate.MaxWait(GateEntry); exitWait <= 0 || entryWait <= 0 {
+                t.Errorf("esperas máximas = %v (salida), %v (entrada), want positivas", exitWait, entryWait)
+            }
+        })
+    }
+}
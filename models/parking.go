@@ -1,120 +1,240 @@
-package models
-
-import (
-    "context"
-    "fmt"
-    "sync"
-    "golang.org/x/sync/semaphore"
-)
-
-type ParkingLot struct {
-    Capacity       int64                      
-    spaceSem       *semaphore.Weighted        
-    gateSem        *semaphore.Weighted      
-    vehicles       map[int]*Vehicle           
-    waitingQueue   []*Vehicle               
-    occupiedSpaces int64                    
-    UpdateUI       func(spaces int, message string) 
-    ctx            context.Context            
-    mu             sync.Mutex                 
-}
-
-func NewParkingLot(capacity int, updateUI func(spaces int, message string)) *ParkingLot {
-    return &ParkingLot{
-        Capacity:       int64(capacity),                         
-        spaceSem:       semaphore.NewWeighted(int64(capacity)),   
-        gateSem:        semaphore.NewWeighted(1),                 
-        vehicles:       make(map[int]*Vehicle),                   
-        waitingQueue:   []*Vehicle{},                               
-        occupiedSpaces: 0,                                          
-        UpdateUI:       updateUI,                                   
-        ctx:            context.Background(),                   
-    }
-}
-
-func (p *ParkingLot) TryEnter(vehicle *Vehicle) bool {
-    p.mu.Lock()        
-    defer p.mu.Unlock()
-
-    if p.occupiedSpaces >= p.Capacity {
-        p.waitingQueue = append(p.waitingQueue, vehicle)
-        return false
-    }
-
-    if !p.spaceSem.TryAcquire(1) {
-        p.waitingQueue = append(p.waitingQueue, vehicle)
-        return false
-    }
-
-    err := p.gateSem.Acquire(p.ctx, 1)
-    if err != nil {
-        p.spaceSem.Release(1) 
-        return false
-    }
-
-    vehicle.SetState(Entering) 
-    p.vehicles[vehicle.ID] = vehicle 
-    p.occupiedSpaces++ 
-    
-    spaces := p.GetAvailableSpaces()
-    message := fmt.Sprintf("%s ha entrado. Espacios disponibles: %d", vehicle, spaces)
-    p.UpdateUI(int(spaces), message) 
-    p.gateSem.Release(1)
-    vehicle.SetState(Parked) 
-
-    return true
-}
-
-func (p *ParkingLot) Exit(vehicle *Vehicle) {
-    p.mu.Lock()        
-    defer p.mu.Unlock() 
-
-    if _, exists := p.vehicles[vehicle.ID]; !exists {
-        return 
-    }
-
-    err := p.gateSem.Acquire(p.ctx, 1)
-    if err != nil {
-        return 
-    }
-
-    vehicle.SetState(Exiting) 
-    delete(p.vehicles, vehicle.ID) 
-    p.occupiedSpaces-- 
-    
-    availableSpaces := p.GetAvailableSpaces()
-    message := fmt.Sprintf("%s ha salido. Espacios disponibles: %d", vehicle, availableSpaces)
-    p.UpdateUI(int(availableSpaces), message)
-
-    p.spaceSem.Release(1)
-
-    if len(p.waitingQueue) > 0 {
-        nextVehicle := p.waitingQueue[0]
-        p.waitingQueue = p.waitingQueue[1:] 
-        
-        p.mu.Unlock()
-        go p.TryEnter(nextVehicle)
-        p.mu.Lock() 
-    }
-
-    p.gateSem.Release(1)
-}
-
-func (p *ParkingLot) GetAvailableSpaces() int64 {
-    return p.Capacity - p.occupiedSpaces 
-}
-
-func (p *ParkingLot) GetOccupancy() int {
-    p.mu.Lock()       
-    defer p.mu.Unlock() 
-    return int(p.occupiedSpaces) 
-}
-
-func (p *ParkingLot) GetWaitingVehicles() []*Vehicle {
-    p.mu.Lock()        
-    defer p.mu.Unlock() 
-    
-    queueCopy := make([]*Vehicle, len(p.waitingQueue))
-    copy(queueCopy, p.waitingQueue)
-    return queueCopy
-}
+package models
+
+import (
+    "context"
+    "fmt"
+    "sync"
+    "time"
+    "golang.org/x/sync/semaphore"
+)
+
+type ParkingLot struct {
+    Capacity       int64                      
+    spaceSem       *semaphore.Weighted        
+    gate           *Gate
+    vehicles       map[int]*Vehicle           
+    waitingQueue   []*Vehicle               
+    externalQueue  bool
+    occupiedSpaces int64                    
+    UpdateUI       func(spaces int, message string) 
+    ctx            context.Context            
+    mu             sync.RWMutex                 
+    spaces         []*ParkingSpace
+    vehicleSpaces  map[int]int
+    gridColumns    int
+    history        []SpaceHistoryEntry
+    onLabelChange  func(spaceID int, label string)
+    doubleParking  bool
+    doublePenalty  time.Duration
+    onDoublePark   func(spaceID int, blocked bool)
+    semHeld        int64
+    entryClosed    bool
+    entries        sync.WaitGroup
+    alertThreshold float64
+    alertFired     bool
+    onAlert        func(rate float64)
+    claims         map[int]spaceClaim
+    conflicts      []SpaceConflict
+    closures       []spaceClosure
+}
+
+func NewParkingLot(capacity int, updateUI func(spaces int, message string)) *ParkingLot {
+    return &ParkingLot{
+        Capacity:       int64(capacity),                         
+        spaceSem:       semaphore.NewWeighted(int64(capacity)),   
+        gate:           NewGate(GatePolicyAlternate),
+        vehicles:       make(map[int]*Vehicle),                   
+        waitingQueue:   []*Vehicle{},                               
+        occupiedSpaces: 0,                                          
+        UpdateUI:       updateUI,                                   
+        ctx:            context.Background(),                   
+        spaces:         newParkingSpaces(capacity),
+        vehicleSpaces:  make(map[int]int),
+        gridColumns:    DefaultGridColumns,
+        claims:         make(map[int]spaceClaim),
+    }
+}
+
+func (p *ParkingLot) TryEnter(vehicle *Vehicle) bool {
+    p.mu.Lock()        
+
+    if p.entryClosed {
+        p.mu.Unlock()
+        return false
+    }
+    p.entries.Add(1)
+    defer p.entries.Done()
+
+    if p.occupiedSpaces >= p.Capacity {
+        p.addWaiting(vehicle)
+        p.mu.Unlock()
+        return false
+    }
+
+    spaceID, found := p.findAvailableSpace(vehicle)
+    if found && !p.claimSpace(spaceID, vehicle) {
+        found = false
+    }
+    if !found {
+        p.addWaiting(vehicle)
+        p.mu.Unlock()
+        return false
+    }
+
+    if !p.acquireSpace() {
+        p.addWaiting(vehicle)
+        p.mu.Unlock()
+        return false
+    }
+
+    // El espacio queda apartado mientras el vehículo espera la pluma, que
+    // se solicita sin retener mu para no bloquear las salidas.
+    p.spaces[spaceID].OccupiedBy = vehicle
+    p.vehicleSpaces[vehicle.ID] = spaceID
+    p.occupiedSpaces++ 
+    alert := p.checkAlert()
+    p.mu.Unlock()
+    if alert != nil {
+        alert()
+    }
+
+    err := p.gate.Acquire(p.ctx, GateEntry, vehicle.ID)
+    if err != nil {
+        p.mu.Lock()
+        p.spaces[spaceID].OccupiedBy = nil
+        delete(p.vehicleSpaces, vehicle.ID)
+        p.occupiedSpaces--
+        p.releaseSpace()
+        p.releaseSpaceClaim(spaceID)
+        p.mu.Unlock()
+        return false
+    }
+
+    p.mu.Lock()
+    vehicle.SetState(Entering) 
+    p.vehicles[vehicle.ID] = vehicle 
+    
+    spaces := p.availableSpaces()
+    message := fmt.Sprintf("%s ha entrado. Espacios disponibles: %d", vehicle, spaces)
+    p.UpdateUI(int(spaces), message) 
+    p.mu.Unlock()
+
+    p.gate.Release()
+    vehicle.SetState(Parked) 
+    p.maybeDoublePark(vehicle, spaceID)
+
+    return true
+}
+
+func (p *ParkingLot) Exit(vehicle *Vehicle) {
+    p.mu.RLock()        
+    _, exists := p.vehicles[vehicle.ID]
+    p.mu.RUnlock() 
+
+    if !exists {
+        return 
+    }
+
+    err := p.gate.Acquire(p.ctx, GateExit, vehicle.ID)
+    if err != nil {
+        return 
+    }
+    defer p.gate.Release()
+
+    p.mu.Lock()        
+    defer p.mu.Unlock() 
+
+    if _, exists := p.vehicles[vehicle.ID]; !exists {
+        return 
+    }
+
+    vehicle.SetState(Exiting) 
+    delete(p.vehicles, vehicle.ID) 
+    if spaceID, ok := p.vehicleSpaces[vehicle.ID]; ok {
+        p.spaces[spaceID].OccupiedBy = nil
+        delete(p.vehicleSpaces, vehicle.ID)
+        p.releaseSpaceClaim(spaceID)
+        p.recordHistory(SpaceHistoryEntry{
+            SpaceID:   spaceID,
+            VehicleID: vehicle.ID,
+            EntryTime: vehicle.GetEntryTime(),
+            ExitTime:  vehicle.GetExitTime(),
+        })
+    }
+    p.occupiedSpaces-- 
+    
+    availableSpaces := p.availableSpaces()
+    message := fmt.Sprintf("%s ha salido. Espacios disponibles: %d", vehicle, availableSpaces)
+    p.UpdateUI(int(availableSpaces), message)
+
+    p.releaseSpace()
+
+    if len(p.waitingQueue) > 0 {
+        nextVehicle := p.waitingQueue[0]
+        p.waitingQueue = p.waitingQueue[1:]
+        go p.TryEnter(nextVehicle)
+    }
+}
+
+func (p *ParkingLot) SetGatePolicy(policy GatePolicy) {
+    p.gate.SetPolicy(policy)
+}
+
+func (p *ParkingLot) GetGateMaxWait(direction GateDirection) time.Duration {
+    return p.gate.MaxWait(direction)
+}
+
+func (p *ParkingLot) GetGateCrossings() []GateCrossing {
+    return p.gate.Crossings()
+}
+
+func (p *ParkingLot) FailGate() bool {
+    return p.gate.Fail()
+}
+
+func (p *ParkingLot) RepairGate() bool {
+    return p.gate.Repair()
+}
+
+func (p *ParkingLot) IsGateOutOfService() bool {
+    return p.gate.OutOfService()
+}
+
+func (p *ParkingLot) GetGateDowntime() time.Duration {
+    return p.gate.Downtime()
+}
+
+// ResetRunHistory borra lo que registró la corrida anterior: los cruces y el
+// tiempo fuera de servicio de la pluma y los conflictos de espacios.
+func (p *ParkingLot) ResetRunHistory() {
+    p.gate.ResetHistory()
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.conflicts = nil
+}
+
+func (p *ParkingLot) GetAvailableSpaces() int64 {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return p.availableSpaces()
+}
+
+// availableSpaces es GetAvailableSpaces para quien ya tiene mu tomado.
+func (p *ParkingLot) availableSpaces() int64 {
+    return p.Capacity - p.occupiedSpaces
+}
+
+func (p *ParkingLot) GetOccupancy() int {
+    p.mu.Lock()       
+    defer p.mu.Unlock() 
+    return int(p.occupiedSpaces) 
+}
+
+func (p *ParkingLot) GetWaitingVehicles() []*Vehicle {
+    p.mu.Lock()        
+    defer p.mu.Unlock() 
+    
+    queueCopy := make([]*Vehicle, len(p.waitingQueue))
+    copy(queueCopy, p.waitingQueue)
+    return queueCopy
+}
@@ -0,0 +1,31 @@
+package models
+
+import "fmt"
+
+// Preload deja estacionados a los vehículos sin pasar por la pluma, como si
+// ya estuvieran adentro al abrir. Devuelve los que cupieron; el resto se
+// descarta. Avisa a la interfaz una sola vez al terminar.
+func (p *ParkingLot) Preload(vehicles []*Vehicle) []*Vehicle {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    parked := make([]*Vehicle, 0, len(vehicles))
+    for _, vehicle := range vehicles {
+        spaceID, found := p.findAvailableSpace(vehicle)
+        if !found || !p.acquireSpace() {
+            break
+        }
+        p.spaces[spaceID].OccupiedBy = vehicle
+        p.vehicleSpaces[vehicle.ID] = spaceID
+        p.vehicles[vehicle.ID] = vehicle
+        p.occupiedSpaces++
+        vehicle.SetState(Entering)
+        vehicle.SetState(Parked)
+        parked = append(parked, vehicle)
+    }
+    if len(parked) > 0 {
+        spaces := p.availableSpaces()
+        p.UpdateUI(int(spaces), fmt.Sprintf("%d vehículos ya estaban estacionados. Espacios disponibles: %d", len(parked), spaces))
+    }
+    return parked
+}
@@ -0,0 +1,375 @@
+package models
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "time"
+)
+
+const DefaultGridColumns = 5
+
+var ErrInvalidSpace = errors.New("espacio fuera de rango")
+
+type ParkingSpace struct {
+    ID         int
+    Label      string
+    OccupiedBy *Vehicle
+    Blocked    bool
+}
+
+func newParkingSpaces(capacity int) []*ParkingSpace {
+    spaces := make([]*ParkingSpace, capacity)
+    for i := range spaces {
+        spaces[i] = &ParkingSpace{
+            ID:    i,
+            Label: fmt.Sprintf("P%d", i+1),
+        }
+    }
+    return spaces
+}
+
+func (ps *ParkingSpace) IsFree() bool {
+    return ps.OccupiedBy == nil && !ps.Blocked
+}
+
+func (p *ParkingLot) SetGridColumns(columns int) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    if columns > 0 {
+        p.gridColumns = columns
+    }
+}
+
+func (p *ParkingLot) GetGridColumns() int {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return p.gridColumns
+}
+
+func (p *ParkingLot) GetAdjacentSpaces(spaceID int) []int {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return p.adjacentSpaces(spaceID)
+}
+
+func (p *ParkingLot) adjacentSpaces(spaceID int) []int {
+    if spaceID < 0 || spaceID >= len(p.spaces) {
+        return nil
+    }
+
+    column := spaceID % p.gridColumns
+    adjacent := []int{}
+    if column > 0 {
+        adjacent = append(adjacent, spaceID-1)
+    }
+    if column < p.gridColumns-1 && spaceID+1 < len(p.spaces) {
+        adjacent = append(adjacent, spaceID+1)
+    }
+    if spaceID-p.gridColumns >= 0 {
+        adjacent = append(adjacent, spaceID-p.gridColumns)
+    }
+    if spaceID+p.gridColumns < len(p.spaces) {
+        adjacent = append(adjacent, spaceID+p.gridColumns)
+    }
+    return adjacent
+}
+
+func (p *ParkingLot) GetContiguousFreeSpaces(n int) ([]int, bool) {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return p.contiguousFreeSpaces(n)
+}
+
+// freeBlocks devuelve las corridas de espacios libres de cada fila como
+// pares (primer espacio, largo); una corrida nunca continúa en la fila
+// siguiente.
+func (p *ParkingLot) freeBlocks() [][2]int {
+    blocks := [][2]int{}
+    start, length := -1, 0
+    flush := func() {
+        if length > 0 {
+            blocks = append(blocks, [2]int{start, length})
+        }
+        start, length = -1, 0
+    }
+    for _, space := range p.spaces {
+        if space.ID%p.gridColumns == 0 {
+            flush()
+        }
+        if !space.IsFree() {
+            flush()
+            continue
+        }
+        if length == 0 {
+            start = space.ID
+        }
+        length++
+    }
+    flush()
+    return blocks
+}
+
+// contiguousFreeSpaces busca n espacios libres seguidos dentro de una misma
+// fila. Usa la corrida más corta en la que caben, así las corridas largas
+// quedan disponibles para quien necesite varios espacios.
+func (p *ParkingLot) contiguousFreeSpaces(n int) ([]int, bool) {
+    if n <= 0 {
+        return nil, false
+    }
+
+    blocks := p.freeBlocks()
+    best := -1
+    for i, block := range blocks {
+        if block[1] < n {
+            continue
+        }
+        if best < 0 || block[1] < blocks[best][1] {
+            best = i
+        }
+    }
+    if best < 0 {
+        return nil, false
+    }
+
+    start := blocks[best][0]
+    run := make([]int, n)
+    for i := range run {
+        run[i] = start + i
+    }
+    return run, true
+}
+
+// GetLargestFreeBlock devuelve el largo de la corrida de espacios libres más
+// larga dentro de una fila, como medida de fragmentación del estacionamiento.
+func (p *ParkingLot) GetLargestFreeBlock() int {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+
+    largest := 0
+    for _, block := range p.freeBlocks() {
+        if block[1] > largest {
+            largest = block[1]
+        }
+    }
+    return largest
+}
+
+func (p *ParkingLot) FindAvailableSpaceForVehicle(vehicle *Vehicle) (int, bool) {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    return p.findAvailableSpace(vehicle)
+}
+
+func (p *ParkingLot) findAvailableSpace(vehicle *Vehicle) (int, bool) {
+    run, ok := p.contiguousFreeSpaces(1)
+    if !ok {
+        return -1, false
+    }
+    return run[0], true
+}
+
+func (p *ParkingLot) GetSpaces() []ParkingSpace {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+
+    spaces := make([]ParkingSpace, len(p.spaces))
+    for i, space := range p.spaces {
+        spaces[i] = *space
+    }
+    return spaces
+}
+
+func (p *ParkingLot) GetVehicleSpace(vehicleID int) (int, bool) {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    spaceID, ok := p.vehicleSpaces[vehicleID]
+    return spaceID, ok
+}
+
+func (p *ParkingLot) SetSpaceLabel(spaceID int, label string) error {
+    p.mu.Lock()
+    if spaceID < 0 || spaceID >= len(p.spaces) {
+        p.mu.Unlock()
+        return ErrInvalidSpace
+    }
+    p.spaces[spaceID].Label = label
+    callback := p.onLabelChange
+    p.mu.Unlock()
+
+    if callback != nil {
+        callback(spaceID, label)
+    }
+    return nil
+}
+
+func (p *ParkingLot) GetSpaceLabel(spaceID int) (string, error) {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+    if spaceID < 0 || spaceID >= len(p.spaces) {
+        return "", ErrInvalidSpace
+    }
+    return p.spaces[spaceID].Label, nil
+}
+
+func (p *ParkingLot) SetSpaceLabelCallback(callback func(spaceID int, label string)) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.onLabelChange = callback
+}
+
+// ResidenceBucket cuenta los espacios ocupados desde hace entre Min y Max.
+type ResidenceBucket struct {
+    Min   time.Duration
+    Max   time.Duration
+    Count int
+}
+
+// AgeHistogramBucket es el mismo bucket, con el nombre que usa
+// GetAgeHistogram.
+type AgeHistogramBucket = ResidenceBucket
+
+// GetSpaceAgeDistribution devuelve, por cada espacio ocupado, cuánto tiempo
+// lleva estacionado su vehículo. Los espacios apartados por un vehículo que
+// todavía espera la pluma no se incluyen.
+func (p *ParkingLot) GetSpaceAgeDistribution() map[int]time.Duration {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+
+    ages := make(map[int]time.Duration)
+    now := time.Now()
+    for _, space := range p.spaces {
+        if space.OccupiedBy == nil {
+            continue
+        }
+        entry := space.OccupiedBy.GetEntryTime()
+        if entry.IsZero() {
+            continue
+        }
+        ages[space.ID] = now.Sub(entry)
+    }
+    return ages
+}
+
+// GetAgeHistogram reparte las antigüedades de GetSpaceAgeDistribution en
+// buckets de igual ancho entre 0 y la mayor antigüedad actual.
+func (p *ParkingLot) GetAgeHistogram(buckets int) []AgeHistogramBucket {
+    if buckets <= 0 {
+        return nil
+    }
+    ages := p.GetSpaceAgeDistribution()
+
+    var oldest time.Duration
+    for _, age := range ages {
+        if age > oldest {
+            oldest = age
+        }
+    }
+    width := oldest / time.Duration(buckets)
+    if width <= 0 {
+        width = time.Second
+    }
+    return ageHistogram(ages, buckets, width)
+}
+
+// GetVehicleResidenceHistogram reparte los vehículos estacionados en buckets
+// de igual ancho entre 0 y maxDuration. Los que llevan más de maxDuration
+// cuentan en el último bucket.
+func (p *ParkingLot) GetVehicleResidenceHistogram(buckets int, maxDuration time.Duration) []ResidenceBucket {
+    if buckets <= 0 || maxDuration <= 0 {
+        return nil
+    }
+    width := maxDuration / time.Duration(buckets)
+    if width <= 0 {
+        width = 1
+    }
+    histogram := ageHistogram(p.GetSpaceAgeDistribution(), buckets, width)
+    histogram[buckets-1].Max = maxDuration
+    return histogram
+}
+
+// ageHistogram cuenta las antigüedades en buckets de ancho width desde 0; las
+// que pasan del último bucket cuentan en él.
+func ageHistogram(ages map[int]time.Duration, buckets int, width time.Duration) []ResidenceBucket {
+    histogram := make([]ResidenceBucket, buckets)
+    for i := range histogram {
+        histogram[i].Min = time.Duration(i) * width
+        histogram[i].Max = time.Duration(i+1) * width
+    }
+    for _, age := range ages {
+        index := int(age / width)
+        if index >= buckets {
+            index = buckets - 1
+        }
+        histogram[index].Count++
+    }
+    return histogram
+}
+
+// spaceBusyTimes suma, por espacio, el tiempo ocupado según el historial, y
+// devuelve también desde cuándo hay registros.
+func (p *ParkingLot) spaceBusyTimes() ([]time.Duration, time.Time) {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+
+    busy := make([]time.Duration, len(p.spaces))
+    var since time.Time
+    for _, entry := range p.history {
+        if entry.SpaceID >= 0 && entry.SpaceID < len(busy) {
+            busy[entry.SpaceID] += entry.Duration()
+        }
+        if since.IsZero() || entry.EntryTime.Before(since) {
+            since = entry.EntryTime
+        }
+    }
+    return busy, since
+}
+
+// GetEntropyScore mide qué tan pareja es la utilización de los espacios: la
+// entropía de Shannon del reparto del tiempo ocupado, normalizada entre 0
+// (todo en un espacio) y 1 (todos por igual). Sin historial devuelve 0.
+func (p *ParkingLot) GetEntropyScore() float64 {
+    busy, _ := p.spaceBusyTimes()
+    var total time.Duration
+    for _, d := range busy {
+        total += d
+    }
+    if total <= 0 {
+        return 0
+    }
+    if len(busy) < 2 {
+        return 1
+    }
+
+    entropy := 0.0
+    for _, d := range busy {
+        if d <= 0 {
+            continue
+        }
+        share := float64(d) / float64(total)
+        entropy -= share * math.Log2(share)
+    }
+    return entropy / math.Log2(float64(len(busy)))
+}
+
+// GetUnderutilizedSpaces devuelve los espacios que estuvieron ocupados menos
+// de threshold (una fracción) del tiempo transcurrido desde el primer
+// registro del historial.
+func (p *ParkingLot) GetUnderutilizedSpaces(threshold float64) []int {
+    busy, since := p.spaceBusyTimes()
+    if since.IsZero() {
+        return nil
+    }
+    elapsed := time.Since(since)
+    if elapsed <= 0 {
+        return nil
+    }
+
+    var spaces []int
+    for id, d := range busy {
+        if float64(d)/float64(elapsed) < threshold {
+            spaces = append(spaces, id)
+        }
+    }
+    return spaces
+}
@@ -0,0 +1,251 @@
+package models
+
+import (
+    "math"
+    "reflect"
+    "testing"
+    "time"
+)
+
+// parkedFor deja un vehículo en cada espacio, estacionado desde hace ages[i].
+func parkedFor(ages ...time.Duration) *ParkingLot {
+    lot := NewParkingLot(len(ages), func(int, string) {})
+    now := time.Now()
+    for i, age := range ages {
+        vehicle := NewVehicle(i + 1)
+        vehicle.EntryTime = now.Add(-age)
+        lot.spaces[i].OccupiedBy = vehicle
+    }
+    return lot
+}
+
+func TestGetVehicleResidenceHistogram(t *testing.T) {
+    minute := time.Minute
+    tests := []struct {
+        name        string
+        ages        []time.Duration
+        buckets     int
+        maxDuration time.Duration
+        wantMax     []time.Duration
+        wantCount   []int
+    }{
+        {"un bucket por rango", []time.Duration{5 * time.Second, 90 * time.Second, 150 * time.Second}, 3, 3 * minute,
+            []time.Duration{minute, 2 * minute, 3 * minute}, []int{1, 1, 1}},
+        {"los que pasan de maxDuration van al último", []time.Duration{30 * time.Second, 10 * minute, time.Hour}, 2, 2 * minute,
+            []time.Duration{minute, 2 * minute}, []int{1, 2}},
+        {"sin vehículos", nil, 4, 4 * minute,
+            []time.Duration{minute, 2 * minute, 3 * minute, 4 * minute}, []int{0, 0, 0, 0}},
+        {"el último bucket termina en maxDuration", []time.Duration{time.Second}, 3, 10 * time.Second,
+            []time.Duration{10 * time.Second / 3, 2 * (10 * time.Second / 3), 10 * time.Second}, []int{1, 0, 0}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            histogram := parkedFor(tt.ages...).GetVehicleResidenceHistogram(tt.buckets, tt.maxDuration)
+            if len(histogram) != tt.buckets {
+                t.Fatalf("len = %d, want %d", len(histogram), tt.buckets)
+            }
+            var previous time.Duration
+            for i, bucket := range histogram {
+                if bucket.Min != previous {
+                    t.Errorf("bucket %d: Min = %v, want %v", i, bucket.Min, previous)
+                }
+                if bucket.Max != tt.wantMax[i] {
+                    t.Errorf("bucket %d: Max = %v, want %v", i, bucket.Max, tt.wantMax[i])
+                }
+                if bucket.Count != tt.wantCount[i] {
+                    t.Errorf("bucket %d: Count = %d, want %d", i, bucket.Count, tt.wantCount[i])
+                }
+                previous = bucket.Max
+            }
+        })
+    }
+}
+
+func TestResidenceHistogramRejectsInvalidRanges(t *testing.T) {
+    lot := parkedFor(time.Second)
+    tests := []struct {
+        name        string
+        buckets     int
+        maxDuration time.Duration
+    }{
+        {"sin buckets", 0, time.Minute},
+        {"buckets negativos", -1, time.Minute},
+        {"sin rango", 3, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if histogram := lot.GetVehicleResidenceHistogram(tt.buckets, tt.maxDuration); histogram != nil {
+                t.Errorf("histograma = %v, want nil", histogram)
+            }
+        })
+    }
+}
+
+func TestGetAgeHistogramSpansOldestAge(t *testing.T) {
+    histogram := parkedFor(10*time.Second, 20*time.Second, 40*time.Second).GetAgeHistogram(4)
+    if len(histogram) != 4 {
+        t.Fatalf("len = %d, want 4", len(histogram))
+    }
+    total := 0
+    for _, bucket := range histogram {
+        total += bucket.Count
+    }
+    if total != 3 {
+        t.Errorf("total = %d, want 3", total)
+    }
+    if histogram[3].Count != 1 {
+        t.Errorf("el más antiguo debería caer en el último bucket: %+v", histogram)
+    }
+}
+
+func TestSpaceLabelSurvivesEnterExit(t *testing.T) {
+    tests := []struct {
+        name    string
+        spaceID int
+        label   string
+        cycles  int
+        wantErr error
+    }{
+        {"una entrada y salida", 0, "A-1", 1, nil},
+        {"varias vueltas", 0, "Discapacitados", 5, nil},
+        {"etiqueta vacía", 0, "", 1, nil},
+        {"espacio negativo", -1, "X", 0, ErrInvalidSpace},
+        {"espacio fuera de rango", 1, "X", 0, ErrInvalidSpace},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lot := NewParkingLot(1, func(int, string) {})
+            var notified []string
+            lot.SetSpaceLabelCallback(func(spaceID int, label string) {
+                notified = append(notified, label)
+            })
+
+            if err := lot.SetSpaceLabel(tt.spaceID, tt.label); err != tt.wantErr {
+                t.Fatalf("SetSpaceLabel = %v, want %v", err, tt.wantErr)
+            }
+            if tt.wantErr != nil {
+                if _, err := lot.GetSpaceLabel(tt.spaceID); err != tt.wantErr {
+                    t.Errorf("GetSpaceLabel = %v, want %v", err, tt.wantErr)
+                }
+                if len(notified) != 0 {
+                    t.Errorf("avisos = %v, want ninguno", notified)
+                }
+                return
+            }
+            for i := 0; i < tt.cycles; i++ {
+                vehicle := NewVehicle(i + 1)
+                if !lot.TryEnter(vehicle) {
+                    t.Fatalf("vuelta %d: el vehículo no pudo entrar", i)
+                }
+                lot.Exit(vehicle)
+            }
+
+            label, err := lot.GetSpaceLabel(tt.spaceID)
+            if err != nil || label != tt.label {
+                t.Errorf("GetSpaceLabel = %q, %v, want %q", label, err, tt.label)
+            }
+            if len(notified) != 1 || notified[0] != tt.label {
+                t.Errorf("avisos = %q, want [%q]", notified, tt.label)
+            }
+        })
+    }
+}
+
+func TestContiguousFreeSpacesStaysInRowAndPicksBestFit(t *testing.T) {
+    tests := []struct {
+        name        string
+        occupied    []int
+        n           int
+        want        []int
+        wantLargest int
+    }{
+        // Dos filas de cinco: 0-4 y 5-9.
+        {"lote vacío", nil, 2, []int{0, 1}, 5},
+        {"no cruza de fila", []int{0, 1, 2, 3, 6, 7, 8, 9}, 2, nil, 1},
+        {"corrida justa al final de la fila", []int{0, 1, 2, 5, 6, 7, 8, 9}, 2, []int{3, 4}, 2},
+        {"prefiere la corrida más corta", []int{2, 5}, 2, []int{0, 1}, 4},
+        {"deja libre la fila entera", []int{3}, 3, []int{0, 1, 2}, 5},
+        {"no hay corrida suficiente", []int{1, 3, 6, 8}, 2, nil, 1},
+        {"lote lleno", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, 1, nil, 0},
+        {"sin espacios pedidos", nil, 0, nil, 5},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lot := NewParkingLot(10, func(int, string) {})
+            lot.SetGridColumns(5)
+            for _, id := range tt.occupied {
+                lot.spaces[id].OccupiedBy = NewVehicle(id + 1)
+            }
+
+            got, ok := lot.GetContiguousFreeSpaces(tt.n)
+            if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("GetContiguousFreeSpaces(%d) = %v, %v, want %v", tt.n, got, ok, tt.want)
+            }
+            if largest := lot.GetLargestFreeBlock(); largest != tt.wantLargest {
+                t.Errorf("GetLargestFreeBlock = %d, want %d", largest, tt.wantLargest)
+            }
+        })
+    }
+}
+
+// withBusyTimes arma un estacionamiento cuyo historial tiene, por espacio,
+// una estancia de busy[i] que empezó hace since.
+func withBusyTimes(since time.Duration, busy ...time.Duration) *ParkingLot {
+    lot := NewParkingLot(len(busy), func(int, string) {})
+    start := time.Now().Add(-since)
+    for id, d := range busy {
+        if d > 0 {
+            lot.recordHistory(SpaceHistoryEntry{SpaceID: id, VehicleID: id + 1, EntryTime: start, ExitTime: start.Add(d)})
+        }
+    }
+    return lot
+}
+
+func TestGetEntropyScore(t *testing.T) {
+    s := time.Second
+    tests := []struct {
+        name string
+        busy []time.Duration
+        want float64
+    }{
+        {"sin historial", []time.Duration{0, 0, 0, 0}, 0},
+        {"reparto parejo", []time.Duration{10 * s, 10 * s, 10 * s, 10 * s}, 1},
+        {"todo en un espacio", []time.Duration{40 * s, 0, 0, 0}, 0},
+        {"la mitad de los espacios", []time.Duration{20 * s, 20 * s, 0, 0}, 0.5},
+        // -(0.75·log2 0.75 + 0.25·log2 0.25) / log2 2
+        {"dos espacios desparejos", []time.Duration{30 * s, 10 * s}, 0.8112781244591328},
+        {"un solo espacio usado", []time.Duration{5 * s}, 1},
+        {"un solo espacio sin usar", []time.Duration{0}, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lot := withBusyTimes(time.Minute, tt.busy...)
+            if got := lot.GetEntropyScore(); math.Abs(got-tt.want) > 1e-9 {
+                t.Errorf("GetEntropyScore = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestGetUnderutilizedSpaces(t *testing.T) {
+    s := time.Second
+    tests := []struct {
+        name      string
+        busy      []time.Duration
+        threshold float64
+        want      []int
+    }{
+        {"sin historial", []time.Duration{0, 0, 0}, 0.5, nil},
+        {"bajo el umbral", []time.Duration{90 * s, 10 * s, 0, 50 * s}, 0.3, []int{1, 2}},
+        {"umbral cero", []time.Duration{90 * s, 10 * s, 0}, 0, nil},
+        {"todos bajo un umbral alto", []time.Duration{90 * s, 10 * s}, 1, []int{0, 1}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lot := withBusyTimes(100*time.Second, tt.busy...)
+            if got := lot.GetUnderutilizedSpaces(tt.threshold); !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("GetUnderutilizedSpaces(%v) = %v, want %v", tt.threshold, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+    "math"
+    "testing"
+    "time"
+)
+
+func TestGetSpaceTurnoverRatesNormalizesByOpenTime(t *testing.T) {
+    since := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+    now := since.Add(2 * time.Hour)
+
+    tests := []struct {
+        name     string
+        closures []spaceClosure
+        want     float64
+    }{
+        {"abierto todo el periodo", nil, 2},
+        {"cerrado la mitad", []spaceClosure{{spaceID: 1, from: since, to: since.Add(time.Hour)}}, 4},
+        {"cerrado desde antes de since", []spaceClosure{{spaceID: 1, from: since.Add(-time.Hour), to: since.Add(time.Hour)}}, 4},
+        {"sigue cerrado", []spaceClosure{{spaceID: 1, from: since.Add(time.Hour)}}, 4},
+        {"cerrado todo el periodo", []spaceClosure{{spaceID: 1, from: since, to: now}}, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lot := NewParkingLot(2, func(int, string) {})
+            // Cuatro salidas en cada espacio durante las dos horas
+            for i := 0; i < 4; i++ {
+                exit := since.Add(time.Duration(i)*30*time.Minute + time.Minute)
+                lot.recordHistory(SpaceHistoryEntry{SpaceID: 0, VehicleID: i, ExitTime: exit})
+                lot.recordHistory(SpaceHistoryEntry{SpaceID: 1, VehicleID: 10 + i, ExitTime: exit})
+            }
+            lot.closures = tt.closures
+
+            rates := lot.GetSpaceTurnoverRates(since, now)
+            if len(rates) != 2 {
+                t.Fatalf("len(rates) = %d, want 2", len(rates))
+            }
+            if math.Abs(rates[0]-2) > 1e-9 {
+                t.Errorf("espacio abierto: rate = %v, want 2", rates[0])
+            }
+            if math.Abs(rates[1]-tt.want) > 1e-9 {
+                t.Errorf("rate = %v, want %v", rates[1], tt.want)
+            }
+        })
+    }
+}
+
+func TestDoubleParkingRecordsClosure(t *testing.T) {
+    lot := NewParkingLot(2, func(int, string) {})
+    start := time.Now()
+    lot.mu.Lock()
+    lot.recordClosure(1, start)
+    lot.endClosure(1, start.Add(time.Minute))
+    lot.mu.Unlock()
+
+    if len(lot.closures) != 1 || !lot.closures[0].to.Equal(start.Add(time.Minute)) {
+        t.Fatalf("closures = %+v, want one closed interval", lot.closures)
+    }
+}
@@ -0,0 +1,73 @@
+package models
+
+import "fmt"
+
+// acquireSpace y releaseSpace envuelven el semáforo de espacios y llevan la
+// cuenta de unidades tomadas, ya que el semáforo no se puede consultar. Deben
+// llamarse con mu tomado.
+func (p *ParkingLot) acquireSpace() bool {
+    if !p.spaceSem.TryAcquire(1) {
+        return false
+    }
+    p.semHeld++
+    return true
+}
+
+func (p *ParkingLot) releaseSpace() {
+    p.semHeld--
+    p.spaceSem.Release(1)
+}
+
+// Validate revisa la consistencia interna del estacionamiento y devuelve una
+// lista con cada problema encontrado, o nil si todo cuadra.
+func (p *ParkingLot) Validate() []error {
+    p.mu.RLock()
+    defer p.mu.RUnlock()
+
+    var errs []error
+
+    blocked := 0
+    assigned := map[int]int{}
+    for _, space := range p.spaces {
+        if space.Blocked {
+            blocked++
+        }
+        if space.OccupiedBy == nil {
+            continue
+        }
+        if previous, ok := assigned[space.OccupiedBy.ID]; ok {
+            errs = append(errs, fmt.Errorf("vehículo %d ocupa %s y %s", space.OccupiedBy.ID, p.spaces[previous].Label, space.Label))
+        }
+        assigned[space.OccupiedBy.ID] = space.ID
+    }
+
+    if int(p.occupiedSpaces) != len(p.vehicleSpaces)+blocked {
+        errs = append(errs, fmt.Errorf("occupiedSpaces es %d, pero hay %d espacios asignados y %d bloqueados",
+            p.occupiedSpaces, len(p.vehicleSpaces), blocked))
+    }
+    if p.semHeld != p.occupiedSpaces {
+        errs = append(errs, fmt.Errorf("el semáforo tiene %d unidades tomadas, pero occupiedSpaces es %d", p.semHeld, p.occupiedSpaces))
+    }
+    if p.occupiedSpaces < 0 || p.occupiedSpaces > p.Capacity {
+        errs = append(errs, fmt.Errorf("occupiedSpaces %d fuera del rango 0..%d", p.occupiedSpaces, p.Capacity))
+    }
+
+    for vehicleID, spaceID := range p.vehicleSpaces {
+        occupant := p.spaces[spaceID].OccupiedBy
+        if occupant == nil || occupant.ID != vehicleID {
+            errs = append(errs, fmt.Errorf("vehículo %d figura en %s, pero el espacio no lo tiene", vehicleID, p.spaces[spaceID].Label))
+        }
+    }
+    for vehicleID := range p.vehicles {
+        if _, ok := p.vehicleSpaces[vehicleID]; !ok {
+            errs = append(errs, fmt.Errorf("vehículo %d está dentro sin espacio asignado", vehicleID))
+        }
+    }
+
+    for _, vehicle := range p.waitingQueue {
+        if state := vehicle.GetState(); state != Waiting {
+            errs = append(errs, fmt.Errorf("%s está en la cola, pero no en estado esperando", vehicle))
+        }
+    }
+    return errs
+}
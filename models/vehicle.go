@@ -1,97 +1,204 @@
-package models
-
-import (
-    "fmt"
-    "sync"
-    "time"
-)
-
-type VehicleState int
-
-const (
-    Waiting VehicleState = iota
-    Entering
-    Parked
-    Exiting
-)
-
-type Vehicle struct {
-    ID        int
-    state     VehicleState
-    EntryTime time.Time
-    ExitTime  time.Time
-    mu        sync.RWMutex 
-}
-
-var stateStrings = map[VehicleState]string{
-    Waiting:  "esperando",
-    Entering: "entrando",
-    Parked:   "estacionado",
-    Exiting:  "saliendo",
-}
-
-func NewVehicle(id int) *Vehicle {
-    return &Vehicle{
-        ID:        id,
-        state:     Waiting,
-        EntryTime: time.Now(),
-    }
-}
-
-func (v *Vehicle) String() string {
-    v.mu.RLock()
-    defer v.mu.RUnlock()
-    return fmt.Sprintf("Vehículo %d [%s]", v.ID, stateStrings[v.state])
-}
-
-func (v *Vehicle) SetState(state VehicleState) {
-    v.mu.Lock()
-    defer v.mu.Unlock()
-    
-    v.state = state
-    if state == Entering && v.EntryTime.IsZero() {
-        v.EntryTime = time.Now()
-    } else if state == Exiting {
-        v.ExitTime = time.Now()
-    }
-}
-
-func (v *Vehicle) GetState() VehicleState {
-    v.mu.RLock()
-    defer v.mu.RUnlock()
-    return v.state
-}
-
-func (v *Vehicle) GetParkingDuration() time.Duration {
-    v.mu.RLock()
-    defer v.mu.RUnlock()
-    
-    if v.state == Exiting || v.ExitTime.After(v.EntryTime) {
-        return v.ExitTime.Sub(v.EntryTime)
-    }
-    return time.Since(v.EntryTime)
-}
-
-func (v *Vehicle) IsParked() bool {
-    v.mu.RLock()
-    defer v.mu.RUnlock()
-    return v.state == Parked
-}
-
-func (v *Vehicle) GetEntryTime() time.Time {
-    v.mu.RLock()
-    defer v.mu.RUnlock()
-    return v.EntryTime
-}
-
-func (v *Vehicle) GetExitTime() time.Time {
-    v.mu.RLock()
-    defer v.mu.RUnlock()
-    return v.ExitTime
-}
-
-func (v *Vehicle) GetStateString() string {
-    v.mu.RLock()
-    defer v.mu.RUnlock()
-    return stateStrings[v.state]
+package models
+
+import (
+    "fmt"
+    "sync"
+    "time"
+)
+
+type VehicleState int
+
+const (
+    Waiting VehicleState = iota
+    Entering
+    Parked
+    Exiting
+)
+
+type StateTransition struct {
+    State VehicleState
+    At    time.Time
+}
+
+// VehicleStates enumera los estados en el orden del recorrido.
+var VehicleStates = []VehicleState{Waiting, Entering, Parked, Exiting}
+
+type Vehicle struct {
+    ID               int
+    Visit            int
+    state            VehicleState
+    ArrivalTime      time.Time
+    QueuedAt         time.Time
+    QueueKey         float64
+    EntryTime        time.Time
+    ExitTime         time.Time
+    ExpectedExitTime time.Time
+    Patience         time.Duration
+    EntryAttempts    int
+    IntendedStay     time.Duration
+    BilledStay       time.Duration
+    Preexisting      bool
+    customData       sync.Map
+    stateSince       time.Time
+    stateTimes       map[VehicleState]time.Duration
+    transitions      []StateTransition
+    mu               sync.RWMutex 
+}
+
+var stateStrings = map[VehicleState]string{
+    Waiting:  "esperando",
+    Entering: "entrando",
+    Parked:   "estacionado",
+    Exiting:  "saliendo",
+}
+
+func NewVehicle(id int) *Vehicle {
+    now := time.Now()
+    return &Vehicle{
+        ID:          id,
+        Visit:       1,
+        state:       Waiting,
+        ArrivalTime: now,
+        stateSince:  now,
+        stateTimes:  make(map[VehicleState]time.Duration),
+        transitions: []StateTransition{{State: Waiting, At: now}},
+    }
+}
+
+func (v *Vehicle) String() string {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+    if v.Preexisting {
+        return fmt.Sprintf("Vehículo preexistente %d [%s]", -v.ID, stateStrings[v.state])
+    }
+    return fmt.Sprintf("Vehículo %d [%s]", v.ID, stateStrings[v.state])
+}
+
+func (v *Vehicle) SetState(state VehicleState) {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    
+    now := time.Now()
+    if state != v.state {
+        v.closeState(now)
+        v.transitions = append(v.transitions, StateTransition{State: state, At: now})
+    }
+    v.state = state
+    if state == Entering && v.EntryTime.IsZero() {
+        v.EntryTime = now
+    } else if state == Exiting {
+        v.ExitTime = now
+    }
+}
+
+// closeState suma al estado actual el tiempo transcurrido desde que se
+// entró en él. Debe llamarse con mu tomado.
+func (v *Vehicle) closeState(now time.Time) {
+    if v.stateTimes == nil {
+        v.stateTimes = make(map[VehicleState]time.Duration)
+    }
+    if !v.stateSince.IsZero() {
+        v.stateTimes[v.state] += now.Sub(v.stateSince)
+    }
+    v.stateSince = now
+}
+
+// GetTimeInState devuelve cuánto tiempo pasó el vehículo en cada estado,
+// incluido lo que lleva en el actual. La suma es el tiempo en el sistema.
+func (v *Vehicle) GetTimeInState() map[VehicleState]time.Duration {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+
+    times := make(map[VehicleState]time.Duration, len(v.stateTimes)+1)
+    for state, d := range v.stateTimes {
+        times[state] = d
+    }
+    if !v.stateSince.IsZero() {
+        times[v.state] += time.Since(v.stateSince)
+    }
+    return times
+}
+
+func (v *Vehicle) GetState() VehicleState {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+    return v.state
+}
+
+func (v *Vehicle) GetParkingDuration() time.Duration {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+    return v.parkingDuration()
+}
+
+// parkingDuration y waitDuration deben llamarse con mu tomado.
+func (v *Vehicle) parkingDuration() time.Duration {
+    if v.EntryTime.IsZero() {
+        return 0
+    }
+    if v.state == Exiting || v.ExitTime.After(v.EntryTime) {
+        return v.ExitTime.Sub(v.EntryTime)
+    }
+    return time.Since(v.EntryTime)
+}
+
+func (v *Vehicle) GetWaitDuration() time.Duration {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+    return v.waitDuration()
+}
+
+func (v *Vehicle) waitDuration() time.Duration {
+    if v.EntryTime.IsZero() {
+        return time.Since(v.ArrivalTime)
+    }
+    return v.EntryTime.Sub(v.ArrivalTime)
+}
+
+// GetResponseTime es el tiempo total en el sistema: espera más estancia.
+// Devuelve 0 si el vehículo todavía no ha salido.
+func (v *Vehicle) GetResponseTime() time.Duration {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+
+    if v.ExitTime.IsZero() {
+        return 0
+    }
+    return v.ExitTime.Sub(v.ArrivalTime)
+}
+
+func (v *Vehicle) IsParked() bool {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+    return v.state == Parked
+}
+
+func (v *Vehicle) GetEntryTime() time.Time {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+    return v.EntryTime
+}
+
+func (v *Vehicle) GetExitTime() time.Time {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+    return v.ExitTime
+}
+
+func (v *Vehicle) SetExpectedExitTime(t time.Time) {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    v.ExpectedExitTime = t
+}
+
+func (v *Vehicle) GetExpectedExitTime() time.Time {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+    return v.ExpectedExitTime
+}
+
+func (v *Vehicle) GetStateString() string {
+    v.mu.RLock()
+    defer v.mu.RUnlock()
+    return stateStrings[v.state]
 }
\ No newline at end of file
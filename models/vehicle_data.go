@@ -0,0 +1,186 @@
+package models
+
+import (
+    "encoding/json"
+    "sort"
+    "time"
+)
+
+// SetCustomData guarda un dato arbitrario asociado al vehículo, para que
+// otros módulos lo extiendan sin modificar el struct.
+func (v *Vehicle) SetCustomData(key string, value interface{}) {
+    v.customData.Store(key, value)
+}
+
+func (v *Vehicle) GetCustomData(key string) (interface{}, bool) {
+    return v.customData.Load(key)
+}
+
+func (v *Vehicle) ClearCustomData() {
+    v.customData.Range(func(key, _ interface{}) bool {
+        v.customData.Delete(key)
+        return true
+    })
+}
+
+func (v *Vehicle) CustomDataKeys() []string {
+    keys := []string{}
+    v.customData.Range(func(key, _ interface{}) bool {
+        keys = append(keys, key.(string))
+        return true
+    })
+    sort.Strings(keys)
+    return keys
+}
+
+type vehicleJSON struct {
+    ID          int                    `json:"id"`
+    Visit       int                    `json:"visit"`
+    State       string                 `json:"state"`
+    ArrivalTime time.Time              `json:"arrivalTime"`
+    EntryTime   time.Time              `json:"entryTime"`
+    ExitTime    time.Time              `json:"exitTime"`
+    CustomData  map[string]interface{} `json:"customData,omitempty"`
+}
+
+// MarshalJSON incluye los datos personalizados que se pueden serializar:
+// tipos primitivos y valores que implementan json.Marshaler. El resto se omite.
+func (v *Vehicle) MarshalJSON() ([]byte, error) {
+    v.mu.RLock()
+    out := vehicleJSON{
+        ID:          v.ID,
+        Visit:       v.Visit,
+        State:       stateStrings[v.state],
+        ArrivalTime: v.ArrivalTime,
+        EntryTime:   v.EntryTime,
+        ExitTime:    v.ExitTime,
+    }
+    v.mu.RUnlock()
+
+    v.customData.Range(func(key, value interface{}) bool {
+        if !isSerializableCustomData(value) {
+            return true
+        }
+        if out.CustomData == nil {
+            out.CustomData = map[string]interface{}{}
+        }
+        out.CustomData[key.(string)] = value
+        return true
+    })
+    return json.Marshal(out)
+}
+
+func isSerializableCustomData(value interface{}) bool {
+    switch value.(type) {
+    case nil, bool, string,
+        int, int8, int16, int32, int64,
+        uint, uint8, uint16, uint32, uint64,
+        float32, float64, json.Marshaler:
+        return true
+    }
+    return false
+}
+
+// LifecycleSummary reúne todos los tiempos de un vehículo, leídos de una
+// sola vez para que sean coherentes entre sí.
+type LifecycleSummary struct {
+    ID            int
+    Visit         int
+    State         VehicleState
+    ArrivalTime   time.Time
+    EntryTime     time.Time
+    ExitTime      time.Time
+    WaitDuration  time.Duration
+    ParkDuration  time.Duration
+    EntryAttempts int
+    IntendedStay  time.Duration
+    BilledStay    time.Duration
+    StateHistory  []StateTransition
+    CustomData    map[string]interface{}
+}
+
+func (v *Vehicle) GetLifecycleSummary() LifecycleSummary {
+    v.mu.RLock()
+    summary := LifecycleSummary{
+        ID:            v.ID,
+        Visit:         v.Visit,
+        State:         v.state,
+        ArrivalTime:   v.ArrivalTime,
+        EntryTime:     v.EntryTime,
+        ExitTime:      v.ExitTime,
+        WaitDuration:  v.waitDuration(),
+        ParkDuration:  v.parkingDuration(),
+        EntryAttempts: v.EntryAttempts,
+        IntendedStay:  v.IntendedStay,
+        BilledStay:    v.BilledStay,
+        StateHistory:  append([]StateTransition(nil), v.transitions...),
+    }
+    v.mu.RUnlock()
+
+    v.customData.Range(func(key, value interface{}) bool {
+        if summary.CustomData == nil {
+            summary.CustomData = map[string]interface{}{}
+        }
+        summary.CustomData[key.(string)] = value
+        return true
+    })
+    return summary
+}
+
+type stateTransitionJSON struct {
+    State string    `json:"state"`
+    At    time.Time `json:"at"`
+}
+
+type lifecycleSummaryJSON struct {
+    ID            int                    `json:"id"`
+    Visit         int                    `json:"visit"`
+    State         string                 `json:"state"`
+    ArrivalTime   time.Time              `json:"arrivalTime"`
+    EntryTime     *time.Time             `json:"entryTime,omitempty"`
+    ExitTime      *time.Time             `json:"exitTime,omitempty"`
+    WaitSeconds   float64                `json:"waitSeconds"`
+    ParkSeconds   float64                `json:"parkSeconds"`
+    EntryAttempts int                    `json:"entryAttempts"`
+    IntendedStay  float64                `json:"intendedStaySeconds,omitempty"`
+    BilledStay    float64                `json:"billedStaySeconds,omitempty"`
+    StateHistory  []stateTransitionJSON  `json:"stateHistory"`
+    CustomData    map[string]interface{} `json:"customData,omitempty"`
+}
+
+// MarshalJSON expresa las duraciones en segundos, los estados por nombre y
+// omite las horas que todavía no ocurrieron. Los datos personalizados siguen
+// la misma regla que Vehicle.MarshalJSON.
+func (s LifecycleSummary) MarshalJSON() ([]byte, error) {
+    out := lifecycleSummaryJSON{
+        ID:            s.ID,
+        Visit:         s.Visit,
+        State:         stateStrings[s.State],
+        ArrivalTime:   s.ArrivalTime,
+        WaitSeconds:   s.WaitDuration.Seconds(),
+        ParkSeconds:   s.ParkDuration.Seconds(),
+        EntryAttempts: s.EntryAttempts,
+        IntendedStay:  s.IntendedStay.Seconds(),
+        BilledStay:    s.BilledStay.Seconds(),
+        StateHistory:  make([]stateTransitionJSON, len(s.StateHistory)),
+    }
+    if !s.EntryTime.IsZero() {
+        out.EntryTime = &s.EntryTime
+    }
+    if !s.ExitTime.IsZero() {
+        out.ExitTime = &s.ExitTime
+    }
+    for i, transition := range s.StateHistory {
+        out.StateHistory[i] = stateTransitionJSON{State: stateStrings[transition.State], At: transition.At}
+    }
+    for key, value := range s.CustomData {
+        if !isSerializableCustomData(value) {
+            continue
+        }
+        if out.CustomData == nil {
+            out.CustomData = map[string]interface{}{}
+        }
+        out.CustomData[key] = value
+    }
+    return json.Marshal(out)
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+    "encoding/json"
+    "fmt"
+    "sync"
+    "testing"
+)
+
+func TestCustomDataConcurrentWrites(t *testing.T) {
+    tests := []struct {
+        name    string
+        writers int
+        keys    int
+        shared  bool
+    }{
+        {"una clave por goroutine", 16, 1, false},
+        {"varias claves por goroutine", 8, 50, false},
+        {"todas escriben la misma clave", 32, 1, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            vehicle := NewVehicle(1)
+            var wg sync.WaitGroup
+            for w := 0; w < tt.writers; w++ {
+                wg.Add(1)
+                go func(w int) {
+                    defer wg.Done()
+                    for k := 0; k < tt.keys; k++ {
+                        key := fmt.Sprintf("w%d-k%d", w, k)
+                        if tt.shared {
+                            key = "compartida"
+                        }
+                        vehicle.SetCustomData(key, w)
+                        vehicle.GetCustomData(key)
+                        if _, err := json.Marshal(vehicle); err != nil {
+                            t.Errorf("MarshalJSON: %v", err)
+                        }
+                    }
+                }(w)
+            }
+            wg.Wait()
+
+            want := tt.writers * tt.keys
+            if tt.shared {
+                want = 1
+            }
+            if keys := vehicle.CustomDataKeys(); len(keys) != want {
+                t.Errorf("claves = %d, want %d", len(keys), want)
+            }
+            if !tt.shared {
+                for w := 0; w < tt.writers; w++ {
+                    if value, ok := vehicle.GetCustomData(fmt.Sprintf("w%d-k0", w)); !ok || value != w {
+                        t.Errorf("w%d-k0 = %v, %v, want %d", w, value, ok, w)
+                    }
+                }
+            }
+        })
+    }
+}
+
+func TestVehicleJSONSkipsUnserializableCustomData(t *testing.T) {
+    tests := []struct {
+        name  string
+        value interface{}
+        keep  bool
+    }{
+        {"texto", "ABC-123", true},
+        {"número", 42, true},
+        {"booleano", true, true},
+        {"nil", nil, true},
+        {"canal", make(chan int), false},
+        {"función", func() {}, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            vehicle := NewVehicle(1)
+            vehicle.SetCustomData("dato", tt.value)
+            data, err := json.Marshal(vehicle)
+            if err != nil {
+                t.Fatalf("MarshalJSON: %v", err)
+            }
+            var decoded struct {
+                CustomData map[string]interface{} `json:"customData"`
+            }
+            if err := json.Unmarshal(data, &decoded); err != nil {
+                t.Fatal(err)
+            }
+            if _, ok := decoded.CustomData["dato"]; ok != tt.keep {
+                t.Errorf("dato incluido = %v, want %v (%s)", ok, tt.keep, data)
+            }
+        })
+    }
+}
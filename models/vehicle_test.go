@@ -0,0 +1,70 @@
+package models
+
+import (
+    "testing"
+    "time"
+)
+
+type lifecycleStep struct {
+    state VehicleState
+    spent time.Duration
+}
+
+// runLifecycle pasa al vehículo por steps como si hubiera estado spent en
+// cada uno, sin esperar ese tiempo.
+func runLifecycle(v *Vehicle, steps []lifecycleStep) {
+    for i, step := range steps {
+        if i > 0 {
+            v.SetState(step.state)
+        }
+        v.mu.Lock()
+        v.stateSince = v.stateSince.Add(-step.spent)
+        v.ArrivalTime = v.ArrivalTime.Add(-step.spent)
+        v.mu.Unlock()
+    }
+}
+
+func TestGetTimeInStateOnScriptedLifecycle(t *testing.T) {
+    const tolerance = 20 * time.Millisecond
+    tests := []struct {
+        name  string
+        steps []lifecycleStep
+        want  map[VehicleState]time.Duration
+    }{
+        {"recorrido completo", []lifecycleStep{
+            {Waiting, 3 * time.Second},
+            {Entering, time.Second},
+            {Parked, 40 * time.Second},
+            {Exiting, 2 * time.Second},
+        }, map[VehicleState]time.Duration{Waiting: 3 * time.Second, Entering: time.Second, Parked: 40 * time.Second, Exiting: 2 * time.Second}},
+        {"rechazado en la cola", []lifecycleStep{
+            {Waiting, 5 * time.Second},
+        }, map[VehicleState]time.Duration{Waiting: 5 * time.Second}},
+        {"vuelve a esperar", []lifecycleStep{
+            {Waiting, 2 * time.Second},
+            {Entering, time.Second},
+            {Waiting, 4 * time.Second},
+            {Entering, time.Second},
+            {Parked, 10 * time.Second},
+        }, map[VehicleState]time.Duration{Waiting: 6 * time.Second, Entering: 2 * time.Second, Parked: 10 * time.Second}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            v := NewVehicle(1)
+            runLifecycle(v, tt.steps)
+
+            times := v.GetTimeInState()
+            var sum time.Duration
+            for _, state := range VehicleStates {
+                got := times[state]
+                sum += got
+                if diff := got - tt.want[state]; diff < -tolerance || diff > tolerance {
+                    t.Errorf("tiempo %s = %v, want %v", stateStrings[state], got, tt.want[state])
+                }
+            }
+            if inSystem := time.Since(v.ArrivalTime); sum-inSystem < -tolerance || sum-inSystem > tolerance {
+                t.Errorf("suma por estado = %v, tiempo en el sistema = %v", sum, inSystem)
+            }
+        })
+    }
+}
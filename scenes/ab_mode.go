@@ -0,0 +1,144 @@
+package scenes
+
+import (
+    "errors"
+    "fmt"
+    "sync/atomic"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/widget"
+    "holafyne/services"
+)
+
+const currentConfigOption = "Configuración actual"
+
+// abMode corre dos configuraciones a la vez sobre el mismo flujo de llegadas.
+// Las dos acumulan sus estadísticas; solo la activa se dibuja.
+type abMode struct {
+    sims    [2]*services.Simulation
+    titles  [2]string
+    configs [2]services.SimulationConfig
+    active  atomic.Int32
+}
+
+// showABDialog pide las dos configuraciones del modo A/B entre la actual y
+// los escenarios incluidos.
+func (s *ParkingScene) showABDialog() {
+    scenarios, err := services.LoadScenarios()
+    if err != nil {
+        dialog.ShowError(err, s.window)
+        return
+    }
+    configs := map[string]services.SimulationConfig{currentConfigOption: s.simulation.GetConfig()}
+    options := []string{currentConfigOption}
+    for _, scenario := range scenarios {
+        configs[scenario.Title] = scenario.Config
+        options = append(options, scenario.Title)
+    }
+
+    selectA := widget.NewSelect(options, nil)
+    selectA.SetSelected(currentConfigOption)
+    selectB := widget.NewSelect(options, nil)
+    selectB.SetSelected(options[len(options)-1])
+    form := []*widget.FormItem{
+        widget.NewFormItem("A", selectA),
+        widget.NewFormItem("B", selectB),
+    }
+    dialog.ShowForm("Modo A/B", "Cargar", "Cancelar", form, func(confirmed bool) {
+        if !confirmed {
+            return
+        }
+        a, b := selectA.Selected, selectB.Selected
+        if err := s.enterABMode([2]string{a, b}, [2]services.SimulationConfig{configs[a], configs[b]}); err != nil {
+            dialog.ShowError(err, s.window)
+        }
+    }, s.window)
+}
+
+// enterABMode arma las dos simulaciones. Las llegadas salen del generador de
+// A, con su tasa y su MaxVehicles, y B recibe los mismos vehículos en el
+// mismo momento.
+func (s *ParkingScene) enterABMode(titles [2]string, configs [2]services.SimulationConfig) error {
+    for i, config := range configs {
+        if err := config.Validate(); err != nil {
+            return fmt.Errorf("%s: %w", titles[i], err)
+        }
+        if config.ClosedPopulation > 0 {
+            return errors.New("el modo A/B no admite poblaciones cerradas")
+        }
+    }
+
+    s.stopSimulations()
+    s.leaveABMode()
+
+    ab := &abMode{titles: titles, configs: configs}
+    for i := range configs {
+        index := int32(i)
+        if i == 0 && configs[i].VehicleLogPath == "" {
+            configs[i].VehicleLogPath = s.vehicleLogPath
+        }
+        // Las dos escribirían el mismo archivo
+        if i == 1 {
+            configs[i].VehicleLogPath = ""
+        }
+        ab.sims[i] = services.NewSimulationWithConfig(configs[i], func(spaces int, message string) {
+            if ab.active.Load() == index {
+                s.updateUI(spaces, message)
+            }
+        })
+    }
+    if err := services.ShareArrivals(ab.sims[:]...); err != nil {
+        return err
+    }
+
+    s.ab = ab
+    s.attachSimulation(ab.sims[0])
+    s.abButton.SetText("Ver B")
+    s.abButton.Show()
+    s.startButton.Enable()
+    s.stopButton.Disable()
+    s.resetPauseButton()
+    s.logBox.SetText(s.logBox.Text() + "\n" + fmt.Sprintf("Modo A/B: A = %s, B = %s", titles[0], titles[1]))
+    return nil
+}
+
+// restartABMode vuelve a armar las dos simulaciones después de Stop: el flujo
+// compartido ya se agotó y no se puede reiniciar.
+func (s *ParkingScene) restartABMode() {
+    if s.ab == nil || s.ab.sims[0].GetPhase() != services.PhaseStopped {
+        return
+    }
+    if err := s.enterABMode(s.ab.titles, s.ab.configs); err != nil {
+        dialog.ShowError(err, s.window)
+    }
+}
+
+// toggleAB cambia la simulación que se dibuja. La otra sigue corriendo.
+func (s *ParkingScene) toggleAB() {
+    if s.ab == nil {
+        return
+    }
+    current := s.ab.active.Load()
+    next := 1 - current
+    s.detachSimulation(s.ab.sims[current])
+    s.ab.active.Store(next)
+    s.attachSimulation(s.ab.sims[next])
+
+    label := [2]string{"A", "B"}
+    s.abButton.SetText("Ver " + label[current])
+    s.logBox.SetText(s.logBox.Text() + "\n" + fmt.Sprintf("Mostrando %s: %s", label[next], s.ab.titles[next]))
+}
+
+// leaveABMode descarta la simulación que no se muestra. Las simulaciones ya
+// deben estar detenidas.
+func (s *ParkingScene) leaveABMode() {
+    if s.ab == nil {
+        return
+    }
+    for _, sim := range s.ab.sims {
+        if sim != s.simulation {
+            s.detachSimulation(sim)
+        }
+    }
+    s.ab = nil
+    s.abButton.Hide()
+}
@@ -0,0 +1,71 @@
+package scenes
+
+import (
+    "image/color"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/canvas"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+    "holafyne/images"
+)
+
+const (
+    backgroundKey = "background"
+
+    backgroundPlain = "Gris"
+    backgroundNight = "Noche"
+    backgroundDay   = "Día"
+)
+
+var defaultBackgroundColor = color.RGBA{R: 30, G: 30, B: 30, A: 255}
+
+var backgroundImages = map[string]fyne.Resource{
+    backgroundNight: images.BackgroundNight,
+    backgroundDay:   images.BackgroundDay,
+}
+
+func (s *ParkingScene) setupBackground() {
+    s.background = container.NewStack()
+    s.SetBackgroundColor(defaultBackgroundColor)
+}
+
+// SetBackgroundImage estira la imagen detrás de los espacios y la calle.
+func (s *ParkingScene) SetBackgroundImage(res fyne.Resource) {
+    image := canvas.NewImageFromResource(res)
+    image.FillMode = canvas.ImageFillStretch
+    s.setBackground(image)
+}
+
+// SetBackgroundColor vuelve a un fondo liso del color dado.
+func (s *ParkingScene) SetBackgroundColor(c color.RGBA) {
+    s.setBackground(canvas.NewRectangle(c))
+}
+
+func (s *ParkingScene) setBackground(object fyne.CanvasObject) {
+    s.background.Objects = []fyne.CanvasObject{object}
+    s.background.Refresh()
+    s.gameContainer.Refresh()
+}
+
+// createBackgroundSelect arma el selector de fondo y aplica el que quedó
+// guardado en las preferencias.
+func (s *ParkingScene) createBackgroundSelect() fyne.CanvasObject {
+    options := widget.NewSelect([]string{backgroundPlain, backgroundNight, backgroundDay}, s.selectBackground)
+    selected := backgroundPlain
+    if app := fyne.CurrentApp(); app != nil {
+        selected = app.Preferences().StringWithFallback(backgroundKey, backgroundPlain)
+    }
+    options.SetSelected(selected)
+    return container.NewHBox(widget.NewLabel("Fondo"), options)
+}
+
+func (s *ParkingScene) selectBackground(name string) {
+    if res, ok := backgroundImages[name]; ok {
+        s.SetBackgroundImage(res)
+    } else {
+        s.SetBackgroundColor(defaultBackgroundColor)
+    }
+    if app := fyne.CurrentApp(); app != nil {
+        app.Preferences().SetString(backgroundKey, name)
+    }
+}
@@ -0,0 +1,55 @@
+package scenes
+
+import (
+    "fyne.io/fyne/v2"
+    "holafyne/images"
+    "holafyne/models"
+)
+
+// ColorBlockIconProvider no devuelve ícono, así que los espacios se dibujan
+// solo con el rectángulo de color.
+func ColorBlockIconProvider(v *models.Vehicle) fyne.Resource {
+    return nil
+}
+
+// CarImageIconProvider dibuja cada vehículo con la imagen de carro incluida
+// en la aplicación.
+func CarImageIconProvider(v *models.Vehicle) fyne.Resource {
+    return images.Car
+}
+
+// SetCarIconProvider define qué ícono se muestra sobre cada espacio ocupado.
+// Si fn es nil o devuelve nil para un vehículo, se usa el rectángulo de color.
+func (s *ParkingScene) SetCarIconProvider(fn func(v *models.Vehicle) fyne.Resource) {
+    s.iconMu.Lock()
+    s.iconProvider = fn
+    s.iconMu.Unlock()
+    s.requestRefresh()
+}
+
+// refreshCarIcons consulta los espacios de la simulación, por lo que no debe
+// llamarse desde un callback que el estacionamiento invoca con su lock tomado.
+// Solo la llama runRefresher.
+func (s *ParkingScene) refreshCarIcons() {
+    s.iconMu.Lock()
+    provider, images := s.iconProvider, s.carImages
+    s.iconMu.Unlock()
+    for _, space := range s.simulation.GetSpaces() {
+        if space.ID >= len(images) {
+            continue
+        }
+        image := images[space.ID]
+
+        var resource fyne.Resource
+        if provider != nil && space.OccupiedBy != nil {
+            resource = provider(space.OccupiedBy)
+        }
+        if resource == nil {
+            image.Hide()
+            continue
+        }
+        image.Resource = resource
+        image.Refresh()
+        image.Show()
+    }
+}
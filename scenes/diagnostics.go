@@ -0,0 +1,53 @@
+package scenes
+
+import (
+    "strings"
+    "time"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/storage"
+    "holafyne/services"
+)
+
+const deadlockCheckInterval = 2 * time.Second
+
+// startWithMonitor arranca sim y, ya con el contexto de la corrida, el
+// monitor de bloqueos. Sus reportes van al log y quedan en el paquete de
+// diagnóstico.
+func (s *ParkingScene) startWithMonitor(sim *services.Simulation) {
+    if err := sim.Start(); err != nil {
+        return
+    }
+    go s.watchDeadlocks(sim.MonitorDeadlocks(deadlockCheckInterval))
+}
+
+func (s *ParkingScene) watchDeadlocks(reports <-chan services.DeadlockReport) {
+    for report := range reports {
+        s.logBox.SetText(s.logBox.Text() + "\n" + s.clockPrefix() + "⚠️ Posible bloqueo: " + report.Evidence)
+    }
+}
+
+// handleDiagnosticBundle pide dónde guardar el paquete de diagnóstico y lo
+// escribe con las líneas actuales del log.
+func (s *ParkingScene) handleDiagnosticBundle() {
+    save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+        if err != nil {
+            dialog.ShowError(err, s.window)
+            return
+        }
+        if writer == nil {
+            return
+        }
+        defer writer.Close()
+
+        log := strings.Split(s.logBox.Text(), "\n")
+        if err := s.simulation.WriteDiagnosticBundle(writer, log); err != nil {
+            dialog.ShowError(err, s.window)
+            return
+        }
+        s.logBox.SetText(s.logBox.Text() + "\n" + "Paquete de diagnóstico guardado en " + writer.URI().Path())
+    }, s.window)
+    save.SetFileName("diagnostico.zip")
+    save.SetFilter(storage.NewExtensionFileFilter([]string{".zip"}))
+    save.Show()
+}
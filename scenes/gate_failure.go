@@ -0,0 +1,44 @@
+package scenes
+
+import "fmt"
+
+// handleGateFailure se llama desde la goroutine de fallas de la simulación.
+// Mientras la pluma está descompuesta, pulseNextIcon hace parpadear la
+// barrera.
+func (s *ParkingScene) handleGateFailure(outOfService bool) {
+    s.gateBroken.Store(outOfService)
+    if !outOfService {
+        s.restoreBarrier()
+    }
+    s.updateGateDowntime()
+}
+
+func (s *ParkingScene) blinkBarrier(on bool) {
+    if !s.gateBroken.Load() {
+        return
+    }
+    if on {
+        s.entryBarrier.Show()
+    } else {
+        s.entryBarrier.Hide()
+    }
+}
+
+func (s *ParkingScene) restoreBarrier() {
+    if s.entryClosed {
+        s.entryBarrier.Show()
+    } else {
+        s.entryBarrier.Hide()
+    }
+}
+
+func (s *ParkingScene) updateGateDowntime() {
+    if s.simulation.GetConfig().GateFailure.MTBF <= 0 {
+        s.gateLabel.Hide()
+        return
+    }
+    metrics := s.simulation.GetMetrics()
+    s.gateLabel.SetText(fmt.Sprintf("Pluma: %d fallas · %.0f s fuera de servicio",
+        metrics.GateFailures, s.simulation.GetGateDowntime().Seconds()))
+    s.gateLabel.Show()
+}
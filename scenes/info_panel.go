@@ -0,0 +1,54 @@
+package scenes
+
+import (
+    "errors"
+    "fmt"
+    "fyne.io/fyne/v2"
+)
+
+// statsHeaderRows es el encabezado del panel de estadísticas (el título y el
+// separador), que SetInfoPanelWidgets no toca.
+const statsHeaderRows = 2
+
+var ErrInfoWidgetIndex = errors.New("fila fuera del panel de estadísticas")
+
+// SetInfoPanelWidgets reemplaza las filas del panel de estadísticas que van
+// debajo del separador. Para agregar filas a las de siempre, pasar
+// DefaultInfoPanelWidgets con las nuevas al final.
+func (s *ParkingScene) SetInfoPanelWidgets(widgets []fyne.CanvasObject) {
+    s.keepInfoDefaults()
+    header := s.statsContainer.Objects[:statsHeaderRows:statsHeaderRows]
+    s.statsContainer.Objects = append(header, widgets...)
+    s.statsContainer.Refresh()
+}
+
+// AppendInfoWidget agrega una fila al final del panel.
+func (s *ParkingScene) AppendInfoWidget(w fyne.CanvasObject) {
+    s.keepInfoDefaults()
+    s.statsContainer.Add(w)
+}
+
+// RemoveInfoWidget quita la fila index, contando desde 0 debajo del
+// separador.
+func (s *ParkingScene) RemoveInfoWidget(index int) error {
+    rows := len(s.statsContainer.Objects) - statsHeaderRows
+    if index < 0 || index >= rows {
+        return fmt.Errorf("%w: %d (hay %d filas)", ErrInfoWidgetIndex, index, rows)
+    }
+    s.keepInfoDefaults()
+    s.statsContainer.Remove(s.statsContainer.Objects[statsHeaderRows+index])
+    return nil
+}
+
+// DefaultInfoPanelWidgets devuelve las filas que tenía el panel antes del
+// primer cambio. Son las mismas etiquetas que la escena sigue actualizando.
+func (s *ParkingScene) DefaultInfoPanelWidgets() []fyne.CanvasObject {
+    if s.infoDefaults != nil {
+        return append([]fyne.CanvasObject(nil), s.infoDefaults...)
+    }
+    return append([]fyne.CanvasObject(nil), s.statsContainer.Objects[statsHeaderRows:]...)
+}
+
+func (s *ParkingScene) keepInfoDefaults() {
+    s.infoDefaults = s.DefaultInfoPanelWidgets()
+}
@@ -0,0 +1,130 @@
+package scenes
+
+import (
+    "fmt"
+    "image"
+    "image/color"
+    "sync"
+    "time"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/canvas"
+    "fyne.io/fyne/v2/widget"
+    "holafyne/services"
+)
+
+const intakeChartHeight = 80
+
+var (
+    generatedColor = color.NRGBA{R: 230, G: 140, B: 30, A: 255}
+    admittedColor  = color.NRGBA{R: 50, G: 150, B: 50, A: 255}
+    intakeGapColor = color.NRGBA{R: 200, G: 60, B: 60, A: 90}
+)
+
+// intakeChart dibuja los generados y los admitidos acumulados contra el
+// tiempo simulado, con la brecha entre las dos curvas sombreada. Con la
+// entrada abierta la brecha crece con los rechazos; con la entrada cerrada
+// las curvas van juntas y los cierres se ven como mesetas.
+type intakeChart struct {
+    mu      sync.Mutex
+    samples []services.IntakeSample
+    raster  *canvas.Raster
+}
+
+func newIntakeChart() *intakeChart {
+    c := &intakeChart{}
+    c.raster = canvas.NewRaster(c.draw)
+    c.raster.SetMinSize(fyne.NewSize(0, intakeChartHeight))
+    return c
+}
+
+func (c *intakeChart) setSamples(samples []services.IntakeSample) {
+    c.mu.Lock()
+    c.samples = samples
+    c.mu.Unlock()
+    c.raster.Refresh()
+}
+
+func (c *intakeChart) draw(w, h int) image.Image {
+    img := image.NewNRGBA(image.Rect(0, 0, w, h))
+    c.mu.Lock()
+    samples := c.samples
+    c.mu.Unlock()
+    if len(samples) < 2 || w < 2 || h < 2 {
+        return img
+    }
+    last := samples[len(samples)-1]
+    if last.SimTime <= 0 || last.Generated <= 0 {
+        return img
+    }
+
+    toY := func(count float64) int {
+        return h - 1 - int(count/float64(last.Generated)*float64(h-1))
+    }
+    prevGenerated, prevAdmitted := -1, -1
+    next := 0
+    for x := 0; x < w; x++ {
+        at := time.Duration(float64(last.SimTime) * float64(x) / float64(w-1))
+        for next < len(samples)-1 && samples[next+1].SimTime < at {
+            next++
+        }
+        generated, admitted := interpolateIntake(samples, next, at)
+        yGenerated, yAdmitted := toY(generated), toY(admitted)
+        fillColumn(img, x, yGenerated, yAdmitted, intakeGapColor)
+        if prevGenerated < 0 {
+            prevGenerated, prevAdmitted = yGenerated, yAdmitted
+        }
+        fillColumn(img, x, prevAdmitted, yAdmitted, admittedColor)
+        fillColumn(img, x, prevGenerated, yGenerated, generatedColor)
+        prevGenerated, prevAdmitted = yGenerated, yAdmitted
+    }
+    return img
+}
+
+// interpolateIntake es el valor de las dos curvas en at, interpolando entre
+// la foto i y la siguiente.
+func interpolateIntake(samples []services.IntakeSample, i int, at time.Duration) (generated, admitted float64) {
+    from := samples[i]
+    if i+1 >= len(samples) {
+        return float64(from.Generated), float64(from.Admitted)
+    }
+    to := samples[i+1]
+    t := 0.0
+    if span := to.SimTime - from.SimTime; span > 0 {
+        t = min(max(float64(at-from.SimTime)/float64(span), 0), 1)
+    }
+    generated = float64(from.Generated) + t*float64(to.Generated-from.Generated)
+    admitted = float64(from.Admitted) + t*float64(to.Admitted-from.Admitted)
+    return generated, admitted
+}
+
+// fillColumn pinta la columna x entre y0 e y1, ambos incluidos.
+func fillColumn(img *image.NRGBA, x, y0, y1 int, c color.NRGBA) {
+    if y0 > y1 {
+        y0, y1 = y1, y0
+    }
+    for y := y0; y <= y1; y++ {
+        img.SetNRGBA(x, y, c)
+    }
+}
+
+func (s *ParkingScene) setupIntakeChart() {
+    s.intakeLabel = widget.NewLabel("")
+    s.intakeChart = newIntakeChart()
+    s.statsContainer.Add(s.intakeLabel)
+    s.statsContainer.Add(s.intakeChart.raster)
+}
+
+// updateIntakeChart muestra los generados contra los admitidos. Solo la
+// llama runRefresher.
+func (s *ParkingScene) updateIntakeChart() {
+    if s.simulation == nil {
+        return
+    }
+    samples := s.simulation.GetIntakeSeries()
+    if len(samples) > 0 {
+        last := samples[len(samples)-1]
+        s.intakeLabel.SetText(fmt.Sprintf("Generados %d · admitidos %d · brecha %d",
+            last.Generated, last.Admitted, last.Generated-last.Admitted))
+    }
+    s.intakeChart.setSamples(samples)
+}
@@ -0,0 +1,104 @@
+package scenes
+
+import (
+    "sync"
+    "fyne.io/fyne/v2"
+)
+
+// resizeLayout apila sus objetos como container.NewStack y avisa cuando
+// cambia el tamaño disponible; Fyne no expone un evento de redimensionado
+// de ventana, pero el contenido raíz se vuelve a distribuir en cada cambio.
+type resizeLayout struct {
+    onResize func(size fyne.Size)
+    last     fyne.Size
+}
+
+func (r *resizeLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+    for _, object := range objects {
+        object.Move(fyne.NewPos(0, 0))
+        object.Resize(size)
+    }
+    if size != r.last {
+        r.last = size
+        if r.onResize != nil {
+            r.onResize(size)
+        }
+    }
+}
+
+func (r *resizeLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+    minSize := fyne.NewSize(0, 0)
+    for _, object := range objects {
+        minSize = minSize.Max(object.MinSize())
+    }
+    return minSize
+}
+
+// stackedBarLayout reparte el ancho entre sus objetos según weights, para
+// dibujar una barra apilada. Los objetos sin peso quedan con ancho cero.
+type stackedBarLayout struct {
+    weights []float64
+    height  float32
+}
+
+func (b *stackedBarLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+    total := 0.0
+    for _, weight := range b.weights {
+        total += weight
+    }
+    x := float32(0)
+    for i, object := range objects {
+        width := float32(0)
+        if total > 0 && i < len(b.weights) {
+            width = size.Width * float32(b.weights[i]/total)
+        }
+        object.Move(fyne.NewPos(x, 0))
+        object.Resize(fyne.NewSize(width, size.Height))
+        x += width
+    }
+}
+
+func (b *stackedBarLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+    return fyne.NewSize(0, b.height)
+}
+
+// barChartLayout dibuja sus objetos como barras verticales de igual ancho y
+// altura proporcional a values, alineadas abajo.
+type barChartLayout struct {
+    mu     sync.Mutex
+    values []float64
+    height float32
+}
+
+func (b *barChartLayout) setValues(values []float64) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    b.values = values
+}
+
+func (b *barChartLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    maxValue := 0.0
+    for _, value := range b.values {
+        if value > maxValue {
+            maxValue = value
+        }
+    }
+    if len(objects) == 0 {
+        return
+    }
+    width := size.Width / float32(len(objects))
+    for i, object := range objects {
+        height := float32(0)
+        if maxValue > 0 && i < len(b.values) {
+            height = size.Height * float32(b.values[i]/maxValue)
+        }
+        object.Move(fyne.NewPos(float32(i)*width+1, size.Height-height))
+        object.Resize(fyne.NewSize(fyne.Max(width-2, 1), height))
+    }
+}
+
+func (b *barChartLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+    return fyne.NewSize(0, b.height)
+}
@@ -0,0 +1,125 @@
+package scenes
+
+import (
+    "fmt"
+    "sync"
+    "time"
+    "fyne.io/fyne/v2"
+)
+
+const (
+    notificationsEnabledKey    = "notificationsEnabled"
+    occupancyAlertThreshold    = 0.9
+    notificationCooldown       = 30 * time.Second
+    mobileNotificationCooldown = 2 * time.Minute
+)
+
+// notifier envía notificaciones del sistema cuando el estacionamiento se
+// llena, cuando la cola llega a su máximo y cuando terminan las llegadas.
+// Solo avisa en la transición, y no repite el mismo tipo de aviso antes de
+// que pase el tiempo de espera.
+type notifier struct {
+    mu        sync.Mutex
+    enabled   bool
+    send      func(*fyne.Notification)
+    cooldown  time.Duration
+    lastSent  map[string]time.Time
+    lotFull   bool
+    queueFull bool
+    alerted   bool
+}
+
+func newNotifier() *notifier {
+    n := &notifier{
+        cooldown: notificationCooldown,
+        lastSent: map[string]time.Time{},
+    }
+    if app := fyne.CurrentApp(); app != nil {
+        n.send = app.SendNotification
+        if app.Driver().Device().IsMobile() {
+            n.cooldown = mobileNotificationCooldown
+        }
+    }
+    return n
+}
+
+// notify debe llamarse con mu tomado.
+func (n *notifier) notify(kind, title, content string) {
+    if !n.enabled || n.send == nil {
+        return
+    }
+    now := time.Now()
+    if last, ok := n.lastSent[kind]; ok && now.Sub(last) < n.cooldown {
+        return
+    }
+    n.lastSent[kind] = now
+    n.send(fyne.NewNotification(title, content))
+}
+
+func (n *notifier) spacesChanged(available int) {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    full := available <= 0
+    if full && !n.lotFull {
+        n.notify("lotFull", "Estacionamiento lleno", "Se ocuparon todos los espacios.")
+    }
+    n.lotFull = full
+}
+
+func (n *notifier) queueChanged(length, max int) {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    full := max > 0 && length >= max
+    if full && !n.queueFull {
+        n.notify("queueFull", "Cola llena", fmt.Sprintf("La cola llegó a su máximo de %d vehículos.", max))
+    }
+    n.queueFull = full
+}
+
+func (n *notifier) occupancyAlert(rate float64) {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    n.alerted = true
+    n.notify("occupancy", "Ocupación alta", fmt.Sprintf("La ocupación llegó al %.0f%%.", rate*100))
+}
+
+// shouldRearm indica si la ocupación ya bajó del umbral después de una
+// alerta, para volver a activarla.
+func (n *notifier) shouldRearm(rate float64) bool {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    if !n.alerted || rate >= occupancyAlertThreshold {
+        return false
+    }
+    n.alerted = false
+    return true
+}
+
+func (n *notifier) finished() {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    n.notify("finished", "Simulación terminada", "Ya llegaron todos los vehículos de la simulación.")
+}
+
+// EnableNotifications activa los avisos del sistema y recuerda la elección.
+func (s *ParkingScene) EnableNotifications() {
+    s.setNotifications(true)
+}
+
+func (s *ParkingScene) DisableNotifications() {
+    s.setNotifications(false)
+}
+
+func (s *ParkingScene) setNotifications(enabled bool) {
+    s.notifier.mu.Lock()
+    s.notifier.enabled = enabled
+    s.notifier.mu.Unlock()
+    if app := fyne.CurrentApp(); app != nil {
+        app.Preferences().SetBool(notificationsEnabledKey, enabled)
+    }
+}
+
+func (s *ParkingScene) handleQueueLength(length int) {
+    s.updateQueueVisual(length)
+    s.notifier.queueChanged(length, s.maxQueueSize)
+}
@@ -0,0 +1,95 @@
+package scenes
+
+import (
+    "reflect"
+    "testing"
+    "time"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/test"
+    "fyne.io/fyne/v2/theme"
+)
+
+func TestNotifierSendsOnTransitions(t *testing.T) {
+    tests := []struct {
+        name     string
+        enabled  bool
+        cooldown time.Duration
+        events   func(n *notifier)
+        want     []string
+    }{
+        {"lote lleno una sola vez", true, 0, func(n *notifier) {
+            n.spacesChanged(1)
+            n.spacesChanged(0)
+            n.spacesChanged(0)
+        }, []string{"Estacionamiento lleno"}},
+        {"lote lleno de nuevo tras liberarse", true, 0, func(n *notifier) {
+            n.spacesChanged(0)
+            n.spacesChanged(1)
+            n.spacesChanged(0)
+        }, []string{"Estacionamiento lleno", "Estacionamiento lleno"}},
+        {"espera entre avisos del mismo tipo", true, time.Hour, func(n *notifier) {
+            n.spacesChanged(0)
+            n.spacesChanged(1)
+            n.spacesChanged(0)
+            n.queueChanged(5, 5)
+        }, []string{"Estacionamiento lleno", "Cola llena"}},
+        {"cola llena", true, 0, func(n *notifier) {
+            n.queueChanged(4, 5)
+            n.queueChanged(5, 5)
+            n.queueChanged(5, 5)
+        }, []string{"Cola llena"}},
+        {"cola sin límite", true, 0, func(n *notifier) {
+            n.queueChanged(10, 0)
+        }, nil},
+        {"fin de la simulación", true, 0, func(n *notifier) {
+            n.finished()
+        }, []string{"Simulación terminada"}},
+        {"desactivadas", false, 0, func(n *notifier) {
+            n.spacesChanged(0)
+            n.queueChanged(5, 5)
+            n.finished()
+        }, nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var got []string
+            n := &notifier{
+                enabled:  tt.enabled,
+                cooldown: tt.cooldown,
+                lastSent: map[string]time.Time{},
+                send: func(notification *fyne.Notification) {
+                    got = append(got, notification.Title)
+                },
+            }
+            tt.events(n)
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("notificaciones = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestNotificationsPreference(t *testing.T) {
+    app := test.NewApp()
+    defer app.Quit()
+    app.Settings().SetTheme(theme.LightTheme())
+    app.Preferences().SetBool(tourCompletedKey, true)
+    window := test.NewWindow(nil)
+    defer window.Close()
+    scene := NewParkingScene(window)
+    defer scene.Close()
+
+    for _, enabled := range []bool{true, false} {
+        if enabled {
+            scene.EnableNotifications()
+        } else {
+            scene.DisableNotifications()
+        }
+        if got := app.Preferences().Bool(notificationsEnabledKey); got != enabled {
+            t.Errorf("preferencia = %v, want %v", got, enabled)
+        }
+        if scene.notifier.enabled != enabled {
+            t.Errorf("notifier.enabled = %v, want %v", scene.notifier.enabled, enabled)
+        }
+    }
+}
@@ -0,0 +1,58 @@
+package scenes
+
+import (
+    "strings"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/theme"
+    "fyne.io/fyne/v2/widget"
+    "holafyne/services"
+)
+
+const (
+    paramChangesEmpty = "Sin cambios"
+    paramChangesShown = 8
+)
+
+// createParamChangesPanel muestra los ajustes hechos en vivo, aparte del log
+// de eventos, con un botón para copiarlos como escenario.
+func (s *ParkingScene) createParamChangesPanel() fyne.CanvasObject {
+    s.changesLabel = widget.NewLabel(paramChangesEmpty)
+    s.changesLabel.TextStyle = fyne.TextStyle{Monospace: true}
+    copyButton := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), s.copyParamChangesScenario)
+    header := container.NewBorder(nil, nil, nil, copyButton,
+        widget.NewLabelWithStyle("📝 Cambios", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+    return container.NewVBox(header, s.changesLabel)
+}
+
+func (s *ParkingScene) handleParamChange(change services.ParamChange) {
+    s.updateParamChanges()
+}
+
+func (s *ParkingScene) updateParamChanges() {
+    changes := s.simulation.GetParamChanges()
+    if len(changes) == 0 {
+        s.changesLabel.SetText(paramChangesEmpty)
+        return
+    }
+    if len(changes) > paramChangesShown {
+        changes = changes[len(changes)-paramChangesShown:]
+    }
+    mapping := s.simulation.GetConfig().TimeMapping
+    lines := make([]string, len(changes))
+    for i, change := range changes {
+        lines[i] = change.Format(mapping)
+    }
+    s.changesLabel.SetText(strings.Join(lines, "\n"))
+}
+
+func (s *ParkingScene) copyParamChangesScenario() {
+    data, err := s.simulation.GetParamChangesScenario("Corrida exploratoria")
+    if err != nil {
+        dialog.ShowError(err, s.window)
+        return
+    }
+    s.window.Clipboard().SetContent(string(data))
+    s.logBox.SetText(s.logBox.Text() + "\n" + "Cambios copiados como escenario")
+}
@@ -1,212 +1,1068 @@
-package scenes
-
-import (
-    "fmt"
-    "image/color"
-    _"time"
-    "strconv"
-    "fyne.io/fyne/v2"
-    "fyne.io/fyne/v2/canvas"
-    "fyne.io/fyne/v2/container"
-    "fyne.io/fyne/v2/widget"
-    "holafyne/services"
-    "fyne.io/fyne/v2/theme"
-)
-
-type ParkingScene struct {
-    window         fyne.Window
-    simulation     *services.Simulation
-    spacesLabel    *widget.Label
-    logBox         *widget.TextGrid
-    startButton    *widget.Button
-    stopButton     *widget.Button
-    spaceIcons     []*canvas.Rectangle
-    carImages      []*canvas.Image
-    queueIcons     []*canvas.Rectangle
-    queueBox       *fyne.Container
-    statsContainer *fyne.Container
-    gameContainer  *fyne.Container
-    maxQueueSize   int
-}
-
-func NewParkingScene(window fyne.Window) *ParkingScene {
-    scene := &ParkingScene{
-        window:      window,
-        spacesLabel: widget.NewLabel("Espacios disponibles: " + strconv.Itoa(services.PARKING_CAPACITY)),
-        logBox:      widget.NewTextGrid(),
-        carImages:   make([]*canvas.Image, services.PARKING_CAPACITY),
-        maxQueueSize: services.MAX_QUEUE_SIZE,
-    }
-    scene.setupUI()
-
-    // Establece el tamaño inicial y fija el tamaño de la ventana
-    scene.window.Resize(fyne.NewSize(100, 168))
-    scene.window.SetFixedSize(true)
-
-    return scene
-}
-
-func (s *ParkingScene) setupUI() {
-    s.window.SetTitle("Parking Game Simulator")
-    s.startButton = widget.NewButtonWithIcon("Iniciar", theme.MediaPlayIcon(), s.handleStart)
-    s.stopButton = widget.NewButtonWithIcon("Detener", theme.MediaStopIcon(), s.handleStop)
-    s.stopButton.Disable()
-    s.statsContainer = container.NewVBox(
-        widget.NewLabelWithStyle("🎮", fyne.TextAlignCenter, fyne.TextStyle{Bold: true, Monospace: true}),
-        widget.NewSeparator(),
-    )
-    s.setupParkingLot()
-    s.queueBox = container.NewHBox()
-    queueLabel := widget.NewLabelWithStyle("🚗 Cola de Espera", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-    queueContainer := container.NewVBox(queueLabel, s.queueBox)
-    controls := container.NewHBox(
-        s.startButton,
-        s.stopButton,
-        widget.NewButtonWithIcon("Limpiar Log", theme.DeleteIcon(), func() {
-            s.logBox.SetText("")
-        }),
-    )
-    infoPanel := container.NewVBox(
-        s.createInfoHeader(),
-        widget.NewSeparator(),
-        s.spacesLabel,
-    )
-    gameArea := container.NewVBox(
-        infoPanel,
-        widget.NewSeparator(),
-        s.gameContainer,
-        widget.NewSeparator(),
-        controls,
-    )
-    rightPanel := container.NewVBox(
-        s.statsContainer,
-        widget.NewSeparator(),
-        queueContainer,
-        widget.NewSeparator(),
-        container.NewScroll(s.logBox),
-    )
-    mainContainer := container.NewHSplit(
-        gameArea,
-        rightPanel,
-    )
-    mainContainer.SetOffset(0.7)
-    s.window.SetContent(mainContainer)
-    s.simulation = services.NewSimulation(s.updateUI)
-    s.simulation.SetQueueUpdateCallback(s.updateQueueVisual)
-}
-
-func (s *ParkingScene) updateQueueVisual(queueSize int) {
-    s.queueBox.Objects = nil
-    s.queueIcons = []*canvas.Rectangle{}
-    for i := 0; i < s.maxQueueSize; i++ {
-        carContainer := container.NewVBox()
-        var car *canvas.Rectangle
-        if i < queueSize {
-            car = canvas.NewRectangle(color.RGBA{0, 100, 255, 255})
-        } else {
-            car = canvas.NewRectangle(color.RGBA{80, 80, 80, 255})
-        }
-        car.SetMinSize(fyne.NewSize(40, 60))
-        carNumber := canvas.NewText(fmt.Sprintf("%d", i+1), color.White)
-        carNumber.TextSize = 16
-        carNumber.TextStyle = fyne.TextStyle{Bold: true}
-        carStack := container.NewStack(car, carNumber)
-        carContainer.Add(carStack)
-        s.queueIcons = append(s.queueIcons, car)
-        s.queueBox.Add(carContainer)
-    }
-    s.queueBox.Refresh()
-}
-
-func (s *ParkingScene) createInfoHeader() fyne.CanvasObject {
-    title := canvas.NewText("🎮 Simulador de Estacionamiento", color.White)
-    title.TextSize = 24
-    title.TextStyle = fyne.TextStyle{Bold: true}
-    return container.NewVBox(
-        container.NewCenter(title),
-    )
-}
-
-func (s *ParkingScene) setupParkingLot() {
-    s.gameContainer = container.NewVBox()
-    background := canvas.NewRectangle(color.RGBA{40, 40, 40, 255})
-    background.SetMinSize(fyne.NewSize(600, 400))
-    parkingContainer := container.NewGridWithColumns(5)
-    s.spaceIcons = make([]*canvas.Rectangle, services.PARKING_CAPACITY)
-    for i := 0; i < services.PARKING_CAPACITY; i++ {
-        space := canvas.NewRectangle(color.RGBA{50, 50, 50, 255})
-        space.SetMinSize(fyne.NewSize(50, 100))
-        s.spaceIcons[i] = space
-        spaceNum := canvas.NewText(fmt.Sprintf("P%d", i+1), color.White)
-        spaceNum.TextSize = 20
-        spaceNum.TextStyle = fyne.TextStyle{Bold: true}
-        spaceContainer := container.NewStack(
-            space,
-            container.NewPadded(spaceNum),
-        )
-        parkingContainer.Add(spaceContainer)
-    }
-    road := s.createRoad()
-    s.gameContainer.Add(parkingContainer)
-    s.gameContainer.Add(road)
-}
-
-func (s *ParkingScene) createRoad() fyne.CanvasObject {
-    road := canvas.NewRectangle(color.RGBA{80, 80, 80, 255})
-    road.SetMinSize(fyne.NewSize(600, 40))
-    lines := container.NewHBox()
-    for i := 0; i < 10; i++ {
-        line := canvas.NewRectangle(color.White)
-        line.SetMinSize(fyne.NewSize(30, 5))
-        lines.Add(line)
-    }
-    return container.NewStack(road, lines)
-}
-
-
-func (s *ParkingScene) handleStart() {
-    s.startButton.Disable()
-    s.stopButton.Enable()
-    go s.simulation.Start()
-}
-
-func (s *ParkingScene) handleStop() {
-    s.stopButton.Disable()
-    s.startButton.Enable()
-    s.simulation.Stop()
-}
-
-func (s *ParkingScene) updateUI(spaces int, message string) {
-    s.spacesLabel.SetText(fmt.Sprintf("🅿️ Espacios disponibles: %d", spaces))
-    s.logBox.SetText(s.logBox.Text() + "\n" + message)
-    for i, space := range s.spaceIcons {
-        if i < services.PARKING_CAPACITY-spaces {
-            space.FillColor = color.RGBA{R: 200, G: 50, B: 50, A: 255}
-        } else {
-            space.FillColor = color.RGBA{R: 50, G: 150, B: 50, A: 255}
-        }
-        space.Refresh()
-    }
-    s.updateQueueBasedOnSpaces(services.PARKING_CAPACITY - spaces)
-}
-
-func (s *ParkingScene) updateQueueBasedOnSpaces(occupiedSpaces int) {
-    s.queueBox.Objects = nil
-    s.queueIcons = []*canvas.Rectangle{}
-    queueLength := 0
-    if occupiedSpaces >= services.PARKING_CAPACITY {
-        queueLength = occupiedSpaces - services.PARKING_CAPACITY + 1
-    }
-    displayQueueLength := queueLength
-    if queueLength > s.maxQueueSize {
-        displayQueueLength = s.maxQueueSize
-    }
-    for i := 0; i < displayQueueLength; i++ {
-        carContainer := container.NewVBox()
-        car := canvas.NewRectangle(color.RGBA{0, 255, 0, 255})
-        car.SetMinSize(fyne.NewSize(40, 60))
-        carContainer.Add(car)
-        s.queueBox.Add(carContainer)
-    }
-    s.queueBox.Refresh()
-}
+package scenes
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "image/color"
+    "log"
+    "math"
+    "sync"
+    "sync/atomic"
+    "time"
+    "strconv"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/canvas"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/widget"
+    "holafyne/models"
+    "holafyne/services"
+    "fyne.io/fyne/v2/theme"
+)
+
+const (
+    parkingColumns  = 5
+    spaceIconWidth  = 50
+    spaceIconHeight = 100
+    spacePadding    = 8
+    controlHeight   = 160
+    roadWidth       = 600
+    minSpaceWidth   = 20
+    gameAreaOffset  = 0.7
+)
+
+// capacityTargetRejection es la tasa de rechazos objetivo de la
+// recomendación de capacidad.
+const capacityTargetRejection = 0.01
+
+// minVisualDwell es lo mínimo que un espacio se ve ocupado, para que las
+// estancias más cortas que un cuadro no pasen desapercibidas.
+const minVisualDwell = 100 * time.Millisecond
+
+// nextPulseInterval es el periodo del borde que parpadea alrededor del
+// próximo vehículo en entrar.
+const nextPulseInterval = 400 * time.Millisecond
+
+// Límites del campo de capacidad de la cola. 0 es sin cola: el que no encuentra
+// espacio se rechaza de inmediato, igual que MaxQueueSize 0.
+const (
+    minQueueCapacity = 0
+    maxQueueCapacity = 100
+)
+
+type ParkingScene struct {
+    window         fyne.Window
+    simulation     *services.Simulation
+    spacesLabel    *widget.Label
+    paramsLabel    *widget.Label
+    stabilityLabel *widget.Label
+    capacityLabel  *widget.Label
+    efficiency     *widget.Label
+    queueingLabel  *widget.Label
+    turnoverLabel  *widget.Label
+    turnoverChart  *fyne.Container
+    turnoverBars   *barChartLayout
+    intakeLabel    *widget.Label
+    intakeChart    *intakeChart
+    changesLabel   *widget.Label
+    gateLabel      *widget.Label
+    gateBroken     atomic.Bool
+    logBox         *widget.TextGrid
+    startButton    *widget.Button
+    stopButton     *widget.Button
+    pauseButton    *widget.Button
+    presetButton   *widget.Button
+    spaceIcons     []*canvas.Rectangle
+    spaceLabels    []*canvas.Text
+    blockedMarks   []*canvas.Raster
+    carImages      []*canvas.Image
+    infoDefaults   []fyne.CanvasObject
+    queueIcons     []*canvas.Rectangle
+    queueIconsMu   sync.Mutex
+    queueBox       *fyne.Container
+    statsContainer *fyne.Container
+    gameContainer  *fyne.Container
+    background     *fyne.Container
+    parkingGrid    *fyne.Container
+    capacity       int
+    maxQueueSize   int
+    queueDetail    *QueueDetailPanel
+    minSizeRect    *canvas.Rectangle
+    spaceSize      fyne.Size
+    fixedSpaceSize bool
+    fitting        bool
+    tour           *TourMode
+    queueDebug     bool
+    vehicleLogPath string
+    entryClosed    bool
+    queueOutside   bool
+    entryBarrier   *canvas.Rectangle
+    queueCapacity  *widget.Entry
+    stateBar       *fyne.Container
+    stateLayout    *stackedBarLayout
+    stateLabel     *widget.Label
+    paintedAt      []time.Time
+    dwellPending   bool
+    dwellMu        sync.Mutex
+    nextPosition   int
+    pulseOn        bool
+    road           fyne.CanvasObject
+    pathLayer      *fyne.Container
+    trajectories   map[int][]*canvas.Line
+    trajectoryMu   sync.Mutex
+    occupants      []*models.Vehicle
+    TrajectoryEnabled bool
+    notifier       *notifier
+    iconProvider   func(v *models.Vehicle) fyne.Resource
+    iconMu         sync.Mutex
+    ab             *abMode
+    abButton       *widget.Button
+    speedSelect    *widget.Select
+    done           chan struct{}
+    closeOnce      sync.Once
+    refresh        chan struct{}
+}
+
+func NewParkingScene(window fyne.Window) *ParkingScene {
+    config := services.DefaultConfig()
+    scene := &ParkingScene{
+        window:      window,
+        spacesLabel: widget.NewLabel("Espacios disponibles: " + strconv.Itoa(config.ParkingCapacity)),
+        paramsLabel: widget.NewLabel(""),
+        stabilityLabel: widget.NewLabel(""),
+        capacityLabel: widget.NewLabel(""),
+        queueingLabel: widget.NewLabel(""),
+        gateLabel:   widget.NewLabel(""),
+        efficiency:  widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+        logBox:      widget.NewTextGrid(),
+        maxQueueSize: config.MaxQueueSize,
+        spaceSize:   fyne.NewSize(spaceIconWidth, spaceIconHeight),
+        capacity:    config.ParkingCapacity,
+        nextPosition: -1,
+        trajectories: make(map[int][]*canvas.Line),
+        notifier:    newNotifier(),
+        done:        make(chan struct{}),
+        refresh:     make(chan struct{}, 1),
+    }
+    scene.setupUI()
+    scene.ApplyConfig(config)
+    go scene.pulseNextIcon()
+    go scene.runRefresher()
+    scene.setupScenarioMenu()
+
+    if app := fyne.CurrentApp(); app != nil && !app.Preferences().Bool(tourCompletedKey) {
+        scene.tour = StartTour(scene)
+    }
+
+    return scene
+}
+
+func computeMinWindowSize(capacity, columns int, iconWidth, iconHeight float32) fyne.Size {
+    rows := (capacity + columns - 1) / columns
+    width := fyne.Max(float32(columns)*(iconWidth+spacePadding), roadWidth)
+    height := float32(rows)*(iconHeight+spacePadding) + controlHeight
+    return fyne.NewSize(width, height)
+}
+
+func (s *ParkingScene) SetMinWindowSize(width, height float32) {
+    s.minSizeRect.SetMinSize(fyne.NewSize(width, height))
+    s.minSizeRect.Refresh()
+
+    current := s.window.Canvas().Size()
+    if current.Width < width || current.Height < height {
+        s.window.Resize(fyne.NewSize(fyne.Max(current.Width, width), fyne.Max(current.Height, height)))
+    }
+}
+
+// SetParkingSpaceSize fija el tamaño de los espacios. Desde entonces al
+// redimensionar la ventana ya no se ajustan solos.
+func (s *ParkingScene) SetParkingSpaceSize(width, height float32) {
+    s.fixedSpaceSize = true
+    s.applySpaceSize(fyne.NewSize(width, height))
+    minSize := computeMinWindowSize(s.capacity, parkingColumns, width, height)
+    s.SetMinWindowSize(minSize.Width, minSize.Height)
+}
+
+func (s *ParkingScene) applySpaceSize(size fyne.Size) {
+    s.spaceSize = size
+    for _, icon := range s.spaceIcons {
+        icon.SetMinSize(size)
+    }
+    s.gameContainer.Refresh()
+}
+
+// AutoFitParkingSpaces ajusta los espacios para que la cuadrícula completa
+// quepa en el área dada, conservando la proporción 1:2 de los íconos. No
+// modifica el mínimo de la ventana para que siempre pueda volver a achicarse.
+func (s *ParkingScene) AutoFitParkingSpaces(availableWidth, availableHeight float32) {
+    rows := (s.capacity + parkingColumns - 1) / parkingColumns
+    if rows == 0 {
+        return
+    }
+    width := availableWidth/float32(parkingColumns) - spacePadding
+    height := (availableHeight-controlHeight)/float32(rows) - spacePadding
+
+    width = fyne.Min(width, height*spaceIconWidth/spaceIconHeight)
+    width = fyne.Max(width, minSpaceWidth)
+    s.applySpaceSize(fyne.NewSize(width, width*spaceIconHeight/spaceIconWidth))
+}
+
+// handleResize ajusta los espacios al nuevo tamaño de la ventana, salvo que
+// se hayan fijado con SetParkingSpaceSize.
+func (s *ParkingScene) handleResize(size fyne.Size) {
+    if s.fitting || s.fixedSpaceSize || s.gameContainer == nil {
+        return
+    }
+    s.fitting = true
+    defer func() { s.fitting = false }()
+    s.AutoFitParkingSpaces(size.Width*gameAreaOffset, size.Height)
+}
+
+// EnableQueueDebug activa la comparación de colas en la simulación actual y
+// en las que se creen después, y abre la ventana que las muestra.
+func (s *ParkingScene) EnableQueueDebug() {
+    s.queueDebug = true
+    s.simulation.SetQueueDebug(true)
+    ShowQueueDebugOverlay(s)
+    s.setupScenarioMenu()
+}
+
+// EnableVehicleLog hace que esta y las siguientes simulaciones anexen a path una
+// fila por vehículo que termina su recorrido.
+func (s *ParkingScene) EnableVehicleLog(path string) error {
+    s.vehicleLogPath = path
+    config := s.simulation.GetConfig()
+    config.VehicleLogPath = path
+    return s.ApplyConfig(config)
+}
+
+// validateParking revisa la consistencia del estacionamiento y registra en el
+// log cada problema encontrado.
+func (s *ParkingScene) validateParking() {
+    errs := s.simulation.ValidateParking()
+    if len(errs) == 0 {
+        s.logBox.SetText(s.logBox.Text() + "\n" + "Validación: sin problemas")
+        return
+    }
+    for _, err := range errs {
+        log.Printf("error de consistencia: %v", err)
+        s.logBox.SetText(s.logBox.Text() + "\n" + "Error de consistencia: " + err.Error())
+    }
+}
+
+func (s *ParkingScene) GetSimulation() *services.Simulation {
+    return s.simulation
+}
+
+// Close detiene las goroutines de la escena. No detiene la simulación.
+func (s *ParkingScene) Close() {
+    s.closeOnce.Do(func() { close(s.done) })
+}
+
+func (s *ParkingScene) setupUI() {
+    s.window.SetTitle("Parking Game Simulator")
+    s.startButton = widget.NewButtonWithIcon("Iniciar", theme.MediaPlayIcon(), s.handleStart)
+    s.stopButton = widget.NewButtonWithIcon("Detener", theme.MediaStopIcon(), s.handleStop)
+    s.stopButton.Disable()
+    s.pauseButton = widget.NewButtonWithIcon("Pausar", theme.MediaPauseIcon(), s.handlePause)
+    s.pauseButton.Disable()
+    s.statsContainer = container.NewVBox(
+        widget.NewLabelWithStyle("🎮", fyne.TextAlignCenter, fyne.TextStyle{Bold: true, Monospace: true}),
+        widget.NewSeparator(),
+        s.efficiency,
+        s.stabilityLabel,
+        s.capacityLabel,
+        s.queueingLabel,
+        s.gateLabel,
+    )
+    s.SetQueueCapacitySpinner()
+    s.setupStateBar()
+    s.setupTurnoverChart()
+    s.setupIntakeChart()
+    s.setupParkingLot()
+    s.queueBox = container.NewHBox()
+    queueLabel := widget.NewLabelWithStyle("🚗 Cola de Espera", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+    s.queueDetail = NewQueueDetailPanel()
+    s.queueDetail.SetLongestWaitingCallback(s.highlightQueueIcon)
+    s.queueDetail.SetLengthCallback(s.handleQueueLength)
+    s.queueDetail.SetNextCallback(s.setNextInQueue)
+    s.queueDetail.SetLagCallback(s.handleUILag)
+    queueContainer := container.NewVBox(queueLabel, s.queueBox, s.queueDetail.Container())
+    controls := container.NewHBox(
+        s.startButton,
+        s.pauseButton,
+        s.stopButton,
+        widget.NewButtonWithIcon("Limpiar Log", theme.DeleteIcon(), func() {
+            s.logBox.SetText("")
+        }),
+        widget.NewButtonWithIcon("Reiniciar estadísticas", theme.ViewRefreshIcon(), s.handleResetStatistics),
+        widget.NewButtonWithIcon("Tour", theme.QuestionIcon(), s.handleTour),
+        widget.NewButtonWithIcon("Generar paquete de diagnóstico", theme.DocumentSaveIcon(), s.handleDiagnosticBundle),
+    )
+    s.presetButton = widget.NewButtonWithIcon("Presets", theme.SettingsIcon(), s.ShowConfigPresetMenu)
+    controls.Add(s.presetButton)
+    notifications := widget.NewCheck("Notificaciones", func(enabled bool) {
+        s.setNotifications(enabled)
+    })
+    if app := fyne.CurrentApp(); app != nil {
+        notifications.SetChecked(app.Preferences().Bool(notificationsEnabledKey))
+    }
+    controls.Add(notifications)
+    queueOutside := widget.NewCheck("Cola afuera", func(enabled bool) {
+        s.queueOutside = enabled
+        s.applyEntrance()
+    })
+    controls.Add(widget.NewCheck("Cerrar entrada", func(closed bool) {
+        s.entryClosed = closed
+        s.applyEntrance()
+    }))
+    controls.Add(queueOutside)
+    controls.Add(s.createBackgroundSelect())
+    controls.Add(s.createSpeedSelect())
+    s.abButton = widget.NewButtonWithIcon("Ver B", theme.ViewRefreshIcon(), s.toggleAB)
+    s.abButton.Hide()
+    controls.Add(s.abButton)
+    infoPanel := container.NewVBox(
+        s.createInfoHeader(),
+        widget.NewSeparator(),
+        s.spacesLabel,
+        s.paramsLabel,
+    )
+    gameArea := container.NewVBox(
+        infoPanel,
+        widget.NewSeparator(),
+        container.NewStack(s.background, s.gameContainer, s.pathLayer),
+        widget.NewSeparator(),
+        controls,
+    )
+    rightPanel := container.NewVBox(
+        s.statsContainer,
+        widget.NewSeparator(),
+        queueContainer,
+        widget.NewSeparator(),
+        s.createParamChangesPanel(),
+        widget.NewSeparator(),
+        container.NewScroll(s.logBox),
+    )
+    mainContainer := container.NewHSplit(
+        gameArea,
+        rightPanel,
+    )
+    mainContainer.SetOffset(gameAreaOffset)
+    s.minSizeRect = canvas.NewRectangle(color.Transparent)
+    s.window.SetContent(container.New(&resizeLayout{onResize: s.handleResize}, s.minSizeRect, mainContainer))
+}
+
+// ApplyConfig reemplaza la simulación por una nueva con la configuración dada
+// y reconstruye la cuadrícula. Si había una simulación en curso, se detiene.
+func (s *ParkingScene) ApplyConfig(config services.SimulationConfig) error {
+    if err := config.Validate(); err != nil {
+        return err
+    }
+
+    s.stopSimulations()
+    s.leaveABMode()
+
+    if config.VehicleLogPath == "" {
+        config.VehicleLogPath = s.vehicleLogPath
+    }
+    s.attachSimulation(services.NewSimulationWithConfig(config, s.updateUI))
+    s.startButton.Enable()
+    s.stopButton.Disable()
+    s.resetPauseButton()
+    return nil
+}
+
+// attachSimulation muestra sim en la escena: arma la cuadrícula según su
+// capacidad y le conecta los callbacks de la interfaz.
+func (s *ParkingScene) attachSimulation(sim *services.Simulation) {
+    s.queueDetail.Unsubscribe()
+    config := sim.GetConfig()
+    s.capacity = config.ParkingCapacity
+    s.maxQueueSize = config.MaxQueueSize
+    s.queueCapacity.SetText(strconv.Itoa(config.MaxQueueSize))
+    s.rebuildParkingGrid()
+
+    // El tamaño mínimo se calcula a partir de la cuadrícula de espacios
+    minSize := computeMinWindowSize(s.capacity, parkingColumns, s.spaceSize.Width, s.spaceSize.Height)
+    s.SetMinWindowSize(minSize.Width, minSize.Height)
+
+    s.simulation = sim
+    s.simulation.SetQueueUpdateCallback(s.updateQueueVisual)
+    s.simulation.SetSpaceLabelCallback(s.updateSpaceLabel)
+    s.simulation.SetDoubleParkingCallback(s.updateBlockedSpace)
+    s.simulation.SetQueueDebug(s.queueDebug)
+    s.simulation.SetFinishedCallback(s.handleFinished)
+    s.simulation.SetParamChangeCallback(s.handleParamChange)
+    s.simulation.SetGateFailureCallback(s.handleGateFailure)
+    s.gateBroken.Store(false)
+    s.simulation.SetOccupancyAlertThreshold(occupancyAlertThreshold, s.notifier.occupancyAlert)
+    s.queueDetail.Subscribe(s.simulation)
+    services.ReplaceExpvarSimulation(s.simulation)
+    s.applyEntrance()
+    s.showSpeed()
+
+    s.renderInitialState()
+}
+
+// detachSimulation desconecta sim de la escena sin detenerla.
+func (s *ParkingScene) detachSimulation(sim *services.Simulation) {
+    sim.SetQueueUpdateCallback(nil)
+    sim.SetSpaceLabelCallback(nil)
+    sim.SetDoubleParkingCallback(nil)
+    sim.SetFinishedCallback(nil)
+    sim.SetParamChangeCallback(nil)
+    sim.SetGateFailureCallback(nil)
+    sim.SetOccupancyAlertThreshold(occupancyAlertThreshold, nil)
+}
+
+// stopSimulations detiene la simulación visible y, en modo A/B, también la
+// otra.
+func (s *ParkingScene) stopSimulations() {
+    for _, sim := range s.runningSimulations() {
+        if sim.IsRunning() {
+            sim.Stop()
+        }
+    }
+}
+
+func (s *ParkingScene) runningSimulations() []*services.Simulation {
+    if s.ab != nil {
+        return s.ab.sims[:]
+    }
+    if s.simulation != nil {
+        return []*services.Simulation{s.simulation}
+    }
+    return nil
+}
+
+// renderInitialState dibuja el estado actual de la simulación sin esperar
+// al primer evento, para que la escena se vea igual al crearse, al cambiar
+// de configuración y al reiniciar estadísticas.
+func (s *ParkingScene) renderInitialState() {
+    available := s.simulation.GetAvailableSpaces()
+    s.spacesLabel.SetText(fmt.Sprintf("🅿️ Espacios disponibles: %d", available))
+    s.paintSpaces(available)
+    s.updateQueueVisual(s.simulation.GetQueueLength())
+    s.updateStability()
+    s.updateCapacityAdvice()
+    s.updateStateBar()
+    s.updateEfficiency()
+    s.updateQueueingParams()
+    s.requestRefresh()
+    s.updateParamChanges()
+    s.updateGateDowntime()
+    s.updateParams()
+}
+
+// updateParams muestra los parámetros de la corrida. Con control de
+// ocupación, λ es el que fijó el control en el último ajuste.
+func (s *ParkingScene) updateParams() {
+    config := s.simulation.GetConfig()
+    rate := fmt.Sprintf("λ = %.2f veh/s", s.simulation.GetArrivalRate())
+    if control := config.OccupancyControl; control.IsSet() {
+        rate += fmt.Sprintf(" (control, objetivo %.0f%%)", control.Target*100)
+    }
+    s.paramsLabel.SetText(fmt.Sprintf("Capacidad: %d · %s · Estancia: %.0f–%.0f s · Cola máx.: %d",
+        config.ParkingCapacity, rate, config.MinParkTime, config.MaxParkTime, s.maxQueueSize))
+}
+
+// ShowConfigPresetMenu muestra junto al botón Presets las configuraciones
+// predefinidas. Si la simulación está corriendo, pide confirmación antes de
+// reemplazarla.
+func (s *ParkingScene) ShowConfigPresetMenu() {
+    items := []*fyne.MenuItem{}
+    for _, name := range services.PresetNames() {
+        name := name
+        items = append(items, fyne.NewMenuItem(name, func() {
+            s.selectPreset(name)
+        }))
+    }
+    menu := fyne.NewMenu("Presets", items...)
+    widget.ShowPopUpMenuAtRelativePosition(menu, s.window.Canvas(), fyne.NewPos(0, s.presetButton.Size().Height), s.presetButton)
+}
+
+func (s *ParkingScene) selectPreset(name string) {
+    apply := func() {
+        presets, err := services.LoadPresets()
+        if err != nil {
+            dialog.ShowError(err, s.window)
+            return
+        }
+        if err := s.ApplyConfig(presets[name]); err != nil {
+            dialog.ShowError(err, s.window)
+            return
+        }
+        s.logBox.SetText(s.logBox.Text() + "\n" + "Preset aplicado: " + name)
+    }
+    if !s.simulation.IsRunning() {
+        apply()
+        return
+    }
+    dialog.ShowConfirm("Presets", "La simulación está en curso y se detendrá. ¿Aplicar \""+name+"\"?", func(confirmed bool) {
+        if confirmed {
+            apply()
+        }
+    }, s.window)
+}
+
+func (s *ParkingScene) setupScenarioMenu() {
+    scenarios, err := services.LoadScenarios()
+    if err != nil {
+        dialog.ShowError(err, s.window)
+        return
+    }
+
+    items := make([]*fyne.MenuItem, 0, len(scenarios))
+    for _, scenario := range scenarios {
+        scenario := scenario
+        items = append(items, fyne.NewMenuItem(scenario.Title, func() {
+            s.showScenario(scenario)
+        }))
+    }
+    items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem("Modo A/B…", s.showABDialog))
+    menus := []*fyne.Menu{fyne.NewMenu("Escenarios", items...)}
+    if s.queueDebug {
+        menus = append(menus, fyne.NewMenu("Depuración",
+            fyne.NewMenuItem("Validar estacionamiento", s.validateParking),
+            fyne.NewMenuItem("Latencia de la interfaz", func() { ShowUILatencyOverlay(s) }),
+        ))
+    }
+    s.window.SetMainMenu(fyne.NewMainMenu(menus...))
+}
+
+func (s *ParkingScene) showScenario(scenario services.Scenario) {
+    dialog.ShowConfirm(scenario.Title, scenario.Description+"\n\n¿Cargar este escenario?", func(confirmed bool) {
+        if !confirmed {
+            return
+        }
+        if err := s.ApplyConfig(scenario.Config); err != nil {
+            dialog.ShowError(err, s.window)
+            return
+        }
+        s.logBox.SetText(s.logBox.Text() + "\n" + "Escenario cargado: " + scenario.Title)
+    }, s.window)
+}
+
+// SetQueueCapacitySpinner agrega al panel de estadísticas el campo que
+// cambia la capacidad de la cola en caliente.
+func (s *ParkingScene) SetQueueCapacitySpinner() {
+    s.queueCapacity = widget.NewEntry()
+    s.queueCapacity.Validator = func(text string) error {
+        n, err := strconv.Atoi(text)
+        if err != nil {
+            return errors.New("debe ser un número entero")
+        }
+        if n < minQueueCapacity || n > maxQueueCapacity {
+            return fmt.Errorf("debe estar entre %d y %d", minQueueCapacity, maxQueueCapacity)
+        }
+        return nil
+    }
+    s.queueCapacity.OnChanged = func(text string) {
+        if s.simulation == nil || s.queueCapacity.Validator(text) != nil {
+            return
+        }
+        n, _ := strconv.Atoi(text)
+        if n == s.maxQueueSize {
+            return
+        }
+        if err := s.simulation.SetQueueCapacity(n); err != nil {
+            return
+        }
+        s.maxQueueSize = n
+        s.renderInitialState()
+    }
+    s.statsContainer.Add(container.NewBorder(nil, nil, widget.NewLabel("Capacidad cola:"), nil, s.queueCapacity))
+}
+
+func (s *ParkingScene) updateQueueVisual(queueSize int) {
+    s.queueIconsMu.Lock()
+    defer s.queueIconsMu.Unlock()
+    s.queueBox.Objects = nil
+    s.queueIcons = []*canvas.Rectangle{}
+    for i := 0; i < s.maxQueueSize; i++ {
+        carContainer := container.NewVBox()
+        var car *canvas.Rectangle
+        if i < queueSize {
+            car = canvas.NewRectangle(color.RGBA{0, 100, 255, 255})
+        } else {
+            car = canvas.NewRectangle(color.RGBA{80, 80, 80, 255})
+        }
+        car.SetMinSize(fyne.NewSize(40, 60))
+        carNumber := canvas.NewText(fmt.Sprintf("%d", i+1), color.White)
+        carNumber.TextSize = 16
+        carNumber.TextStyle = fyne.TextStyle{Bold: true}
+        carStack := container.NewStack(car, carNumber)
+        carContainer.Add(carStack)
+        s.queueIcons = append(s.queueIcons, car)
+        s.queueBox.Add(carContainer)
+    }
+    // Los íconos son nuevos: hay que volver a marcar al siguiente.
+    s.paintNextIcon()
+    s.queueBox.Refresh()
+}
+
+func (s *ParkingScene) setNextInQueue(position int) {
+    s.queueIconsMu.Lock()
+    defer s.queueIconsMu.Unlock()
+    s.nextPosition = position
+    s.paintNextIcon()
+}
+
+// pulseNextIcon alterna el grosor del borde del próximo vehículo en entrar,
+// hasta que se cierra la escena.
+func (s *ParkingScene) pulseNextIcon() {
+    ticker := time.NewTicker(nextPulseInterval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-s.done:
+            return
+        case <-ticker.C:
+        }
+        s.queueIconsMu.Lock()
+        s.pulseOn = !s.pulseOn
+        pulseOn := s.pulseOn
+        s.paintNextIcon()
+        s.queueIconsMu.Unlock()
+        s.blinkBarrier(pulseOn)
+    }
+}
+
+// paintNextIcon debe llamarse con queueIconsMu tomado.
+func (s *ParkingScene) paintNextIcon() {
+    for i, icon := range s.queueIcons {
+        width := float32(0)
+        if i == s.nextPosition {
+            width = 2
+            if s.pulseOn {
+                width = 4
+            }
+        }
+        if icon.StrokeWidth != width {
+            icon.StrokeColor = color.White
+            icon.StrokeWidth = width
+            icon.Refresh()
+        }
+    }
+}
+
+func (s *ParkingScene) highlightQueueIcon(position int) {
+    s.queueIconsMu.Lock()
+    defer s.queueIconsMu.Unlock()
+    if position < 0 || position >= len(s.queueIcons) {
+        return
+    }
+    icon := s.queueIcons[position]
+    icon.FillColor = color.RGBA{255, 200, 0, 255}
+    icon.Refresh()
+}
+
+// blockedStripes dibuja franjas diagonales amarillas sobre un espacio
+// bloqueado por doble estacionamiento.
+func blockedStripes(x, y, w, h int) color.Color {
+    if (x+y)/8%2 == 0 {
+        return color.RGBA{230, 200, 0, 200}
+    }
+    return color.Transparent
+}
+
+func (s *ParkingScene) updateBlockedSpace(spaceID int, blocked bool) {
+    if spaceID < 0 || spaceID >= len(s.blockedMarks) {
+        return
+    }
+    if blocked {
+        s.blockedMarks[spaceID].Show()
+    } else {
+        s.blockedMarks[spaceID].Hide()
+    }
+}
+
+func (s *ParkingScene) updateSpaceLabel(spaceID int, label string) {
+    if spaceID < 0 || spaceID >= len(s.spaceLabels) {
+        return
+    }
+    s.spaceLabels[spaceID].Text = label
+    s.spaceLabels[spaceID].Refresh()
+}
+
+func (s *ParkingScene) createInfoHeader() fyne.CanvasObject {
+    title := canvas.NewText("🎮 Simulador de Estacionamiento", color.White)
+    title.TextSize = 24
+    title.TextStyle = fyne.TextStyle{Bold: true}
+    return container.NewVBox(
+        container.NewCenter(title),
+    )
+}
+
+func (s *ParkingScene) setupParkingLot() {
+    s.gameContainer = container.NewVBox()
+    s.parkingGrid = container.NewGridWithColumns(parkingColumns)
+    s.road = s.createRoad()
+    s.pathLayer = container.NewWithoutLayout()
+    s.setupBackground()
+    s.gameContainer.Add(s.parkingGrid)
+    s.gameContainer.Add(s.road)
+}
+
+func (s *ParkingScene) rebuildParkingGrid() {
+    s.parkingGrid.Objects = nil
+    s.spaceIcons = make([]*canvas.Rectangle, s.capacity)
+    s.spaceLabels = make([]*canvas.Text, s.capacity)
+    s.blockedMarks = make([]*canvas.Raster, s.capacity)
+    carImages := make([]*canvas.Image, s.capacity)
+    s.dwellMu.Lock()
+    s.paintedAt = make([]time.Time, s.capacity)
+    s.dwellMu.Unlock()
+    for i := 0; i < s.capacity; i++ {
+        space := canvas.NewRectangle(color.RGBA{50, 50, 50, 255})
+        space.SetMinSize(s.spaceSize)
+        s.spaceIcons[i] = space
+        spaceNum := canvas.NewText(fmt.Sprintf("P%d", i+1), color.White)
+        spaceNum.TextSize = 20
+        spaceNum.TextStyle = fyne.TextStyle{Bold: true}
+        s.spaceLabels[i] = spaceNum
+        blocked := canvas.NewRasterWithPixels(blockedStripes)
+        blocked.Hide()
+        s.blockedMarks[i] = blocked
+        car := canvas.NewImageFromResource(nil)
+        car.FillMode = canvas.ImageFillContain
+        car.Hide()
+        carImages[i] = car
+        spaceContainer := container.NewStack(
+            space,
+            blocked,
+            container.NewPadded(car),
+            container.NewPadded(spaceNum),
+        )
+        s.parkingGrid.Add(spaceContainer)
+    }
+    if s.capacity == 0 {
+        s.parkingGrid.Add(widget.NewLabel("Sin espacios: solo cola"))
+    }
+    s.iconMu.Lock()
+    s.carImages = carImages
+    s.iconMu.Unlock()
+    s.parkingGrid.Refresh()
+}
+
+func (s *ParkingScene) createRoad() fyne.CanvasObject {
+    road := canvas.NewRectangle(color.RGBA{80, 80, 80, 255})
+    road.SetMinSize(fyne.NewSize(roadWidth, 40))
+    lines := container.NewHBox()
+    for i := 0; i < 10; i++ {
+        line := canvas.NewRectangle(color.White)
+        line.SetMinSize(fyne.NewSize(30, 5))
+        lines.Add(line)
+    }
+    s.entryBarrier = canvas.NewRectangle(color.RGBA{R: 220, G: 40, B: 40, A: 255})
+    s.entryBarrier.SetMinSize(fyne.NewSize(12, 40))
+    s.entryBarrier.Hide()
+    return container.NewStack(road, lines, container.NewHBox(s.entryBarrier))
+}
+
+// applyEntrance lleva el estado de los controles de la entrada a la
+// simulación actual y muestra u oculta la barrera.
+func (s *ParkingScene) applyEntrance() {
+    if s.simulation == nil {
+        return
+    }
+    if s.entryClosed {
+        s.simulation.CloseEntrance(s.queueOutside)
+        s.entryBarrier.Show()
+    } else {
+        s.simulation.OpenEntrance()
+        s.entryBarrier.Hide()
+    }
+}
+
+
+func (s *ParkingScene) handleStart() {
+    s.restartABMode()
+    s.startButton.Disable()
+    s.stopButton.Enable()
+    s.pauseButton.Enable()
+    for _, sim := range s.runningSimulations() {
+        go s.startWithMonitor(sim)
+    }
+}
+
+func (s *ParkingScene) handleStop() {
+    s.stopButton.Disable()
+    s.startButton.Enable()
+    s.resetPauseButton()
+    for _, sim := range s.runningSimulations() {
+        sim.Stop()
+    }
+}
+
+// handlePause alterna entre pausar y reanudar. En modo A/B las dos
+// simulaciones se pausan juntas.
+func (s *ParkingScene) handlePause() {
+    paused := !s.simulation.IsPaused()
+    for _, sim := range s.runningSimulations() {
+        if paused {
+            sim.Pause()
+        } else {
+            sim.Resume()
+        }
+    }
+    s.showPaused(paused)
+    if paused {
+        s.logBox.SetText(s.logBox.Text() + "\n" + s.clockPrefix() + "Simulación en pausa")
+    } else {
+        s.logBox.SetText(s.logBox.Text() + "\n" + s.clockPrefix() + "Simulación reanudada")
+    }
+}
+
+func (s *ParkingScene) showPaused(paused bool) {
+    if paused {
+        s.pauseButton.SetText("Reanudar")
+        s.pauseButton.SetIcon(theme.MediaPlayIcon())
+    } else {
+        s.pauseButton.SetText("Pausar")
+        s.pauseButton.SetIcon(theme.MediaPauseIcon())
+    }
+}
+
+func (s *ParkingScene) resetPauseButton() {
+    s.showPaused(false)
+    s.pauseButton.Disable()
+}
+
+func (s *ParkingScene) handleFinished() {
+    s.logBox.SetText(s.logBox.Text() + "\n" + "Llegaron todos los vehículos de la simulación")
+    s.notifier.finished()
+    if s.queueDebug {
+        s.validateParking()
+    }
+    s.showRunSummary()
+}
+
+func (s *ParkingScene) handleTour() {
+    if s.tour != nil {
+        s.tour.StopTour()
+    }
+    s.tour = StartTour(s)
+}
+
+func (s *ParkingScene) handleResetStatistics() {
+    dialog.ShowConfirm(
+        "Reiniciar estadísticas",
+        "¿Poner en cero las estadísticas? La ocupación y la cola actuales se conservan.",
+        func(confirmed bool) {
+            if confirmed {
+                s.simulation.ResetStatistics()
+                s.renderInitialState()
+            }
+        },
+        s.window,
+    )
+}
+
+// clockPrefix antepone al log la hora del reloj ficticio, si la
+// configuración define uno.
+func (s *ParkingScene) clockPrefix() string {
+    if !s.simulation.GetConfig().TimeMapping.IsSet() {
+        return ""
+    }
+    return "[" + s.simulation.FormatElapsed(s.simulation.GetElapsed()) + "] "
+}
+
+func (s *ParkingScene) updateUI(spaces int, message string) {
+    s.spacesLabel.SetText(fmt.Sprintf("🅿️ Espacios disponibles: %d", spaces))
+    s.logBox.SetText(s.logBox.Text() + "\n" + s.clockPrefix() + message)
+    s.paintSpaces(spaces)
+    s.updateStability()
+    s.updateCapacityAdvice()
+    s.updateStateBar()
+    s.updateEfficiency()
+    s.updateQueueingParams()
+    if s.simulation.GetConfig().OccupancyControl.IsSet() {
+        s.updateParams()
+    }
+    s.requestRefresh()
+    s.notifier.spacesChanged(spaces)
+    if s.capacity > 0 && s.notifier.shouldRearm(float64(s.capacity-spaces)/float64(s.capacity)) {
+        // updateUI se llama con el lock del estacionamiento tomado
+        go s.simulation.ClearOccupancyAlert()
+    }
+    if s.TrajectoryEnabled {
+        go s.detectEntries()
+    }
+}
+
+func (s *ParkingScene) updateStability() {
+    if s.simulation == nil {
+        return
+    }
+    rho := s.simulation.GetServerUtilization()
+    if math.IsNaN(rho) {
+        s.stabilityLabel.SetText("Sistema: n/a (sin espacios)")
+    } else if s.simulation.IsStable() {
+        s.stabilityLabel.SetText(fmt.Sprintf("Sistema: Estable ✅ (ρ = %.2f)", rho))
+    } else {
+        s.stabilityLabel.SetText(fmt.Sprintf("Sistema: Sobrecargado ❌ (ρ = %.2f)", rho))
+    }
+}
+
+// stateColors son los colores de cada estado en la barra de tiempo por estado.
+var stateColors = map[models.VehicleState]color.Color{
+    models.Waiting:  color.RGBA{R: 0, G: 100, B: 255, A: 255},
+    models.Entering: color.RGBA{R: 255, G: 200, B: 0, A: 255},
+    models.Parked:   color.RGBA{R: 200, G: 50, B: 50, A: 255},
+    models.Exiting:  color.RGBA{R: 50, G: 150, B: 50, A: 255},
+}
+
+func (s *ParkingScene) setupStateBar() {
+    s.stateLayout = &stackedBarLayout{height: 16}
+    s.stateBar = container.New(s.stateLayout)
+    for _, state := range models.VehicleStates {
+        s.stateBar.Add(canvas.NewRectangle(stateColors[state]))
+    }
+    s.stateLabel = widget.NewLabel("")
+    s.statsContainer.Add(s.stateBar)
+    s.statsContainer.Add(s.stateLabel)
+}
+
+// updateStateBar muestra el tiempo medio por estado: espera (azul), entrada
+// (amarillo), estancia (rojo) y salida (verde).
+func (s *ParkingScene) updateStateBar() {
+    if s.simulation == nil {
+        return
+    }
+    means := s.simulation.GetMeanTimeInState()
+    s.stateLayout.weights = s.stateLayout.weights[:0]
+    for _, state := range models.VehicleStates {
+        s.stateLayout.weights = append(s.stateLayout.weights, means[state].Seconds())
+    }
+    s.stateLabel.SetText(fmt.Sprintf("Tiempo por estado: espera %.1f s · entrada %.1f s · estancia %.1f s · salida %.1f s",
+        means[models.Waiting].Seconds(), means[models.Entering].Seconds(),
+        means[models.Parked].Seconds(), means[models.Exiting].Seconds()))
+    s.stateBar.Refresh()
+}
+
+func (s *ParkingScene) setupTurnoverChart() {
+    s.turnoverLabel = widget.NewLabel("")
+    s.turnoverBars = &barChartLayout{height: 40}
+    s.turnoverChart = container.New(s.turnoverBars)
+    s.statsContainer.Add(s.turnoverLabel)
+    s.statsContainer.Add(s.turnoverChart)
+}
+
+// requestRefresh pide a runRefresher que vuelva a dibujar lo que consulta al
+// estacionamiento. Se puede llamar con el lock del estacionamiento tomado;
+// varias peticiones seguidas se juntan en una.
+func (s *ParkingScene) requestRefresh() {
+    select {
+    case s.refresh <- struct{}{}:
+    default:
+    }
+}
+
+// runRefresher es la única goroutine que actualiza las gráficas de rotación
+// y de admitidos y los íconos de los vehículos, fuera del lock del
+// estacionamiento.
+func (s *ParkingScene) runRefresher() {
+    for {
+        select {
+        case <-s.done:
+            return
+        case <-s.refresh:
+            s.updateTurnover()
+            s.updateIntakeChart()
+            s.refreshCarIcons()
+        }
+    }
+}
+
+// updateTurnover muestra la rotación y una barra por espacio. Las barras se
+// recrean cuando cambia la capacidad. Solo la llama runRefresher.
+func (s *ParkingScene) updateTurnover() {
+    if s.simulation == nil {
+        return
+    }
+    rates := s.simulation.GetSpaceTurnoverRates()
+    if len(s.turnoverChart.Objects) != len(rates) {
+        s.turnoverChart.Objects = nil
+        for range rates {
+            s.turnoverChart.Add(canvas.NewRectangle(color.RGBA{R: 50, G: 150, B: 50, A: 255}))
+        }
+    }
+    s.turnoverBars.setValues(rates)
+    s.turnoverLabel.SetText(fmt.Sprintf("Rotación: %.1f veh/espacio/h", s.simulation.GetTurnoverRate()))
+    s.turnoverChart.Refresh()
+}
+
+func (s *ParkingScene) updateQueueingParams() {
+    if s.simulation == nil {
+        return
+    }
+    params := s.simulation.GetQueueingTheoryParams()
+    s.queueingLabel.SetText(fmt.Sprintf("M/M/c/K: λ = %.2f · μ = %.3f · c = %.0f · K = %.0f · ρ = %.2f\nP(rechazo): teórica %.1f%% · observada %.1f%%",
+        params.Lambda, params.Mu, params.C, params.K, params.Rho,
+        params.TheoreticalRejectProb*100, params.ObservedRejectProb*100))
+}
+
+func (s *ParkingScene) updateEfficiency() {
+    if s.simulation == nil {
+        return
+    }
+    s.efficiency.SetText(fmt.Sprintf("Eficiencia: %s (%.2f)", s.simulation.GetEfficiencyGrade(), s.simulation.GetEfficiencyScore()))
+}
+
+func (s *ParkingScene) updateCapacityAdvice() {
+    if s.simulation == nil {
+        return
+    }
+    target := capacityTargetRejection * 100
+    extra, err := s.simulation.RecommendCapacity(context.Background(), capacityTargetRejection)
+    if err != nil {
+        s.capacityLabel.SetText(fmt.Sprintf("Capacidad: sin recomendación para rechazos <%.0f%%", target))
+    } else if extra == 0 {
+        s.capacityLabel.SetText(fmt.Sprintf("Capacidad suficiente para rechazos <%.0f%%", target))
+    } else {
+        s.capacityLabel.SetText(fmt.Sprintf("Capacidad: agregar ~%d espacios para rechazos <%.0f%%", extra, target))
+    }
+}
+
+// paintSpaces pinta ocupados los primeros espacios. Un espacio que se libera
+// antes de minVisualDwell sigue rojo hasta cumplirlo y luego se repinta.
+func (s *ParkingScene) paintSpaces(available int) {
+    s.dwellMu.Lock()
+    defer s.dwellMu.Unlock()
+
+    now := time.Now()
+    var pending time.Duration
+    for i, space := range s.spaceIcons {
+        if i >= len(s.paintedAt) {
+            break
+        }
+        if i < s.capacity-available {
+            if s.paintedAt[i].IsZero() {
+                s.paintedAt[i] = now
+            }
+            space.FillColor = color.RGBA{R: 200, G: 50, B: 50, A: 255}
+        } else if shown := now.Sub(s.paintedAt[i]); !s.paintedAt[i].IsZero() && shown < minVisualDwell {
+            if remaining := minVisualDwell - shown; remaining > pending {
+                pending = remaining
+            }
+            continue
+        } else {
+            s.paintedAt[i] = time.Time{}
+            space.FillColor = color.RGBA{R: 50, G: 150, B: 50, A: 255}
+        }
+        space.Refresh()
+    }
+
+    if pending > 0 && !s.dwellPending {
+        s.dwellPending = true
+        time.AfterFunc(pending, func() {
+            s.dwellMu.Lock()
+            s.dwellPending = false
+            s.dwellMu.Unlock()
+            s.paintSpaces(int(s.simulation.GetAvailableSpaces()))
+        })
+    }
+}
@@ -0,0 +1,177 @@
+package scenes
+
+import (
+    "image"
+    "strings"
+    "testing"
+    "time"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/test"
+    "fyne.io/fyne/v2/theme"
+    "fyne.io/fyne/v2/widget"
+    "holafyne/services"
+)
+
+func TestComputeMinWindowSize(t *testing.T) {
+    tests := []struct {
+        name       string
+        capacity   int
+        iconWidth  float32
+        iconHeight float32
+        minWidth   float32
+        minHeight  float32
+    }{
+        {"20 espacios con íconos por defecto", 20, spaceIconWidth, spaceIconHeight, 600, 400},
+        {"20 espacios con íconos chicos", 20, minSpaceWidth, minSpaceWidth * 2, 600, 160},
+        {"íconos anchos superan la calle", 20, 200, 100, 5 * (200 + spacePadding), 400},
+        {"un solo espacio", 1, spaceIconWidth, spaceIconHeight, roadWidth, spaceIconHeight + controlHeight},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            size := computeMinWindowSize(tt.capacity, parkingColumns, tt.iconWidth, tt.iconHeight)
+            if size.Width < tt.minWidth || size.Height < tt.minHeight {
+                t.Errorf("computeMinWindowSize = %v×%v, want al menos %v×%v", size.Width, size.Height, tt.minWidth, tt.minHeight)
+            }
+        })
+    }
+}
+
+// captureScene toma una imagen de la escena con el log vacío, que es lo
+// único que se espera que cambie entre una y otra, una vez que el refresco
+// en segundo plano terminó de dibujar.
+func captureScene(scene *ParkingScene) image.Image {
+    scene.logBox.SetText("")
+    time.Sleep(200 * time.Millisecond)
+    return scene.window.Canvas().Capture()
+}
+
+func sameImage(a, b image.Image) (int, bool) {
+    if a.Bounds() != b.Bounds() {
+        return -1, false
+    }
+    diff := 0
+    for y := a.Bounds().Min.Y; y < a.Bounds().Max.Y; y++ {
+        for x := a.Bounds().Min.X; x < a.Bounds().Max.X; x++ {
+            if a.At(x, y) != b.At(x, y) {
+                diff++
+            }
+        }
+    }
+    return diff, diff == 0
+}
+
+func TestInitialRenderMatchesAfterReset(t *testing.T) {
+    tests := []struct {
+        name  string
+        reset func(scene *ParkingScene)
+    }{
+        {"reinicio de estadísticas", func(scene *ParkingScene) {
+            scene.simulation.ResetStatistics()
+            scene.renderInitialState()
+        }},
+        {"volver a aplicar la configuración", func(scene *ParkingScene) {
+            scene.ApplyConfig(services.DefaultConfig())
+        }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            app := test.NewApp()
+            defer app.Quit()
+            app.Settings().SetTheme(theme.LightTheme())
+            app.Preferences().SetBool(tourCompletedKey, true)
+            window := test.NewWindow(nil)
+            defer window.Close()
+            window.Resize(fyne.NewSize(1000, 900))
+            scene := NewParkingScene(window)
+            defer scene.Close()
+
+            fresh := captureScene(scene)
+            tt.reset(scene)
+            if diff, ok := sameImage(fresh, captureScene(scene)); !ok {
+                t.Errorf("la escena cambió después del reinicio: %d píxeles distintos", diff)
+            }
+        })
+    }
+}
+
+func TestParkingGridEdgeCapacities(t *testing.T) {
+    tests := []struct {
+        name      string
+        capacity  int
+        wantCells int
+        wantNote  bool
+    }{
+        {"solo cola", 0, 1, true},
+        {"un espacio", 1, 1, false},
+        {"dos espacios", 2, 2, false},
+        {"veinte espacios", 20, 20, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            app := test.NewApp()
+            defer app.Quit()
+            app.Settings().SetTheme(theme.LightTheme())
+            app.Preferences().SetBool(tourCompletedKey, true)
+            window := test.NewWindow(nil)
+            defer window.Close()
+            window.Resize(fyne.NewSize(1000, 900))
+            scene := NewParkingScene(window)
+            defer scene.Close()
+
+            config := services.DefaultConfig()
+            config.ParkingCapacity = tt.capacity
+            scene.ApplyConfig(config)
+            scene.AutoFitParkingSpaces(1000, 900)
+
+            if cells := len(scene.parkingGrid.Objects); cells != tt.wantCells {
+                t.Errorf("celdas = %d, want %d", cells, tt.wantCells)
+            }
+            if len(scene.spaceIcons) != tt.capacity {
+                t.Errorf("íconos = %d, want %d", len(scene.spaceIcons), tt.capacity)
+            }
+            _, note := scene.parkingGrid.Objects[0].(*widget.Label)
+            if note != tt.wantNote {
+                t.Errorf("aviso de sin espacios = %v, want %v", note, tt.wantNote)
+            }
+            scene.updateStability()
+            if na := strings.Contains(scene.stabilityLabel.Text, "n/a"); na != tt.wantNote {
+                t.Errorf("estabilidad = %q, want n/a solo sin espacios", scene.stabilityLabel.Text)
+            }
+        })
+    }
+}
+
+func TestQueueCapacityEntryAcceptsZero(t *testing.T) {
+    tests := []struct {
+        text  string
+        valid bool
+    }{
+        {"-1", false},
+        {"0", true},
+        {"1", true},
+        {"100", true},
+        {"101", false},
+        {"x", false},
+    }
+    app := test.NewApp()
+    defer app.Quit()
+    app.Settings().SetTheme(theme.LightTheme())
+    app.Preferences().SetBool(tourCompletedKey, true)
+    window := test.NewWindow(nil)
+    defer window.Close()
+    scene := NewParkingScene(window)
+    defer scene.Close()
+
+    for _, tt := range tests {
+        t.Run(tt.text, func(t *testing.T) {
+            if err := scene.queueCapacity.Validator(tt.text); (err == nil) != tt.valid {
+                t.Errorf("Validator(%q) = %v, want válido %v", tt.text, err, tt.valid)
+            }
+        })
+    }
+
+    scene.queueCapacity.SetText("0")
+    if got := scene.simulation.GetConfig().MaxQueueSize; got != 0 {
+        t.Errorf("MaxQueueSize = %d tras escribir 0, want 0", got)
+    }
+}
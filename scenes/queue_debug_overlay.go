@@ -0,0 +1,89 @@
+package scenes
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+)
+
+const queueDebugRefresh = 500 * time.Millisecond
+
+// QueueDebugOverlay muestra lado a lado la cola de la simulación y la cola
+// interna del estacionamiento. Una vez unificadas, la segunda debería estar
+// siempre vacía.
+type QueueDebugOverlay struct {
+    scene      *ParkingScene
+    window     fyne.Window
+    simQueue   *widget.Label
+    lotQueue   *widget.Label
+    mismatches *widget.Label
+    done       chan struct{}
+}
+
+func ShowQueueDebugOverlay(scene *ParkingScene) *QueueDebugOverlay {
+    overlay := &QueueDebugOverlay{
+        scene:      scene,
+        window:     fyne.CurrentApp().NewWindow("Depuración de colas"),
+        simQueue:   widget.NewLabel(""),
+        lotQueue:   widget.NewLabel(""),
+        mismatches: widget.NewLabel(""),
+        done:       make(chan struct{}),
+    }
+
+    columns := container.NewGridWithColumns(2,
+        container.NewVBox(widget.NewLabelWithStyle("Cola de la simulación", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), overlay.simQueue),
+        container.NewVBox(widget.NewLabelWithStyle("Cola del estacionamiento", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), overlay.lotQueue),
+    )
+    overlay.window.SetContent(container.NewVBox(columns, widget.NewSeparator(), overlay.mismatches))
+    overlay.window.SetOnClosed(func() { close(overlay.done) })
+    overlay.window.Resize(fyne.NewSize(420, 300))
+    overlay.window.Show()
+
+    go overlay.run()
+    return overlay
+}
+
+func (o *QueueDebugOverlay) run() {
+    ticker := time.NewTicker(queueDebugRefresh)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-o.done:
+            return
+        case <-ticker.C:
+            o.render()
+        }
+    }
+}
+
+func (o *QueueDebugOverlay) render() {
+    sim := o.scene.GetSimulation()
+    snapshot := sim.GetQueueSnapshot()
+    inBoth := map[int]bool{}
+    for _, id := range snapshot.InBoth {
+        inBoth[id] = true
+    }
+
+    o.simQueue.SetText(formatQueueIDs(snapshot.Simulation, inBoth))
+    o.lotQueue.SetText(formatQueueIDs(snapshot.Lot, inBoth))
+    o.mismatches.SetText(fmt.Sprintf("En ambas colas: %d · Discrepancias registradas: %d",
+        len(snapshot.InBoth), sim.GetMetrics().QueueMismatches))
+}
+
+func formatQueueIDs(ids []int, flagged map[int]bool) string {
+    if len(ids) == 0 {
+        return "(vacía)"
+    }
+    lines := make([]string, len(ids))
+    for i, id := range ids {
+        lines[i] = "Vehículo " + strconv.Itoa(id)
+        if flagged[id] {
+            lines[i] += " ⚠️"
+        }
+    }
+    return strings.Join(lines, "\n")
+}
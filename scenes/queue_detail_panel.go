@@ -0,0 +1,241 @@
+package scenes
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "sync"
+    "time"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+    "holafyne/models"
+    "holafyne/services"
+)
+
+const longestWaitRefresh = 500 * time.Millisecond
+
+// QueueDetailPanel lista los vehículos en cola. mu protege vehicles,
+// position, cancel y SortByAge, que tocan a la vez la suscripción, el
+// indicador de peor espera y los controles de la interfaz.
+type QueueDetailPanel struct {
+    container *fyne.Container
+    summary   *widget.Label
+    longest   *widget.Button
+    rows      *fyne.Container
+    mu        sync.Mutex
+    vehicles  []*models.Vehicle
+    cancel    context.CancelFunc
+    position  int
+    onSelect  func(position int)
+    onLength  func(length int)
+    onNext    func(position int)
+    latency   *uiLatency
+    SortByAge bool
+}
+
+func NewQueueDetailPanel() *QueueDetailPanel {
+    panel := &QueueDetailPanel{
+        summary:  widget.NewLabel("Vehículos en cola: 0"),
+        rows:     container.NewVBox(),
+        position: -1,
+        latency:  newUILatency(),
+    }
+    panel.longest = widget.NewButton("Peor espera actual: —", panel.selectLongest)
+    panel.longest.Importance = widget.LowImportance
+    sortByAge := widget.NewCheck("Ordenar por antigüedad", func(enabled bool) {
+        panel.mu.Lock()
+        defer panel.mu.Unlock()
+        panel.SortByAge = enabled
+        panel.render()
+    })
+    panel.container = container.NewVBox(panel.summary, panel.longest, sortByAge, panel.rows)
+    return panel
+}
+
+// SetLongestWaitingCallback registra la función que se llama al pulsar el
+// indicador de peor espera, con la posición en la cola de ese vehículo.
+func (p *QueueDetailPanel) SetLongestWaitingCallback(callback func(position int)) {
+    p.onSelect = callback
+}
+
+// SetLengthCallback registra la función que recibe el largo de la cola cada
+// vez que cambia.
+func (p *QueueDetailPanel) SetLengthCallback(callback func(length int)) {
+    p.onLength = callback
+}
+
+// SetNextCallback registra la función que recibe la posición en la cola del
+// próximo vehículo en entrar, o -1 si la cola está vacía.
+func (p *QueueDetailPanel) SetNextCallback(callback func(position int)) {
+    p.onNext = callback
+}
+
+// SetLagCallback registra la función que se llama cuando los eventos de la
+// cola tardan más de uiLatencyWarning en aplicarse.
+func (p *QueueDetailPanel) SetLagCallback(callback func(latency time.Duration)) {
+    p.latency.mu.Lock()
+    defer p.latency.mu.Unlock()
+    p.latency.onLag = callback
+}
+
+func (p *QueueDetailPanel) selectLongest() {
+    p.mu.Lock()
+    position := p.position
+    p.mu.Unlock()
+    if p.onSelect != nil && position >= 0 {
+        p.onSelect(position)
+    }
+}
+
+func (p *QueueDetailPanel) Container() fyne.CanvasObject {
+    return p.container
+}
+
+// Subscribe cancela la suscripción anterior y sigue la cola de sim. Los
+// eventos que la anterior todavía tenga en su canal se descartan: cancelar
+// y revisar ctx ocurren con mu tomado, así que no pisan la lista nueva.
+func (p *QueueDetailPanel) Subscribe(sim *services.Simulation) {
+    ctx, cancel := context.WithCancel(context.Background())
+    p.mu.Lock()
+    p.unsubscribe()
+    p.cancel = cancel
+    p.vehicles = nil
+    p.position = -1
+    p.render()
+    p.mu.Unlock()
+    p.latency.reset()
+
+    events := sim.WatchQueue(ctx)
+    go func() {
+        for event := range events {
+            p.mu.Lock()
+            if ctx.Err() != nil {
+                p.mu.Unlock()
+                continue
+            }
+            if event.Dropped > 0 {
+                p.vehicles = sim.GetQueuedVehicles()
+            }
+            length, next := p.apply(event)
+            p.mu.Unlock()
+
+            p.notify(length, next)
+            p.latency.record(event.Timestamp, len(events))
+        }
+    }()
+    go p.watchLongest(ctx, sim)
+}
+
+func (p *QueueDetailPanel) watchLongest(ctx context.Context, sim *services.Simulation) {
+    ticker := time.NewTicker(longestWaitRefresh)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            p.renderLongest(sim)
+        }
+    }
+}
+
+func (p *QueueDetailPanel) renderLongest(sim *services.Simulation) {
+    text := "Peor espera actual: —"
+    position := -1
+    if info, wait, ok := sim.LongestWaiting(); ok {
+        text = fmt.Sprintf("Peor espera actual: Vehículo %d · %.0fs", info.ID, wait.Seconds())
+        position = info.Position
+    }
+    p.mu.Lock()
+    p.position = position
+    p.mu.Unlock()
+    if worst := sim.GetWorstWait(); worst > 0 {
+        text += fmt.Sprintf(" (récord: %.0fs)", worst.Seconds())
+    }
+    p.longest.SetText(text)
+}
+
+func (p *QueueDetailPanel) Unsubscribe() {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.unsubscribe()
+}
+
+func (p *QueueDetailPanel) unsubscribe() {
+    if p.cancel != nil {
+        p.cancel()
+        p.cancel = nil
+    }
+}
+
+// apply aplica el evento a la lista, con mu tomado, y devuelve el largo de
+// la cola y la posición del siguiente para avisar fuera del candado.
+func (p *QueueDetailPanel) apply(event services.QueueChangeEvent) (length, next int) {
+    switch event.ChangeType {
+    case services.Added:
+        // Después de releer la cola, el vehículo ya puede estar en la lista
+        if p.indexOf(event.Changed.ID) >= 0 {
+            break
+        }
+        position := event.Position
+        if position < 0 || position > len(p.vehicles) {
+            position = len(p.vehicles)
+        }
+        p.vehicles = append(p.vehicles, nil)
+        copy(p.vehicles[position+1:], p.vehicles[position:])
+        p.vehicles[position] = event.Changed
+    case services.Removed, services.Abandoned, services.Cancelled, services.Rejected:
+        if i := p.indexOf(event.Changed.ID); i >= 0 {
+            p.vehicles = append(p.vehicles[:i], p.vehicles[i+1:]...)
+        }
+    }
+    p.render()
+    next = -1
+    if len(p.vehicles) > 0 {
+        next = 0
+    }
+    return len(p.vehicles), next
+}
+
+func (p *QueueDetailPanel) notify(length, next int) {
+    if p.onLength != nil {
+        p.onLength(length)
+    }
+    if p.onNext != nil {
+        p.onNext(next)
+    }
+}
+
+func (p *QueueDetailPanel) indexOf(vehicleID int) int {
+    for i, vehicle := range p.vehicles {
+        if vehicle.ID == vehicleID {
+            return i
+        }
+    }
+    return -1
+}
+
+// render dibuja la lista; se llama con mu tomado.
+func (p *QueueDetailPanel) render() {
+    p.summary.SetText(fmt.Sprintf("Vehículos en cola: %d", len(p.vehicles)))
+    p.rows.Objects = nil
+    vehicles := p.vehicles
+    if p.SortByAge {
+        vehicles = append([]*models.Vehicle(nil), p.vehicles...)
+        sort.SliceStable(vehicles, func(i, j int) bool {
+            return vehicles[i].QueuedAt.Before(vehicles[j].QueuedAt)
+        })
+    }
+    for i, vehicle := range vehicles {
+        row := fmt.Sprintf("%d. Vehículo %d", i+1, vehicle.ID)
+        if vehicle == p.vehicles[0] {
+            row = "▶ " + row + " · siguiente"
+        }
+        if vehicle.Patience > 0 {
+            row += fmt.Sprintf(" · paciencia %.0fs", vehicle.Patience.Seconds())
+        }
+        p.rows.Add(widget.NewLabel(row))
+    }
+    p.rows.Refresh()
+}
@@ -0,0 +1,167 @@
+package scenes
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "testing"
+    "time"
+    "fyne.io/fyne/v2/test"
+    "fyne.io/fyne/v2/theme"
+    "fyne.io/fyne/v2/widget"
+    "holafyne/models"
+    "holafyne/services"
+)
+
+// scriptedArrivals entrega los vehículos que el test manda por el canal.
+type scriptedArrivals chan *models.Vehicle
+
+func (a scriptedArrivals) Next(ctx context.Context) (*models.Vehicle, bool) {
+    select {
+    case <-ctx.Done():
+        return nil, false
+    case vehicle := <-a:
+        return vehicle, true
+    }
+}
+
+// nextRow devuelve la fila marcada como siguiente en el panel de la cola y
+// su posición, o -1 si no hay ninguna. Se llama con panel.mu tomado.
+func nextRow(panel *QueueDetailPanel) (int, string) {
+    for i, object := range panel.rows.Objects {
+        if label, ok := object.(*widget.Label); ok && strings.HasPrefix(label.Text, "▶") {
+            return i, label.Text
+        }
+    }
+    return -1, ""
+}
+
+func TestNextInQueueHighlightFollowsPriority(t *testing.T) {
+    tests := []struct {
+        name    string
+        breaker func(a, b *models.Vehicle) bool
+        // wantNext es el vehículo marcado después de cada llegada.
+        wantNext []int
+        // wantRow es la fila marcada, con la lista ordenada por antigüedad.
+        wantRow []int
+    }{
+        {"FIFO", services.FIFOTieBreaker, []int{1, 1, 1}, []int{0, 0, 0}},
+        {"LIFO: el último en llegar pasa adelante", services.LIFOTieBreaker, []int{1, 2, 3}, []int{0, 1, 2}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            app := test.NewApp()
+            defer app.Quit()
+            app.Settings().SetTheme(theme.LightTheme())
+            app.Preferences().SetBool(tourCompletedKey, true)
+            window := test.NewWindow(nil)
+            defer window.Close()
+            scene := NewParkingScene(window)
+            defer scene.Close()
+
+            // Sin espacios los vehículos se quedan en la cola.
+            config := services.DefaultConfig()
+            config.ParkingCapacity = 0
+            scene.ApplyConfig(config)
+            scene.queueDetail.mu.Lock()
+            scene.queueDetail.SortByAge = true
+            scene.queueDetail.mu.Unlock()
+            sim := scene.simulation
+            sim.SetTieBreaker(tt.breaker)
+            arrivals := make(scriptedArrivals)
+            if err := sim.SetArrivalSource(arrivals); err != nil {
+                t.Fatal(err)
+            }
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            defer sim.Stop()
+
+            for i, want := range tt.wantNext {
+                arrivals <- models.NewVehicle(i + 1)
+                wantText := fmt.Sprintf("Vehículo %d", want)
+                wantSummary := fmt.Sprintf("Vehículos en cola: %d", i+1)
+                deadline := time.Now().Add(time.Second)
+                for {
+                    scene.queueDetail.mu.Lock()
+                    row, text := nextRow(scene.queueDetail)
+                    summary := scene.queueDetail.summary.Text
+                    scene.queueDetail.mu.Unlock()
+                    if summary == wantSummary && row == tt.wantRow[i] && strings.Contains(text, wantText+" ") {
+                        break
+                    }
+                    if time.Now().After(deadline) {
+                        t.Fatalf("llegada %d: fila marcada %d %q, want fila %d con %s", i+1, row, text, tt.wantRow[i], wantText)
+                    }
+                    time.Sleep(5 * time.Millisecond)
+                }
+                if next, ok := sim.PeekNextInQueue(); !ok || next.ID != want {
+                    t.Errorf("llegada %d: PeekNextInQueue = %v, want vehículo %d", i+1, next, want)
+                }
+                scene.queueIconsMu.Lock()
+                position, width := scene.nextPosition, scene.queueIcons[0].StrokeWidth
+                scene.queueIconsMu.Unlock()
+                if position != 0 || width == 0 {
+                    t.Errorf("llegada %d: ícono resaltado %d con borde %v, want el primero con borde", i+1, position, width)
+                }
+            }
+        })
+    }
+}
+
+func TestQueueDetailResubscribeDropsPreviousEvents(t *testing.T) {
+    app := test.NewApp()
+    defer app.Quit()
+    config := services.DefaultConfig()
+    config.ParkingCapacity = 0
+    newSim := func(arrivals scriptedArrivals) *services.Simulation {
+        sim := services.NewSimulationWithConfig(config, func(int, string) {})
+        if err := sim.SetArrivalSource(arrivals); err != nil {
+            t.Fatal(err)
+        }
+        if err := sim.Start(); err != nil {
+            t.Fatal(err)
+        }
+        return sim
+    }
+    queued := func(panel *QueueDetailPanel) []int {
+        panel.mu.Lock()
+        defer panel.mu.Unlock()
+        var ids []int
+        for _, vehicle := range panel.vehicles {
+            ids = append(ids, vehicle.ID)
+        }
+        return ids
+    }
+    waitFor := func(panel *QueueDetailPanel, want int) {
+        deadline := time.Now().Add(time.Second)
+        for len(queued(panel)) != want {
+            if time.Now().After(deadline) {
+                t.Fatalf("en el panel %v, want %d vehículos", queued(panel), want)
+            }
+            time.Sleep(5 * time.Millisecond)
+        }
+    }
+
+    first, second := make(scriptedArrivals), make(scriptedArrivals)
+    simA, simB := newSim(first), newSim(second)
+    defer simA.Stop()
+    defer simB.Stop()
+    panel := NewQueueDetailPanel()
+    defer panel.Unsubscribe()
+
+    panel.Subscribe(simA)
+    for id := 1; id <= 2; id++ {
+        first <- models.NewVehicle(id)
+    }
+    waitFor(panel, 2)
+
+    panel.Subscribe(simB)
+    first <- models.NewVehicle(3)
+    second <- models.NewVehicle(10)
+    waitFor(panel, 1)
+    time.Sleep(50 * time.Millisecond)
+    if ids := queued(panel); len(ids) != 1 || ids[0] != 10 {
+        t.Errorf("en el panel %v, want solo el vehículo 10 de la nueva simulación", ids)
+    }
+}
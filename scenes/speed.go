@@ -0,0 +1,43 @@
+package scenes
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/widget"
+)
+
+// speedOptions son las velocidades que ofrece el selector.
+var speedOptions = []string{"1x", "2x", "5x", "10x"}
+
+func (s *ParkingScene) createSpeedSelect() fyne.CanvasObject {
+    s.speedSelect = widget.NewSelect(speedOptions, s.selectSpeed)
+    s.speedSelect.SetSelected(speedOptions[0])
+    return container.NewHBox(widget.NewLabel("Velocidad"), s.speedSelect)
+}
+
+// selectSpeed cambia la velocidad de la simulación visible y, en modo A/B,
+// también la de la otra.
+func (s *ParkingScene) selectSpeed(option string) {
+    factor, err := strconv.ParseFloat(strings.TrimSuffix(option, "x"), 64)
+    if err != nil || s.simulation == nil {
+        return
+    }
+    for _, sim := range s.runningSimulations() {
+        if err := sim.SetSpeed(factor); err != nil {
+            dialog.ShowError(err, s.window)
+            return
+        }
+    }
+}
+
+// showSpeed muestra en el selector la velocidad de la simulación visible.
+func (s *ParkingScene) showSpeed() {
+    option := fmt.Sprintf("%gx", s.simulation.GetSpeed())
+    if s.speedSelect.Selected != option {
+        s.speedSelect.SetSelected(option)
+    }
+}
@@ -0,0 +1,32 @@
+package scenes
+
+import (
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/theme"
+    "fyne.io/fyne/v2/widget"
+    "holafyne/services"
+)
+
+// showRunSummary muestra el resumen al terminar las llegadas, con botones
+// para copiarlo como texto alineado o como tabla de Markdown.
+func (s *ParkingScene) showRunSummary() {
+    rows := s.simulation.GetRunSummary()
+    table := widget.NewLabel(services.FormatSummaryTable(rows, false))
+    table.TextStyle = fyne.TextStyle{Monospace: true}
+
+    copyText := widget.NewButtonWithIcon("Copiar resumen", theme.ContentCopyIcon(), func() {
+        s.copySummary(services.FormatSummaryTable(rows, false))
+    })
+    copyMarkdown := widget.NewButtonWithIcon("Copiar Markdown", theme.ContentCopyIcon(), func() {
+        s.copySummary(services.FormatSummaryTable(rows, true))
+    })
+    content := container.NewVBox(table, container.NewHBox(copyText, copyMarkdown))
+    dialog.ShowCustom("Simulación terminada", "Cerrar", content, s.window)
+}
+
+func (s *ParkingScene) copySummary(text string) {
+    s.window.Clipboard().SetContent(text)
+    s.logBox.SetText(s.logBox.Text() + "\n" + "Resumen copiado al portapapeles")
+}
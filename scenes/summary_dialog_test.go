@@ -0,0 +1,49 @@
+package scenes
+
+import (
+    "strings"
+    "testing"
+    "fyne.io/fyne/v2/test"
+    "fyne.io/fyne/v2/theme"
+    "holafyne/services"
+)
+
+func TestCopySummaryPutsTableOnClipboard(t *testing.T) {
+    rows := []services.SummaryRow{{Label: "Llegadas", Value: "12"}, {Label: "Espera máxima", Value: "12.5 s"}}
+    tests := []struct {
+        name     string
+        markdown bool
+        want     string
+    }{
+        {"texto plano", false, "" +
+            "Indicador       Valor\n" +
+            "-------------  ------\n" +
+            "Llegadas           12\n" +
+            "Espera máxima  12.5 s\n"},
+        {"markdown", true, "" +
+            "| Indicador     |  Valor |\n" +
+            "|---------------|-------:|\n" +
+            "| Llegadas      |     12 |\n" +
+            "| Espera máxima | 12.5 s |\n"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            app := test.NewApp()
+            defer app.Quit()
+            app.Settings().SetTheme(theme.LightTheme())
+            app.Preferences().SetBool(tourCompletedKey, true)
+            window := test.NewWindow(nil)
+            defer window.Close()
+            scene := NewParkingScene(window)
+            defer scene.Close()
+
+            scene.copySummary(services.FormatSummaryTable(rows, tt.markdown))
+            if got := window.Clipboard().Content(); got != tt.want {
+                t.Errorf("portapapeles:\n%s\nwant:\n%s", got, tt.want)
+            }
+            if !strings.HasSuffix(scene.logBox.Text(), "Resumen copiado al portapapeles") {
+                t.Errorf("el registro no avisa de la copia: %q", scene.logBox.Text())
+            }
+        })
+    }
+}
@@ -0,0 +1,150 @@
+package scenes
+
+import (
+    "fmt"
+    "sync"
+    "time"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/dialog"
+)
+
+const tourCompletedKey = "tourCompleted"
+
+type TourStep int
+
+const (
+    TourPoissonArrivals TourStep = iota
+    TourQueueing
+    TourRejection
+    TourLittlesLaw
+    TourFinished
+)
+
+type tourObservation struct {
+    running         bool
+    availableSpaces int
+    rejected        int64
+    arrivals        int64
+}
+
+type tourState struct {
+    step TourStep
+}
+
+// advance devuelve el paso que debe mostrarse para la observación actual, si
+// corresponde alguno. Los pasos se muestran en orden y cada uno una sola vez.
+func (t *tourState) advance(obs tourObservation) (TourStep, bool) {
+    var ready bool
+    switch t.step {
+    case TourPoissonArrivals:
+        ready = !obs.running
+        if obs.running {
+            t.step = TourQueueing
+            return t.advance(obs)
+        }
+    case TourQueueing:
+        ready = obs.running && obs.availableSpaces == 0
+    case TourRejection:
+        ready = obs.rejected > 0
+    case TourLittlesLaw:
+        ready = obs.arrivals >= 50
+    }
+
+    if !ready {
+        return t.step, false
+    }
+    shown := t.step
+    t.step++
+    return shown, true
+}
+
+type TourMode struct {
+    scene  *ParkingScene
+    state  tourState
+    ticker *time.Ticker
+    done   chan struct{}
+    stop   sync.Once
+}
+
+func StartTour(scene *ParkingScene) *TourMode {
+    tour := &TourMode{
+        scene:  scene,
+        ticker: time.NewTicker(250 * time.Millisecond),
+        done:   make(chan struct{}),
+    }
+    tour.check()
+    go tour.run()
+    return tour
+}
+
+func (t *TourMode) StopTour() {
+    t.stop.Do(func() {
+        t.ticker.Stop()
+        close(t.done)
+    })
+}
+
+func (t *TourMode) run() {
+    for {
+        select {
+        case <-t.done:
+            return
+        case <-t.ticker.C:
+            t.check()
+        }
+    }
+}
+
+func (t *TourMode) check() {
+    sim := t.scene.simulation
+    metrics := sim.GetMetrics()
+    step, ok := t.state.advance(tourObservation{
+        running:         sim.IsRunning(),
+        availableSpaces: sim.GetAvailableSpaces(),
+        rejected:        metrics.TotalRejected,
+        arrivals:        metrics.TotalArrivals,
+    })
+    if !ok {
+        return
+    }
+
+    title, message := t.describe(step)
+    dialog.NewInformation(title, message, t.scene.window).Show()
+
+    if t.state.step == TourFinished {
+        if app := fyne.CurrentApp(); app != nil {
+            app.Preferences().SetBool(tourCompletedKey, true)
+        }
+        t.StopTour()
+    }
+}
+
+func (t *TourMode) describe(step TourStep) (string, string) {
+    switch step {
+    case TourPoissonArrivals:
+        return "Llegadas de Poisson", "Los vehículos llegan siguiendo un proceso de Poisson:\n" +
+            "el tiempo entre llegadas es exponencial, así que a veces\n" +
+            "llegan varios seguidos y a veces hay pausas largas."
+    case TourQueueing:
+        return "Cola de espera", "El estacionamiento se llenó. Los vehículos que llegan\n" +
+            "ahora esperan en la cola hasta que se libere un espacio."
+    case TourRejection:
+        return "Modelo M/M/c/K", "La cola alcanzó su capacidad y un vehículo fue rechazado.\n" +
+            "Con c espacios y capacidad total K, el sistema se comporta\n" +
+            "como una cola M/M/c/K: lo que excede K se pierde."
+    case TourLittlesLaw:
+        sim := t.scene.simulation
+        elapsed := time.Since(sim.GetStatisticsSince()).Seconds()
+        metrics := sim.GetMetrics()
+        lambda := 0.0
+        if elapsed > 0 {
+            lambda = float64(metrics.TotalArrivals) / elapsed
+        }
+        inSystem := sim.GetOccupancy() + sim.GetQueueLength()
+        w := sim.GetSystemResponseTime().Seconds()
+        return "Ley de Little", fmt.Sprintf("Ya llegaron 50 vehículos. La ley de Little dice L = λW.\n"+
+            "λ observada: %.2f veh/s, tiempo en el sistema W: %.1f s.\n"+
+            "λW = %.1f frente a L observada: %d.", lambda, w, lambda*w, inSystem)
+    }
+    return "", ""
+}
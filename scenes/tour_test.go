@@ -0,0 +1,49 @@
+package scenes
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestTourStateAdvance(t *testing.T) {
+    idle := tourObservation{availableSpaces: 20}
+    running := tourObservation{running: true, availableSpaces: 5}
+    full := tourObservation{running: true}
+    rejected := tourObservation{running: true, rejected: 1, arrivals: 10}
+    busy := tourObservation{running: true, rejected: 3, arrivals: 50}
+    rejectedWithSpaces := tourObservation{running: true, availableSpaces: 3, rejected: 1}
+
+    tests := []struct {
+        name         string
+        observations []tourObservation
+        want         []TourStep
+        wantStep     TourStep
+    }{
+        {"sin simular solo muestra las llegadas", []tourObservation{idle, idle}, []TourStep{TourPoissonArrivals}, TourQueueing},
+        {"recorrido completo en orden", []tourObservation{idle, running, full, rejected, busy},
+            []TourStep{TourPoissonArrivals, TourQueueing, TourRejection, TourLittlesLaw}, TourFinished},
+        {"empezar corriendo salta las llegadas", []tourObservation{full}, []TourStep{TourQueueing}, TourRejection},
+        {"un paso por observación", []tourObservation{idle, busy, busy, busy},
+            []TourStep{TourPoissonArrivals, TourQueueing, TourRejection, TourLittlesLaw}, TourFinished},
+        {"el rechazo espera a que se llene", []tourObservation{idle, rejectedWithSpaces}, []TourStep{TourPoissonArrivals}, TourQueueing},
+        {"terminado no muestra más", []tourObservation{idle, busy, busy, busy, busy, idle},
+            []TourStep{TourPoissonArrivals, TourQueueing, TourRejection, TourLittlesLaw}, TourFinished},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var state tourState
+            var shown []TourStep
+            for _, obs := range tt.observations {
+                if step, ok := state.advance(obs); ok {
+                    shown = append(shown, step)
+                }
+            }
+            if !reflect.DeepEqual(shown, tt.want) {
+                t.Errorf("pasos mostrados = %v, want %v", shown, tt.want)
+            }
+            if state.step != tt.wantStep {
+                t.Errorf("paso actual = %v, want %v", state.step, tt.wantStep)
+            }
+        })
+    }
+}
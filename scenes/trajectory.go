@@ -0,0 +1,152 @@
+package scenes
+
+import (
+    "image/color"
+    "time"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/canvas"
+    "holafyne/models"
+)
+
+const (
+    trajectoryFade = time.Second
+    trajectoryDash = 6
+    trajectoryGap  = 6
+)
+
+// EnableVehicleTrajectory dibuja una línea punteada desde la entrada del
+// camino hasta el espacio spaceID, que se desvanece en trajectoryFade. Cada
+// trayectoria tiene sus propios trazos, así que varias pueden coexistir.
+func (s *ParkingScene) EnableVehicleTrajectory(spaceID int) {
+    app := fyne.CurrentApp()
+    if app == nil || s.road == nil || spaceID < 0 || spaceID >= len(s.spaceIcons) {
+        return
+    }
+    driver := app.Driver()
+    origin := driver.AbsolutePositionForObject(s.pathLayer)
+    roadPos := driver.AbsolutePositionForObject(s.road).Subtract(origin)
+    space := s.spaceIcons[spaceID]
+    spacePos := driver.AbsolutePositionForObject(space).Subtract(origin)
+
+    start := fyne.NewPos(roadPos.X, roadPos.Y+s.road.Size().Height/2)
+    corner := fyne.NewPos(spacePos.X+space.Size().Width/2, start.Y)
+    end := fyne.NewPos(corner.X, spacePos.Y+space.Size().Height)
+
+    dashes := append(dashedLine(start, corner), dashedLine(corner, end)...)
+    s.trajectoryMu.Lock()
+    for _, dash := range dashes {
+        s.pathLayer.Add(dash)
+    }
+    s.trajectories[spaceID] = append(s.trajectories[spaceID], dashes...)
+    s.trajectoryMu.Unlock()
+    s.pathLayer.Refresh()
+
+    fade := fyne.NewAnimation(trajectoryFade, func(done float32) {
+        alpha := uint8(255 * (1 - done))
+        for _, dash := range dashes {
+            dash.StrokeColor = color.NRGBA{R: 255, G: 255, B: 255, A: alpha}
+            dash.Refresh()
+        }
+    })
+    fade.Start()
+    time.AfterFunc(trajectoryFade, func() { s.removeTrajectory(spaceID, dashes) })
+}
+
+// DisableVehicleTrajectory deja de dibujar trayectorias y borra las que
+// estaban en curso.
+func (s *ParkingScene) DisableVehicleTrajectory() {
+    s.TrajectoryEnabled = false
+    s.trajectoryMu.Lock()
+    for spaceID, dashes := range s.trajectories {
+        for _, dash := range dashes {
+            s.pathLayer.Remove(dash)
+        }
+        delete(s.trajectories, spaceID)
+    }
+    s.trajectoryMu.Unlock()
+    s.pathLayer.Refresh()
+}
+
+// AnimateVehicleEntry muestra la trayectoria hacia un espacio recién ocupado
+// si TrajectoryEnabled está activo.
+func (s *ParkingScene) AnimateVehicleEntry(spaceID int) {
+    if s.TrajectoryEnabled {
+        s.EnableVehicleTrajectory(spaceID)
+    }
+}
+
+func (s *ParkingScene) removeTrajectory(spaceID int, dashes []*canvas.Line) {
+    s.trajectoryMu.Lock()
+    defer s.trajectoryMu.Unlock()
+
+    remaining := s.trajectories[spaceID][:0]
+    for _, dash := range s.trajectories[spaceID] {
+        if containsLine(dashes, dash) {
+            s.pathLayer.Remove(dash)
+            continue
+        }
+        remaining = append(remaining, dash)
+    }
+    if len(remaining) == 0 {
+        delete(s.trajectories, spaceID)
+    } else {
+        s.trajectories[spaceID] = remaining
+    }
+}
+
+// detectEntries compara los ocupantes de cada espacio con los de la revisión
+// anterior y anima los que cambiaron. Consulta los espacios, así que no debe
+// llamarse con el lock del estacionamiento tomado.
+func (s *ParkingScene) detectEntries() {
+    spaces := s.simulation.GetSpaces()
+    var entered []int
+
+    s.trajectoryMu.Lock()
+    if len(s.occupants) != len(spaces) {
+        s.occupants = make([]*models.Vehicle, len(spaces))
+    }
+    for i, space := range spaces {
+        if space.OccupiedBy != nil && space.OccupiedBy != s.occupants[i] {
+            entered = append(entered, space.ID)
+        }
+        s.occupants[i] = space.OccupiedBy
+    }
+    s.trajectoryMu.Unlock()
+
+    for _, spaceID := range entered {
+        s.AnimateVehicleEntry(spaceID)
+    }
+}
+
+func dashedLine(from, to fyne.Position) []*canvas.Line {
+    dx, dy := to.X-from.X, to.Y-from.Y
+    length := float32(0)
+    if dx != 0 {
+        length = fyne.Max(dx, -dx)
+    } else {
+        length = fyne.Max(dy, -dy)
+    }
+    if length == 0 {
+        return nil
+    }
+
+    var dashes []*canvas.Line
+    for offset := float32(0); offset < length; offset += trajectoryDash + trajectoryGap {
+        endOffset := fyne.Min(offset+trajectoryDash, length)
+        dash := canvas.NewLine(color.White)
+        dash.StrokeWidth = 2
+        dash.Position1 = fyne.NewPos(from.X+dx*offset/length, from.Y+dy*offset/length)
+        dash.Position2 = fyne.NewPos(from.X+dx*endOffset/length, from.Y+dy*endOffset/length)
+        dashes = append(dashes, dash)
+    }
+    return dashes
+}
+
+func containsLine(lines []*canvas.Line, line *canvas.Line) bool {
+    for _, candidate := range lines {
+        if candidate == line {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,175 @@
+package scenes
+
+import (
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+    "holafyne/utils"
+)
+
+const (
+    uiLatencySamples  = 512
+    uiDepthSamples    = 60
+    uiLatencyWarning  = time.Second
+    uiLatencyInterval = time.Second
+)
+
+// uiLatency mide cuánto tarda un evento de la cola en verse en pantalla:
+// desde el Timestamp con que lo emite la simulación hasta que el panel lo
+// aplica. Guarda las últimas latencias y, una vez por segundo, cuántos
+// eventos esperaban en el canal.
+type uiLatency struct {
+    mu        sync.Mutex
+    latencies []float64
+    next      int
+    depths    []int
+    sampledAt time.Time
+    lagging   bool
+    onLag     func(latency time.Duration)
+}
+
+func newUILatency() *uiLatency {
+    return &uiLatency{latencies: make([]float64, 0, uiLatencySamples)}
+}
+
+// record registra un evento aplicado. pending es cuántos eventos quedaban en
+// el canal al aplicarlo.
+func (l *uiLatency) record(emitted time.Time, pending int) {
+    latency := time.Since(emitted)
+
+    l.mu.Lock()
+    if len(l.latencies) < uiLatencySamples {
+        l.latencies = append(l.latencies, latency.Seconds())
+    } else {
+        l.latencies[l.next] = latency.Seconds()
+        l.next = (l.next + 1) % uiLatencySamples
+    }
+    if now := time.Now(); now.Sub(l.sampledAt) >= uiLatencyInterval {
+        l.sampledAt = now
+        l.depths = append(l.depths, pending)
+        if len(l.depths) > uiDepthSamples {
+            l.depths = l.depths[1:]
+        }
+    }
+    // El aviso se rearma cuando la latencia baja a la mitad del umbral
+    warn := !l.lagging && latency > uiLatencyWarning
+    if warn {
+        l.lagging = true
+    } else if latency < uiLatencyWarning/2 {
+        l.lagging = false
+    }
+    callback := l.onLag
+    l.mu.Unlock()
+
+    if warn && callback != nil {
+        callback(latency)
+    }
+}
+
+// percentiles devuelve p50 y p99 de las últimas latencias.
+func (l *uiLatency) percentiles() (p50, p99 time.Duration) {
+    l.mu.Lock()
+    samples := append([]float64(nil), l.latencies...)
+    l.mu.Unlock()
+    seconds := func(x float64) time.Duration { return time.Duration(x * float64(time.Second)) }
+    return seconds(utils.Percentile(samples, 0.5)), seconds(utils.Percentile(samples, 0.99))
+}
+
+func (l *uiLatency) depthHistory() []int {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return append([]int(nil), l.depths...)
+}
+
+func (l *uiLatency) reset() {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    l.latencies = l.latencies[:0]
+    l.next = 0
+    l.depths = nil
+    l.lagging = false
+}
+
+// UILatencyOverlay muestra la latencia de aplicación de los eventos de la
+// cola y la profundidad del canal en el último minuto.
+type UILatencyOverlay struct {
+    latency *uiLatency
+    window  fyne.Window
+    summary *widget.Label
+    depths  *widget.Label
+    done    chan struct{}
+}
+
+func ShowUILatencyOverlay(scene *ParkingScene) *UILatencyOverlay {
+    overlay := &UILatencyOverlay{
+        latency: scene.queueDetail.latency,
+        window:  fyne.CurrentApp().NewWindow("Latencia de la interfaz"),
+        summary: widget.NewLabel(""),
+        depths:  widget.NewLabel(""),
+        done:    make(chan struct{}),
+    }
+    overlay.depths.TextStyle = fyne.TextStyle{Monospace: true}
+    overlay.window.SetContent(container.NewVBox(
+        overlay.summary,
+        widget.NewLabelWithStyle("Eventos pendientes (último minuto)", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+        overlay.depths,
+    ))
+    overlay.window.SetOnClosed(func() { close(overlay.done) })
+    overlay.window.Resize(fyne.NewSize(420, 160))
+    overlay.window.Show()
+
+    go overlay.run()
+    return overlay
+}
+
+func (o *UILatencyOverlay) run() {
+    ticker := time.NewTicker(queueDebugRefresh)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-o.done:
+            return
+        case <-ticker.C:
+            o.render()
+        }
+    }
+}
+
+func (o *UILatencyOverlay) render() {
+    p50, p99 := o.latency.percentiles()
+    o.summary.SetText(fmt.Sprintf("Latencia de aplicación: p50 %s · p99 %s",
+        p50.Round(time.Millisecond), p99.Round(time.Millisecond)))
+
+    depths := o.latency.depthHistory()
+    if len(depths) == 0 {
+        o.depths.SetText("(sin eventos)")
+        return
+    }
+    o.depths.SetText(fmt.Sprintf("%s  actual: %d", sparkline(depths), depths[len(depths)-1]))
+}
+
+// sparkline dibuja los valores con bloques de distinta altura, escalados al
+// máximo.
+func sparkline(values []int) string {
+    blocks := []rune("▁▂▃▄▅▆▇█")
+    peak := 1
+    for _, v := range values {
+        peak = max(peak, v)
+    }
+    var b strings.Builder
+    for _, v := range values {
+        b.WriteRune(blocks[v*(len(blocks)-1)/peak])
+    }
+    return b.String()
+}
+
+// handleUILag avisa en el log que la interfaz va atrasada respecto al modelo.
+func (s *ParkingScene) handleUILag(latency time.Duration) {
+    s.logBox.SetText(s.logBox.Text() + "\n" + fmt.Sprintf(
+        "⚠️ La interfaz va %.1f s atrasada respecto a la simulación; conviene bajar la tasa de llegadas",
+        latency.Seconds()))
+}
@@ -0,0 +1,126 @@
+package scenes
+
+import (
+    "reflect"
+    "testing"
+    "time"
+)
+
+// recordAgo registra un evento emitido hace ago.
+func recordAgo(l *uiLatency, ago time.Duration, pending int) {
+    l.record(time.Now().Add(-ago), pending)
+}
+
+func nearLatency(got, want time.Duration) bool {
+    return (got - want).Abs() < 5*time.Millisecond
+}
+
+func TestUILatencyPercentiles(t *testing.T) {
+    tests := []struct {
+        name     string
+        fill     func(l *uiLatency)
+        p50, p99 time.Duration
+    }{
+        {"sin eventos", func(l *uiLatency) {}, 0, 0},
+        {"de 1 a 100 ms", func(l *uiLatency) {
+            for i := 1; i <= 100; i++ {
+                recordAgo(l, time.Duration(i)*time.Millisecond, 0)
+            }
+        }, 50500 * time.Microsecond, 99010 * time.Microsecond},
+        // El buffer guarda las últimas uiLatencySamples: las lentas de antes
+        // ya no cuentan.
+        {"el buffer descarta las viejas", func(l *uiLatency) {
+            for i := 0; i < uiLatencySamples; i++ {
+                recordAgo(l, 800*time.Millisecond, 0)
+            }
+            for i := 0; i < uiLatencySamples; i++ {
+                recordAgo(l, 20*time.Millisecond, 0)
+            }
+        }, 20 * time.Millisecond, 20 * time.Millisecond},
+        {"reset vacía", func(l *uiLatency) {
+            recordAgo(l, 300*time.Millisecond, 3)
+            l.reset()
+        }, 0, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            l := newUILatency()
+            tt.fill(l)
+            p50, p99 := l.percentiles()
+            if !nearLatency(p50, tt.p50) || !nearLatency(p99, tt.p99) {
+                t.Errorf("p50 %v, p99 %v, want %v y %v", p50, p99, tt.p50, tt.p99)
+            }
+            if len(l.latencies) > uiLatencySamples {
+                t.Errorf("%d latencias guardadas, más que %d", len(l.latencies), uiLatencySamples)
+            }
+        })
+    }
+}
+
+func TestUILatencyWarnsOncePerEpisode(t *testing.T) {
+    tests := []struct {
+        name      string
+        latencies []time.Duration
+        wantWarns int
+    }{
+        {"por debajo del umbral", []time.Duration{100 * time.Millisecond, 900 * time.Millisecond}, 0},
+        {"un episodio lento avisa una vez", []time.Duration{2 * time.Second, 3 * time.Second, 1500 * time.Millisecond}, 1},
+        // Entre la mitad del umbral y el umbral el aviso no se rearma.
+        {"sin bajar a la mitad no se rearma", []time.Duration{2 * time.Second, 800 * time.Millisecond, 2 * time.Second}, 1},
+        {"al bajar a la mitad se rearma", []time.Duration{2 * time.Second, 100 * time.Millisecond, 2 * time.Second}, 2},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            l := newUILatency()
+            var warned []time.Duration
+            l.onLag = func(latency time.Duration) { warned = append(warned, latency) }
+            for _, latency := range tt.latencies {
+                recordAgo(l, latency, 0)
+            }
+            if len(warned) != tt.wantWarns {
+                t.Fatalf("avisos = %v, want %d", warned, tt.wantWarns)
+            }
+            for _, latency := range warned {
+                if latency <= uiLatencyWarning {
+                    t.Errorf("aviso con latencia %v, por debajo del umbral", latency)
+                }
+            }
+        })
+    }
+}
+
+func TestUILatencySamplesDepthOncePerInterval(t *testing.T) {
+    l := newUILatency()
+    for _, pending := range []int{4, 7, 9} {
+        recordAgo(l, time.Millisecond, pending)
+    }
+    if got := l.depthHistory(); !reflect.DeepEqual(got, []int{4}) {
+        t.Fatalf("profundidades = %v, want [4]", got)
+    }
+
+    for i := 0; i < uiDepthSamples+5; i++ {
+        l.sampledAt = time.Now().Add(-uiLatencyInterval)
+        recordAgo(l, time.Millisecond, i)
+    }
+    got := l.depthHistory()
+    if len(got) != uiDepthSamples || got[len(got)-1] != uiDepthSamples+4 {
+        t.Errorf("profundidades = %v, want las últimas %d", got, uiDepthSamples)
+    }
+}
+
+func TestSparkline(t *testing.T) {
+    tests := []struct {
+        values []int
+        want   string
+    }{
+        {[]int{0, 0}, "▁▁"},
+        {[]int{0, 7}, "▁█"},
+        {[]int{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+        {[]int{14, 7, 0}, "█▄▁"},
+    }
+    for _, tt := range tests {
+        if got := sparkline(tt.values); got != tt.want {
+            t.Errorf("sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+    "encoding/xml"
+    "fmt"
+    "os"
+    "holafyne/models"
+)
+
+const (
+    ARENA_TIME_UNITS = "Seconds"
+
+    ARENA_SEIZE_DELAY_RELEASE = "Seize Delay Release"
+    ARENA_SEIZE_DELAY         = "Seize Delay"
+    ARENA_DELAY               = "Delay"
+    ARENA_DELAY_RELEASE       = "Delay Release"
+
+    // ARENA_UNLIMITED_QUEUE marca una cola sin límite. Capacity 0 es una cola
+    // donde nadie espera, como MaxQueueSize 0 en la simulación.
+    ARENA_UNLIMITED_QUEUE = -1
+)
+
+// ArenaModel es una versión simplificada del modelo de ARENA: recursos,
+// entidades, colas y procesos. Solo describe la configuración, no el estado
+// de la corrida, para que los alumnos armen el mismo modelo y comparen.
+type ArenaModel struct {
+    XMLName   xml.Name        `xml:"ArenaModel"`
+    TimeUnits string          `xml:"timeUnits,attr"`
+    Resources []ArenaResource `xml:"Resources>Resource"`
+    Entities  []ArenaEntity   `xml:"Entities>Entity"`
+    Queues    []ArenaQueue    `xml:"Queues>Queue"`
+    Processes []ArenaProcess  `xml:"Processes>Process"`
+}
+
+type ArenaResource struct {
+    Name     string `xml:"name,attr"`
+    Capacity int    `xml:"capacity,attr"`
+}
+
+// ArenaEntity corresponde a un módulo Create: MaxArrivals 0 significa sin
+// límite.
+type ArenaEntity struct {
+    Name               string `xml:"name,attr"`
+    InterArrivalTime   string `xml:"interArrivalTime,attr"`
+    EntitiesPerArrival int    `xml:"entitiesPerArrival,attr"`
+    MaxArrivals        int    `xml:"maxArrivals,attr"`
+}
+
+// ArenaQueue usa Capacity ARENA_UNLIMITED_QUEUE para una cola sin límite y
+// Renege vacío cuando los vehículos no abandonan.
+type ArenaQueue struct {
+    Name     string `xml:"name,attr"`
+    Ranking  string `xml:"ranking,attr"`
+    Capacity int    `xml:"capacity,attr"`
+    Renege   string `xml:"renege,attr,omitempty"`
+}
+
+type ArenaProcess struct {
+    Name      string   `xml:"name,attr"`
+    Action    string   `xml:"action,attr"`
+    Queue     string   `xml:"queue,attr,omitempty"`
+    Delay     string   `xml:"delay,attr"`
+    Resources []string `xml:"Resource"`
+}
+
+// GetArenaModel traduce la configuración actual a conceptos de ARENA: los
+// espacios y la pluma son recursos, los vehículos la entidad, y
+// processVehicle se reparte en entrada, estancia y salida.
+func (s *Simulation) GetArenaModel() ArenaModel {
+    config := s.GetConfig()
+
+    entity := ArenaEntity{
+        Name:               "Vehiculo",
+        InterArrivalTime:   arenaExpo(1 / config.ArrivalRate),
+        EntitiesPerArrival: 1,
+        MaxArrivals:        config.MaxVehicles,
+    }
+    if config.ClosedPopulation > 0 {
+        // En una población cerrada los vehículos se crean una sola vez y
+        // regresan tras el tiempo fuera.
+        entity.InterArrivalTime = "0"
+        entity.EntitiesPerArrival = config.ClosedPopulation
+        entity.MaxArrivals = 1
+    }
+
+    model := ArenaModel{
+        TimeUnits: ARENA_TIME_UNITS,
+        Resources: []ArenaResource{
+            {Name: "Espacios", Capacity: config.ParkingCapacity},
+            {Name: "Pluma", Capacity: 1},
+        },
+        Entities: []ArenaEntity{entity},
+        Queues: []ArenaQueue{
+            {Name: "Entrada.Queue", Ranking: "FIFO", Capacity: config.MaxQueueSize, Renege: arenaRenege(config.Patience)},
+            {Name: "Pluma.Queue", Ranking: arenaGateRanking(config.GatePolicy), Capacity: ARENA_UNLIMITED_QUEUE},
+        },
+        Processes: []ArenaProcess{
+            {Name: "Entrada", Action: ARENA_SEIZE_DELAY, Queue: "Entrada.Queue", Delay: "0", Resources: []string{"Espacios"}},
+            {Name: "Pluma Entrada", Action: ARENA_SEIZE_DELAY_RELEASE, Queue: "Pluma.Queue", Delay: "0", Resources: []string{"Pluma"}},
+            {Name: "Estacionado", Action: ARENA_DELAY, Delay: arenaUniform(config.MinParkTime, config.MaxParkTime)},
+            {Name: "Pluma Salida", Action: ARENA_SEIZE_DELAY_RELEASE, Queue: "Pluma.Queue", Delay: "0", Resources: []string{"Pluma"}},
+            {Name: "Salida", Action: ARENA_DELAY_RELEASE, Delay: "0", Resources: []string{"Espacios"}},
+        },
+    }
+    if config.ClosedPopulation > 0 && config.AwayRate > 0 {
+        model.Processes = append(model.Processes, ArenaProcess{
+            Name:   "Fuera",
+            Action: ARENA_DELAY,
+            Delay:  arenaExpo(1 / config.AwayRate),
+        })
+    }
+    return model
+}
+
+// WriteToFile guarda el modelo como XML con su encabezado.
+func (m ArenaModel) WriteToFile(path string) error {
+    data, err := xml.MarshalIndent(m, "", "  ")
+    if err != nil {
+        return err
+    }
+    data = append([]byte(xml.Header), data...)
+    return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func arenaExpo(mean float64) string {
+    return fmt.Sprintf("EXPO(%.4g)", mean)
+}
+
+func arenaUniform(min, max float64) string {
+    return fmt.Sprintf("UNIF(%.4g, %.4g)", min, max)
+}
+
+// arenaGateRanking aproxima la política de la pluma. ARENA no tiene una regla
+// que alterne direcciones, así que la alternancia se exporta como FIFO.
+func arenaGateRanking(policy models.GatePolicy) string {
+    if policy == models.GatePolicyExitsFirst {
+        return "HVF(Direccion)"
+    }
+    return "FIFO"
+}
+
+func arenaRenege(patience PatienceConfig) string {
+    switch patience.Mode {
+    case PATIENCE_FIXED:
+        return fmt.Sprintf("%.4g", patience.MaxWaitTime)
+    case PATIENCE_DISTRIBUTION:
+        if patience.Distribution == DISTRIBUTION_UNIFORM {
+            return arenaUniform(patience.Min, patience.Max)
+        }
+        return arenaExpo(patience.Mean)
+    }
+    return ""
+}
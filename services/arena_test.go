@@ -0,0 +1,32 @@
+package services
+
+import "testing"
+
+func TestArenaQueueCapacity(t *testing.T) {
+    tests := []struct {
+        name     string
+        maxQueue int
+        want     int
+    }{
+        {"sin cola rechaza de inmediato", 0, 0},
+        {"cola limitada", 5, 5},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.MaxQueueSize = tt.maxQueue
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+
+            queues := map[string]int{}
+            for _, queue := range sim.GetArenaModel().Queues {
+                queues[queue.Name] = queue.Capacity
+            }
+            if got := queues["Entrada.Queue"]; got != tt.want {
+                t.Errorf("Entrada.Queue capacity = %d, want %d", got, tt.want)
+            }
+            if got := queues["Pluma.Queue"]; got != ARENA_UNLIMITED_QUEUE {
+                t.Errorf("Pluma.Queue capacity = %d, want ARENA_UNLIMITED_QUEUE", got)
+            }
+        })
+    }
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+    "context"
+    "math"
+    "math/rand"
+    "sync"
+    "time"
+    "holafyne/models"
+    "holafyne/utils"
+)
+
+const (
+    DEFAULT_AWAY_RATE       = 0.1
+    DEFAULT_DAY_LENGTH      = 240
+    DEFAULT_DAILY_AMPLITUDE = 0.8
+)
+
+type ArrivalSource interface {
+    Next(ctx context.Context) (*models.Vehicle, bool)
+}
+
+// DepartureObserver lo implementan las fuentes que necesitan saber cuándo un
+// vehículo abandona el sistema, ya sea al salir o al ser rechazado.
+type DepartureObserver interface {
+    OnDeparture(vehicle *models.Vehicle)
+}
+
+// resettableSource es una fuente que puede volver a empezar su secuencia
+// cuando la simulación se arranca de nuevo después de Stop.
+type resettableSource interface {
+    reset()
+}
+
+type PoissonArrivalSource struct {
+    generator   *utils.PoissonGenerator
+    maxVehicles int
+    count       int
+    wait        func(ctx context.Context, d time.Duration) bool
+}
+
+func NewPoissonArrivalSource(generator *utils.PoissonGenerator, maxVehicles int) *PoissonArrivalSource {
+    return &PoissonArrivalSource{
+        generator:   generator,
+        maxVehicles: maxVehicles,
+        wait:        sleepContext,
+    }
+}
+
+// sleepContext espera d en tiempo real. Devuelve falso si ctx termina antes.
+// Las fuentes que crea la simulación usan en cambio su reloj, que respeta la
+// pausa y la velocidad.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+    timer := time.NewTimer(d)
+    defer timer.Stop()
+    select {
+    case <-ctx.Done():
+        return false
+    case <-timer.C:
+        return true
+    }
+}
+
+func (src *PoissonArrivalSource) Next(ctx context.Context) (*models.Vehicle, bool) {
+    if src.count >= src.maxVehicles {
+        return nil, false
+    }
+
+    if src.count > 0 && !src.wait(ctx, src.generator.NextInterval()) {
+        return nil, false
+    }
+
+    src.count++
+    return models.NewVehicle(src.count), true
+}
+
+func (src *PoissonArrivalSource) reset() {
+    src.count = 0
+}
+
+// DailyPatternArrivalSource sigue el patrón diario del generador: arma las
+// llegadas de un día completo y, al acabarse, las del siguiente.
+type DailyPatternArrivalSource struct {
+    generator   *utils.PoissonGenerator
+    dayLength   time.Duration
+    maxVehicles int
+    count       int
+    pending     []time.Duration
+    dayStart    time.Duration
+    last        time.Duration
+    wait        func(ctx context.Context, d time.Duration) bool
+}
+
+// dailyPhase calcula la fase del patrón diario para que el pico caiga en
+// DailyPeak, leído con el mapeo de reloj; sin DailyPeak usa la fase por
+// defecto.
+func dailyPhase(config SimulationConfig, dayLength time.Duration) float64 {
+    peak, err := config.TimeMapping.ParseClock(config.DailyPeak)
+    if config.DailyPeak == "" || err != nil || dayLength <= 0 {
+        return utils.DEFAULT_DAILY_PHASE
+    }
+    return 2*math.Pi*peak.Seconds()/dayLength.Seconds() - math.Pi/2
+}
+
+func NewDailyPatternArrivalSource(generator *utils.PoissonGenerator, dayLength time.Duration, maxVehicles int) *DailyPatternArrivalSource {
+    return &DailyPatternArrivalSource{
+        generator:   generator,
+        dayLength:   dayLength,
+        maxVehicles: maxVehicles,
+        wait:        sleepContext,
+    }
+}
+
+func (src *DailyPatternArrivalSource) Next(ctx context.Context) (*models.Vehicle, bool) {
+    if src.count >= src.maxVehicles {
+        return nil, false
+    }
+
+    for len(src.pending) == 0 {
+        for _, t := range src.generator.GenerateDailyPattern(src.dayLength) {
+            src.pending = append(src.pending, src.dayStart+t)
+        }
+        src.dayStart += src.dayLength
+    }
+    next := src.pending[0]
+    src.pending = src.pending[1:]
+
+    if !src.wait(ctx, next-src.last) {
+        return nil, false
+    }
+    src.last = next
+
+    src.count++
+    return models.NewVehicle(src.count), true
+}
+
+func (src *DailyPatternArrivalSource) reset() {
+    src.count = 0
+    src.pending = nil
+    src.dayStart = 0
+    src.last = 0
+}
+
+// ClosedLoopArrivalSource modela una población fija de vehículos: cada uno,
+// al irse, pasa un tiempo exponencial fuera y luego vuelve a llegar.
+type ClosedLoopArrivalSource struct {
+    population int
+    awayRate   float64
+    ready      chan int
+    visits     map[int]int
+    rng        *rand.Rand
+    mu         sync.Mutex
+    startOnce  sync.Once
+    generation int
+}
+
+func NewClosedLoopArrivalSource(population int, awayRate float64) *ClosedLoopArrivalSource {
+    if awayRate <= 0 {
+        awayRate = DEFAULT_AWAY_RATE
+    }
+    return &ClosedLoopArrivalSource{
+        population: population,
+        awayRate:   awayRate,
+        ready:      make(chan int, population),
+        visits:     make(map[int]int),
+        rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+    }
+}
+
+func (src *ClosedLoopArrivalSource) Next(ctx context.Context) (*models.Vehicle, bool) {
+    src.startOnce.Do(func() {
+        for id := 1; id <= src.population; id++ {
+            src.scheduleReturn(id)
+        }
+    })
+
+    select {
+    case <-ctx.Done():
+        return nil, false
+    case id := <-src.ready:
+        src.mu.Lock()
+        src.visits[id]++
+        visit := src.visits[id]
+        src.mu.Unlock()
+
+        vehicle := models.NewVehicle(id)
+        vehicle.Visit = visit
+        return vehicle, true
+    }
+}
+
+func (src *ClosedLoopArrivalSource) OnDeparture(vehicle *models.Vehicle) {
+    src.scheduleReturn(vehicle.ID)
+}
+
+// reset vuelve a poner a toda la población fuera. Los regresos que quedaron
+// programados de la corrida anterior se descartan.
+func (src *ClosedLoopArrivalSource) reset() {
+    src.mu.Lock()
+    src.generation++
+    src.visits = make(map[int]int)
+    src.startOnce = sync.Once{}
+    src.mu.Unlock()
+    for {
+        select {
+        case <-src.ready:
+        default:
+            return
+        }
+    }
+}
+
+func (src *ClosedLoopArrivalSource) scheduleReturn(id int) {
+    src.mu.Lock()
+    away := src.rng.ExpFloat64() / src.awayRate
+    generation := src.generation
+    src.mu.Unlock()
+
+    time.AfterFunc(time.Duration(away*float64(time.Second)), func() {
+        src.mu.Lock()
+        stale := generation != src.generation
+        src.mu.Unlock()
+        if stale {
+            return
+        }
+        select {
+        case src.ready <- id:
+        default:
+        }
+    })
+}
+
+func (src *ClosedLoopArrivalSource) GetPopulation() int {
+    return src.population
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+    "sync"
+    "time"
+)
+
+// busyTracker mide los periodos de ocupado: desde que el estacionamiento se
+// llena hasta que se libera un espacio.
+type busyTracker struct {
+    mu     sync.Mutex
+    isBusy bool
+    start  time.Time
+    last   time.Duration
+    total  time.Duration
+}
+
+func (b *busyTracker) update(available int, now time.Time) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    if available <= 0 && !b.isBusy {
+        b.isBusy = true
+        b.start = now
+    } else if available > 0 && b.isBusy {
+        b.isBusy = false
+        b.last = now.Sub(b.start)
+        b.total += b.last
+    }
+}
+
+func (b *busyTracker) reset(now time.Time) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    b.last = 0
+    b.total = 0
+    if b.isBusy {
+        b.start = now
+    }
+}
+
+func (s *Simulation) handleLotUpdate(spaces int, message string) {
+    now := time.Now()
+    s.busy.update(spaces, now)
+    s.freeSpaces.update(spaces, now)
+    if s.updateUI != nil {
+        s.updateUI(spaces, message)
+    }
+}
+
+// GetBusyPeriodDuration devuelve lo que lleva el periodo de ocupado actual o,
+// si el estacionamiento no está lleno, lo que duró el último.
+func (s *Simulation) GetBusyPeriodDuration() time.Duration {
+    s.busy.mu.Lock()
+    defer s.busy.mu.Unlock()
+    if s.busy.isBusy {
+        return time.Since(s.busy.start)
+    }
+    return s.busy.last
+}
+
+// GetTotalBusyTime suma todos los periodos de ocupado desde el último
+// reinicio de estadísticas, incluido el actual.
+func (s *Simulation) GetTotalBusyTime() time.Duration {
+    s.busy.mu.Lock()
+    defer s.busy.mu.Unlock()
+    total := s.busy.total
+    if s.busy.isBusy {
+        total += time.Since(s.busy.start)
+    }
+    return total
+}
+
+func (s *Simulation) GetBusyPeriodFraction() float64 {
+    elapsed := time.Since(s.GetStatisticsSince())
+    if elapsed <= 0 {
+        return 0
+    }
+    return float64(s.GetTotalBusyTime()) / float64(elapsed)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "time"
+    "holafyne/utils"
+)
+
+// MAX_CAPACITY_SEARCH limita cuántos espacios extra prueba RecommendCapacity.
+const MAX_CAPACITY_SEARCH = 500
+
+var ErrCapacityNotFound = errors.New("no se encontró una capacidad que cumpla el objetivo de rechazos")
+
+// freeSpaceTracker integra en el tiempo los espacios libres para obtener su
+// promedio ponderado.
+type freeSpaceTracker struct {
+    mu       sync.Mutex
+    free     int
+    last     time.Time
+    since    time.Time
+    integral float64
+}
+
+func newFreeSpaceTracker(free int, now time.Time) *freeSpaceTracker {
+    return &freeSpaceTracker{free: free, last: now, since: now}
+}
+
+func (f *freeSpaceTracker) update(free int, now time.Time) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.integral += float64(f.free) * now.Sub(f.last).Seconds()
+    f.free = free
+    f.last = now
+}
+
+func (f *freeSpaceTracker) reset(now time.Time) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.integral = 0
+    f.last = now
+    f.since = now
+}
+
+func (f *freeSpaceTracker) average(now time.Time) float64 {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    elapsed := now.Sub(f.since).Seconds()
+    if elapsed <= 0 {
+        return float64(f.free)
+    }
+    integral := f.integral + float64(f.free)*now.Sub(f.last).Seconds()
+    return integral / elapsed
+}
+
+// GetTimeWeightedFreeSpaces devuelve el promedio de espacios libres ponderado
+// por el tiempo desde el último reinicio de estadísticas.
+func (s *Simulation) GetTimeWeightedFreeSpaces() float64 {
+    return s.freeSpaces.average(time.Now())
+}
+
+// RecommendCapacity estima cuántos espacios hay que agregar para que la tasa
+// de rechazos baje de target (una fracción, p. ej. 0.01). Parte de Erlang B
+// con la carga observada y corrige el modelo con la proporción entre los
+// rechazos observados y los teóricos a la capacidad actual.
+func (s *Simulation) RecommendCapacity(ctx context.Context, target float64) (int, error) {
+    capacity := s.GetConfig().ParkingCapacity
+    lambda, avgPark := s.observedRates()
+    load := lambda * avgPark
+
+    blocking := 1.0
+    for c := 1; c <= capacity; c++ {
+        blocking = erlangStep(blocking, c, load)
+    }
+
+    correction := 1.0
+    if samples := s.samples.rejection.Samples(); len(samples) > 0 && blocking > 0 {
+        if observed := utils.Mean(samples); observed > 0 {
+            correction = observed / blocking
+        }
+    }
+
+    for extra := 0; extra <= MAX_CAPACITY_SEARCH; extra++ {
+        if err := ctx.Err(); err != nil {
+            return 0, err
+        }
+        if extra > 0 {
+            blocking = erlangStep(blocking, capacity+extra, load)
+        }
+        if blocking*correction < target {
+            return extra, nil
+        }
+    }
+    return 0, ErrCapacityNotFound
+}
+
+// erlangStep aplica la recurrencia de Erlang B: B(c) = aB(c-1) / (c + aB(c-1)).
+func erlangStep(previous float64, c int, load float64) float64 {
+    return load * previous / (float64(c) + load*previous)
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+    "context"
+    "fmt"
+    "runtime"
+    "sync/atomic"
+    "time"
+)
+
+const (
+    // DEADLOCK_SUSPECT_CHECKS es cuántas revisiones seguidas debe repetirse
+    // una anomalía antes de reportarla, para no confundir una carrera entre
+    // lecturas con un bloqueo.
+    DEADLOCK_SUSPECT_CHECKS = 2
+    // GOROUTINE_GROWTH_CHECKS es cuántas revisiones seguidas debe crecer el
+    // número de goroutines para considerarlo una fuga.
+    GOROUTINE_GROWTH_CHECKS = 5
+    DEADLOCK_REPORT_BUFFER  = 8
+)
+
+// DeadlockReport incluye en Stack el volcado de goroutines del momento en
+// que se sospechó el bloqueo.
+type DeadlockReport struct {
+    SuspectedAt    time.Time
+    GoroutineCount int
+    Evidence       string
+    Stack          string
+}
+
+// deadlockMonitor guarda lo observado en la revisión anterior.
+type deadlockMonitor struct {
+    goroutines int
+    growth     int
+    state      int
+    stalled    int
+    orphaned   int
+}
+
+// MonitorDeadlocks revisa cada interval que la simulación avance y envía un
+// reporte por cada anomalía: goroutines que crecen sin parar, vehículos en
+// cola con espacios libres sin que nada cambie, o vehículos estacionados sin
+// goroutine que los saque. Si nadie lee el canal, los reportes se descartan.
+// El canal se cierra con StopMonitor o al detener la simulación; con un
+// interval que no es positivo se devuelve ya cerrado.
+func (s *Simulation) MonitorDeadlocks(interval time.Duration) <-chan DeadlockReport {
+    if interval <= 0 {
+        reports := make(chan DeadlockReport)
+        close(reports)
+        return reports
+    }
+    ctx, cancel := context.WithCancel(s.ctx)
+    s.stateMutex.Lock()
+    if s.stopMonitor != nil {
+        s.stopMonitor()
+    }
+    s.stopMonitor = cancel
+    s.stateMutex.Unlock()
+
+    reports := make(chan DeadlockReport, DEADLOCK_REPORT_BUFFER)
+    go func() {
+        defer close(reports)
+        ticker := time.NewTicker(interval)
+        defer ticker.Stop()
+
+        monitor := &deadlockMonitor{goroutines: runtime.NumGoroutine(), state: -1}
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case <-ticker.C:
+                for _, evidence := range s.checkDeadlock(monitor) {
+                    report := DeadlockReport{
+                        SuspectedAt:    time.Now(),
+                        GoroutineCount: runtime.NumGoroutine(),
+                        Evidence:       evidence,
+                        Stack:          goroutineDump(),
+                    }
+                    s.keepDeadlockReport(report)
+                    select {
+                    case reports <- report:
+                    default:
+                    }
+                }
+            }
+        }
+    }()
+    return reports
+}
+
+// keepDeadlockReport conserva los últimos reportes para el paquete de
+// diagnóstico, aunque nadie esté leyendo el canal.
+func (s *Simulation) keepDeadlockReport(report DeadlockReport) {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+    s.deadlocks = append(s.deadlocks, report)
+    if len(s.deadlocks) > DEADLOCK_REPORT_BUFFER {
+        s.deadlocks = s.deadlocks[len(s.deadlocks)-DEADLOCK_REPORT_BUFFER:]
+    }
+}
+
+func (s *Simulation) GetDeadlockReports() []DeadlockReport {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+    return append([]DeadlockReport(nil), s.deadlocks...)
+}
+
+func goroutineDump() string {
+    buf := make([]byte, 1<<16)
+    for {
+        n := runtime.Stack(buf, true)
+        if n < len(buf) {
+            return string(buf[:n])
+        }
+        buf = make([]byte, 2*len(buf))
+    }
+}
+
+func (s *Simulation) StopMonitor() {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+    if s.stopMonitor != nil {
+        s.stopMonitor()
+        s.stopMonitor = nil
+    }
+}
+
+func (s *Simulation) checkDeadlock(monitor *deadlockMonitor) []string {
+    var evidence []string
+
+    goroutines := runtime.NumGoroutine()
+    if goroutines > monitor.goroutines {
+        monitor.growth++
+    } else {
+        monitor.growth = 0
+    }
+    monitor.goroutines = goroutines
+    if monitor.growth >= GOROUTINE_GROWTH_CHECKS {
+        evidence = append(evidence, fmt.Sprintf("las goroutines crecieron %d revisiones seguidas (%d)", monitor.growth, goroutines))
+        monitor.growth = 0
+    }
+
+    if !s.IsRunning() {
+        return evidence
+    }
+
+    parked := 0
+    for _, space := range s.GetSpaces() {
+        if space.OccupiedBy != nil {
+            parked++
+        }
+    }
+    queueLength := s.GetQueueLength()
+    available := s.parking.GetAvailableSpaces()
+
+    state := parked + queueLength
+    if state == monitor.state && queueLength > 0 && available > 0 {
+        monitor.stalled++
+    } else {
+        monitor.stalled = 0
+    }
+    monitor.state = state
+    if monitor.stalled == DEADLOCK_SUSPECT_CHECKS {
+        evidence = append(evidence, fmt.Sprintf("%d vehículos en cola y %d espacios libres, sin cambios", queueLength, available))
+    }
+
+    // Cada vehículo con espacio asignado tiene su goroutine en processVehicle.
+    workers := int(atomic.LoadInt64(&s.metrics.ActiveWorkers))
+    if parked > workers {
+        monitor.orphaned++
+    } else {
+        monitor.orphaned = 0
+    }
+    if monitor.orphaned == DEADLOCK_SUSPECT_CHECKS {
+        evidence = append(evidence, fmt.Sprintf("%d vehículos con espacio pero solo %d goroutines activas", parked, workers))
+    }
+    return evidence
+}
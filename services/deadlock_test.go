@@ -0,0 +1,80 @@
+package services
+
+import (
+    "strings"
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+func TestMonitorDeadlocksReportsStall(t *testing.T) {
+    tests := []struct {
+        name     string
+        stall    func(s *Simulation)
+        evidence string
+    }{
+        {
+            // La goroutine de la cola deja de correr con un vehículo esperando
+            // y espacios libres.
+            name: "cola detenida",
+            stall: func(s *Simulation) {
+                s.stopQueue()
+                <-s.queueDone
+                vehicle := models.NewVehicle(1000)
+                s.queueMutex.Lock()
+                s.queue = append(s.queue, vehicle)
+                s.queueMutex.Unlock()
+            },
+            evidence: "en cola",
+        },
+        {
+            // Un vehículo ocupa un espacio sin la goroutine de processVehicle
+            // que lo sacaría.
+            name: "vehículo sin goroutine",
+            stall: func(s *Simulation) {
+                if !s.parking.TryEnter(models.NewVehicle(1000)) {
+                    t.Fatal("TryEnter falló con el estacionamiento vacío")
+                }
+            },
+            evidence: "goroutines activas",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.ArrivalRate = 0.001
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            defer sim.Stop()
+
+            reports := sim.MonitorDeadlocks(20 * time.Millisecond)
+            tt.stall(sim)
+
+            timeout := time.After(2 * time.Second)
+            for {
+                select {
+                case report := <-reports:
+                    if strings.Contains(report.Evidence, tt.evidence) {
+                        if report.Stack == "" {
+                            t.Error("el reporte no trae el volcado de goroutines")
+                        }
+                        return
+                    }
+                case <-timeout:
+                    t.Fatalf("no llegó un reporte con %q", tt.evidence)
+                }
+            }
+        })
+    }
+}
+
+func TestMonitorDeadlocksInvalidInterval(t *testing.T) {
+    sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+    for _, interval := range []time.Duration{0, -time.Second} {
+        if _, ok := <-sim.MonitorDeadlocks(interval); ok {
+            t.Errorf("MonitorDeadlocks(%v): el canal debería estar cerrado", interval)
+        }
+    }
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+    "fmt"
+    "math/rand"
+    "sort"
+    "sync"
+    "sync/atomic"
+    "time"
+)
+
+const (
+    DEFAULT_CLUSTER_SIZE   = 5
+    DEFAULT_CLUSTER_WINDOW = 2.0
+)
+
+// departurePlan guarda las salidas programadas de los vehículos
+// estacionados para detectar ráfagas de salidas que saturan la pluma.
+type departurePlan struct {
+    mu         sync.Mutex
+    times      []time.Time
+    clusterEnd time.Time
+}
+
+// schedule agrega una salida y devuelve cuántas salidas caen en la ventana de
+// ancho window centrada en ella, y si con esta se abre una ráfaga nueva.
+func (d *departurePlan) schedule(at time.Time, window time.Duration, size int) (int, bool) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+
+    now := time.Now()
+    pending := d.times[:0]
+    for _, t := range d.times {
+        if t.After(now) {
+            pending = append(pending, t)
+        }
+    }
+    index := sort.Search(len(pending), func(i int) bool { return pending[i].After(at) })
+    pending = append(pending, time.Time{})
+    copy(pending[index+1:], pending[index:])
+    pending[index] = at
+    d.times = pending
+
+    half := window / 2
+    count := 0
+    for _, t := range d.times {
+        if !t.Before(at.Add(-half)) && !t.After(at.Add(half)) {
+            count++
+        }
+    }
+    if count <= size || at.Before(d.clusterEnd) {
+        return count, false
+    }
+    d.clusterEnd = at.Add(half)
+    return count, true
+}
+
+func (d *departurePlan) reset() {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    d.clusterEnd = time.Time{}
+}
+
+func (s *Simulation) planDeparture(at time.Time) {
+    size := s.config.ClusterSize
+    if size <= 0 {
+        return
+    }
+    window := time.Duration(s.config.ClusterWindow * float64(time.Second))
+    count, cluster := s.departures.schedule(at, window, size)
+    if !cluster {
+        return
+    }
+    atomic.AddInt64(&s.metrics.ExitClusters, 1)
+    if s.updateUI != nil {
+        s.updateUI(int(s.parking.GetAvailableSpaces()), fmt.Sprintf("Aviso: %d salidas programadas en %.0f s", count, s.config.ClusterWindow))
+    }
+}
+
+// applyJitter suma una variación uniforme en ±DepartureJitter segundos a la
+// estancia para separar salidas que coinciden.
+func (s *Simulation) applyJitter(stay time.Duration) time.Duration {
+    if s.config.DepartureJitter <= 0 {
+        return stay
+    }
+    jitter := (rand.Float64()*2 - 1) * s.config.DepartureJitter
+    stay += time.Duration(jitter * float64(time.Second))
+    if stay < 0 {
+        return 0
+    }
+    return stay
+}
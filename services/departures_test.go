@@ -0,0 +1,104 @@
+package services
+
+import (
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestPlanDepartureDetectsClusters(t *testing.T) {
+    tests := []struct {
+        name   string
+        size   int
+        window float64
+        // offsets son las salidas, en segundos desde un instante futuro, en
+        // el orden en que se programan.
+        offsets      []float64
+        wantClusters int64
+    }{
+        {"salidas espaciadas", 3, 2, []float64{0, 3, 6, 9, 12}, 0},
+        {"justo en el tamaño", 3, 2, []float64{0, 0.2, 0.4}, 0},
+        {"una ráfaga", 3, 2, []float64{0, 0.2, 0.4, 0.6}, 1},
+        {"la ráfaga no se cuenta de nuevo", 3, 2, []float64{0, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6}, 1},
+        {"dos ráfagas separadas", 3, 2, []float64{0, 0.1, 0.2, 0.3, 10, 10.1, 10.2, 10.3}, 2},
+        {"programadas en desorden", 3, 2, []float64{0.6, 0, 5, 0.4, 0.2}, 1},
+        {"detección apagada", 0, 2, []float64{0, 0, 0, 0, 0}, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.ClusterSize = tt.size
+            config.ClusterWindow = tt.window
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            base := time.Now().Add(time.Minute)
+            for _, offset := range tt.offsets {
+                sim.planDeparture(base.Add(time.Duration(offset * float64(time.Second))))
+            }
+            if got := atomic.LoadInt64(&sim.metrics.ExitClusters); got != tt.wantClusters {
+                t.Errorf("ráfagas = %d, want %d", got, tt.wantClusters)
+            }
+        })
+    }
+}
+
+func TestApplyJitter(t *testing.T) {
+    tests := []struct {
+        name   string
+        jitter float64
+        stay   time.Duration
+    }{
+        {"sin variación", 0, 10 * time.Second},
+        {"variación de un segundo", 1, 10 * time.Second},
+        {"variación mayor que la estancia", 5, time.Second},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.DepartureJitter = tt.jitter
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            limit := time.Duration(tt.jitter * float64(time.Second))
+            distinct := map[time.Duration]bool{}
+            for i := 0; i < 200; i++ {
+                got := sim.applyJitter(tt.stay)
+                if got < 0 || got < tt.stay-limit || got > tt.stay+limit {
+                    t.Fatalf("applyJitter(%v) = %v, fuera de [max(0, %v), %v]", tt.stay, got, tt.stay-limit, tt.stay+limit)
+                }
+                distinct[got] = true
+            }
+            if tt.jitter == 0 && len(distinct) != 1 {
+                t.Errorf("sin variación dio %d estancias distintas", len(distinct))
+            }
+            if tt.jitter > 0 && len(distinct) < 100 {
+                t.Errorf("con variación solo dio %d estancias distintas de 200", len(distinct))
+            }
+        })
+    }
+}
+
+func TestJitterBreaksUpClusters(t *testing.T) {
+    tests := []struct {
+        name         string
+        jitter       float64
+        wantClusters bool
+    }{
+        {"estancias idénticas", 0, true},
+        {"con variación", 30, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.ClusterSize = 5
+            config.ClusterWindow = 1
+            config.DepartureJitter = tt.jitter
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            // Diez vehículos que entran juntos con la misma estancia.
+            now := time.Now()
+            for i := 0; i < 10; i++ {
+                sim.planDeparture(now.Add(sim.applyJitter(time.Minute)))
+            }
+            if got := atomic.LoadInt64(&sim.metrics.ExitClusters) > 0; got != tt.wantClusters {
+                t.Errorf("hubo ráfaga = %v, want %v", got, tt.wantClusters)
+            }
+        })
+    }
+}
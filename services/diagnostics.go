@@ -0,0 +1,101 @@
+package services
+
+import (
+    "archive/zip"
+    "encoding/json"
+    "io"
+    "runtime"
+    "strings"
+    "time"
+)
+
+// DIAGNOSTIC_LOG_LINES es cuántas líneas del log se incluyen en el paquete.
+const DIAGNOSTIC_LOG_LINES = 500
+
+type diagnosticMetadata struct {
+    CreatedAt      time.Time `json:"createdAt"`
+    GoVersion      string    `json:"goVersion"`
+    Seed           int64     `json:"seed"`
+    RNGBackend     string    `json:"rngBackend"`
+    Phase          string    `json:"phase"`
+    Running        bool      `json:"running"`
+    StatisticsFrom time.Time `json:"statisticsFrom"`
+    Goroutines     int       `json:"goroutines"`
+}
+
+type diagnosticFile struct {
+    name  string
+    write func(w io.Writer) error
+}
+
+// WriteDiagnosticBundle escribe en w un zip con lo necesario para reportar
+// un problema: configuración, semilla y estado de la corrida, las últimas
+// líneas del log, los cruces de la pluma, el historial de espacios, la serie
+// de generados y admitidos, las métricas de /debug/vars, un volcado de
+// goroutines, la trayectoria de λ si hay control de ocupación y, si el
+// monitor de bloqueos reportó algo, sus reportes.
+func (s *Simulation) WriteDiagnosticBundle(w io.Writer, log []string) error {
+    if len(log) > DIAGNOSTIC_LOG_LINES {
+        log = log[len(log)-DIAGNOSTIC_LOG_LINES:]
+    }
+    random := s.poissonGen.GetOriginalConfig()
+    metadata := diagnosticMetadata{
+        CreatedAt:      time.Now(),
+        GoVersion:      runtime.Version(),
+        Seed:           random.RandomSeed,
+        RNGBackend:     random.RNGBackend,
+        Phase:          s.GetPhase().String(),
+        Running:        s.IsRunning(),
+        StatisticsFrom: s.GetStatisticsSince(),
+        Goroutines:     runtime.NumGoroutine(),
+    }
+
+    files := []diagnosticFile{
+        {"config.json", jsonWriter(s.GetConfig())},
+        {"metadata.json", jsonWriter(metadata)},
+        {"log.txt", func(w io.Writer) error {
+            _, err := io.WriteString(w, strings.Join(log, "\n")+"\n")
+            return err
+        }},
+        {"gate_crossings.csv", func(w io.Writer) error {
+            _, err := s.parking.WriteGateCrossingsTo(w)
+            return err
+        }},
+        {"space_history.csv", func(w io.Writer) error {
+            _, err := s.parking.WriteHistoryTo(w)
+            return err
+        }},
+        {"intake.csv", s.WriteIntakeSeries},
+        {"expvar.json", jsonWriter(s.expvarSnapshot())},
+        {"goroutines.txt", func(w io.Writer) error {
+            _, err := io.WriteString(w, goroutineDump())
+            return err
+        }},
+    }
+    if s.GetConfig().OccupancyControl.IsSet() {
+        files = append(files, diagnosticFile{"lambda.json", jsonWriter(s.GetLambdaTrajectory())})
+    }
+    if reports := s.GetDeadlockReports(); len(reports) > 0 {
+        files = append(files, diagnosticFile{"deadlocks.json", jsonWriter(reports)})
+    }
+
+    archive := zip.NewWriter(w)
+    for _, file := range files {
+        entry, err := archive.Create(file.name)
+        if err != nil {
+            return err
+        }
+        if err := file.write(entry); err != nil {
+            return err
+        }
+    }
+    return archive.Close()
+}
+
+func jsonWriter(value any) func(w io.Writer) error {
+    return func(w io.Writer) error {
+        encoder := json.NewEncoder(w)
+        encoder.SetIndent("", "  ")
+        return encoder.Encode(value)
+    }
+}
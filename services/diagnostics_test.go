@@ -0,0 +1,93 @@
+package services
+
+import (
+    "archive/zip"
+    "bytes"
+    "encoding/csv"
+    "encoding/json"
+    "io"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestWriteDiagnosticBundleFilesAreParseable(t *testing.T) {
+    base := []string{
+        "config.json", "metadata.json", "log.txt", "gate_crossings.csv",
+        "space_history.csv", "intake.csv", "expvar.json", "goroutines.txt",
+    }
+    tests := []struct {
+        name      string
+        control   OccupancyControl
+        deadlock  bool
+        log       []string
+        wantExtra []string
+        wantLines int
+    }{
+        {"configuración por defecto", OccupancyControl{}, false, []string{"uno", "dos"}, nil, 2},
+        {"con control de ocupación", OccupancyControl{Target: 0.8, Gain: 1, MinRate: 0.5, MaxRate: 5}, false, []string{"uno"}, []string{"lambda.json"}, 1},
+        {"con reporte de bloqueo", OccupancyControl{}, true, []string{"uno"}, []string{"deadlocks.json"}, 1},
+        {"log recortado", OccupancyControl{}, false, make([]string, DIAGNOSTIC_LOG_LINES+10), nil, DIAGNOSTIC_LOG_LINES},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.OccupancyControl = tt.control
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if tt.deadlock {
+                sim.keepDeadlockReport(DeadlockReport{SuspectedAt: time.Now(), Evidence: "prueba"})
+            }
+
+            var buf bytes.Buffer
+            if err := sim.WriteDiagnosticBundle(&buf, tt.log); err != nil {
+                t.Fatalf("WriteDiagnosticBundle: %v", err)
+            }
+            archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+            if err != nil {
+                t.Fatalf("zip.NewReader: %v", err)
+            }
+
+            contents := map[string][]byte{}
+            for _, file := range archive.File {
+                r, err := file.Open()
+                if err != nil {
+                    t.Fatalf("abrir %s: %v", file.Name, err)
+                }
+                data, err := io.ReadAll(r)
+                r.Close()
+                if err != nil {
+                    t.Fatalf("leer %s: %v", file.Name, err)
+                }
+                contents[file.Name] = data
+            }
+            want := append(append([]string(nil), base...), tt.wantExtra...)
+            if len(contents) != len(want) {
+                t.Errorf("archivos = %d, want %d", len(contents), len(want))
+            }
+            for _, name := range want {
+                data, ok := contents[name]
+                if !ok {
+                    t.Errorf("falta %s", name)
+                    continue
+                }
+                switch {
+                case strings.HasSuffix(name, ".json"):
+                    var value any
+                    if err := json.Unmarshal(data, &value); err != nil {
+                        t.Errorf("%s no es JSON válido: %v", name, err)
+                    }
+                case strings.HasSuffix(name, ".csv"):
+                    rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+                    if err != nil {
+                        t.Errorf("%s no es CSV válido: %v", name, err)
+                    } else if len(rows) == 0 {
+                        t.Errorf("%s no tiene encabezado", name)
+                    }
+                }
+            }
+            if lines := strings.Count(string(contents["log.txt"]), "\n"); lines != tt.wantLines {
+                t.Errorf("líneas del log = %d, want %d", lines, tt.wantLines)
+            }
+        })
+    }
+}
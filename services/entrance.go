@@ -0,0 +1,47 @@
+package services
+
+import (
+    "sync/atomic"
+    "holafyne/models"
+)
+
+// CloseEntrance cierra la entrada: las llegadas se siguen generando pero se
+// rechazan o, si queueOutside es verdadero, esperan en la cola hasta que se
+// vuelva a abrir. Los vehículos que ya cruzaban la pluma terminan de entrar.
+func (s *Simulation) CloseEntrance(queueOutside bool) {
+    previous := s.queueOutside.Swap(queueOutside)
+    s.recordParamChange("Cola afuera", yesNo(previous), yesNo(queueOutside))
+    if s.entryClosed.Swap(true) {
+        return
+    }
+    s.recordParamChange("Entrada", "abierta", "cerrada")
+    s.notifyEntrance("Entrada cerrada")
+}
+
+func (s *Simulation) OpenEntrance() {
+    if !s.entryClosed.Swap(false) {
+        return
+    }
+    s.recordParamChange("Entrada", "cerrada", "abierta")
+    s.notifyEntrance("Entrada abierta")
+}
+
+func (s *Simulation) IsEntranceOpen() bool {
+    return !s.entryClosed.Load()
+}
+
+func (s *Simulation) notifyEntrance(message string) {
+    if s.updateUI != nil {
+        s.updateUI(int(s.parking.GetAvailableSpaces()), message)
+    }
+}
+
+// handleClosedEntrance decide qué pasa con un vehículo que llega con la
+// entrada cerrada.
+func (s *Simulation) handleClosedEntrance(vehicle *models.Vehicle) {
+    if s.queueOutside.Load() && s.addToQueue(vehicle) {
+        return
+    }
+    atomic.AddInt64(&s.metrics.EntranceRejected, 1)
+    s.reject(vehicle)
+}
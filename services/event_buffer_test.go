@@ -0,0 +1,116 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+func TestEventBufferOverflowWithSlowConsumer(t *testing.T) {
+    sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+    if err := sim.SetEventBufferSize(1); err != nil {
+        t.Fatal(err)
+    }
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    events := sim.WatchQueue(ctx)
+
+    // Nadie lee mientras se encolan cinco vehículos: solo cabe el primero
+    for id := 1; id <= 5; id++ {
+        sim.addToQueue(models.NewVehicle(id))
+    }
+    if dropped := sim.GetMetrics().DroppedEvents; dropped != 4 {
+        t.Fatalf("DroppedEvents = %d, want 4", dropped)
+    }
+
+    first := receiveEvent(t, events)
+    if first.Changed.ID != 1 || first.Dropped != 0 {
+        t.Errorf("primer evento: vehículo %d, Dropped %d; want vehículo 1, Dropped 0", first.Changed.ID, first.Dropped)
+    }
+
+    // El siguiente evento entregado avisa de los cuatro perdidos
+    sim.addToQueue(models.NewVehicle(6))
+    next := receiveEvent(t, events)
+    if next.Changed.ID != 6 || next.Dropped != 4 {
+        t.Errorf("siguiente evento: vehículo %d, Dropped %d; want vehículo 6, Dropped 4", next.Changed.ID, next.Dropped)
+    }
+    if got := len(sim.GetQueuedVehicles()); got != 6 {
+        t.Errorf("GetQueuedVehicles() tiene %d vehículos, want 6", got)
+    }
+}
+
+func TestSetEventBufferSize(t *testing.T) {
+    tests := []struct {
+        name    string
+        size    int
+        running bool
+        wantErr bool
+        is      error
+    }{
+        {"válido", 10, false, false, nil},
+        {"cero", 0, false, true, nil},
+        {"negativo", -1, false, true, nil},
+        {"en ejecución", 10, true, true, ErrSimulationRunning},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+            if tt.running {
+                if err := sim.Start(); err != nil {
+                    t.Fatal(err)
+                }
+                defer sim.Stop()
+            }
+            err := sim.SetEventBufferSize(tt.size)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("SetEventBufferSize(%d) = %v, wantErr %v", tt.size, err, tt.wantErr)
+            }
+            if tt.is != nil && !errors.Is(err, tt.is) {
+                t.Errorf("SetEventBufferSize(%d) = %v, want %v", tt.size, err, tt.is)
+            }
+            if !tt.wantErr && sim.GetConfig().EventBufferSize != tt.size {
+                t.Errorf("EventBufferSize = %d, want %d", sim.GetConfig().EventBufferSize, tt.size)
+            }
+        })
+    }
+}
+
+func TestDefaultEventBufferSize(t *testing.T) {
+    tests := []struct {
+        name   string
+        config func() SimulationConfig
+    }{
+        {"configuración por defecto", DefaultConfig},
+        {"tamaño sin indicar", func() SimulationConfig {
+            config := DefaultConfig()
+            config.EventBufferSize = 0
+            return config
+        }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sim := NewSimulationWithConfig(tt.config(), func(int, string) {})
+            if got := sim.GetConfig().EventBufferSize; got != 1000 {
+                t.Errorf("EventBufferSize = %d, want 1000", got)
+            }
+            ctx, cancel := context.WithCancel(context.Background())
+            defer cancel()
+            if got := cap(sim.WatchQueue(ctx)); got != 1000 {
+                t.Errorf("búfer del canal = %d, want 1000", got)
+            }
+        })
+    }
+}
+
+func receiveEvent(t *testing.T, events <-chan QueueChangeEvent) QueueChangeEvent {
+    t.Helper()
+    select {
+    case event := <-events:
+        return event
+    case <-time.After(time.Second):
+        t.Fatal("no llegó el evento")
+    }
+    return QueueChangeEvent{}
+}
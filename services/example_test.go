@@ -0,0 +1,116 @@
+package services_test
+
+import (
+    "context"
+    "fmt"
+    "time"
+    "holafyne/models"
+    "holafyne/services"
+)
+
+// fixedArrivals entrega n vehículos seguidos y luego espera a que termine la
+// corrida, como una fuente de llegadas propia.
+type fixedArrivals struct {
+    n, next int
+}
+
+func (a *fixedArrivals) Next(ctx context.Context) (*models.Vehicle, bool) {
+    if a.next < a.n {
+        a.next++
+        return models.NewVehicle(a.next), true
+    }
+    <-ctx.Done()
+    return nil, false
+}
+
+func ExampleSimulationConfig_Validate() {
+    config := services.DefaultConfig()
+    config.ParkingCapacity = 10
+    config.MaxQueueSize = 5
+    config.ArrivalRate = 0.5
+    fmt.Println(config.Validate())
+
+    config.ArrivalRate = 0
+    fmt.Println(config.Validate())
+    // Output:
+    // <nil>
+    // la tasa de llegadas debe ser positiva
+}
+
+func ExampleSimulation_SetArrivalSource() {
+    config := services.DefaultConfig()
+    config.ParkingCapacity = 10
+    config.MinParkTime = 0.05
+    config.MaxParkTime = 0.05
+    sim := services.NewSimulationWithConfig(config, func(int, string) {})
+    if err := sim.SetArrivalSource(&fixedArrivals{n: 5}); err != nil {
+        fmt.Println(err)
+        return
+    }
+
+    if err := sim.Start(); err != nil {
+        fmt.Println(err)
+        return
+    }
+    // Sin interfaz: se cortan las llegadas y se espera a que se vacíe.
+    time.Sleep(200 * time.Millisecond)
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    fmt.Println(sim.RunUntilEmpty(ctx))
+
+    metrics := sim.GetMetrics()
+    fmt.Println("llegadas:", metrics.TotalArrivals, "entraron:", metrics.TotalEntered, "salieron:", metrics.TotalExited)
+    // Output:
+    // <nil>
+    // llegadas: 5 entraron: 5 salieron: 5
+}
+
+func ExampleSimulation_WatchQueue() {
+    // Sin espacios, los vehículos se quedan en la cola.
+    config := services.DefaultConfig()
+    config.ParkingCapacity = 0
+    config.MaxQueueSize = 2
+    sim := services.NewSimulationWithConfig(config, func(int, string) {})
+    sim.SetArrivalSource(&fixedArrivals{n: 2})
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    events := sim.WatchQueue(ctx)
+    if err := sim.Start(); err != nil {
+        fmt.Println(err)
+        return
+    }
+    for i := 0; i < 2; i++ {
+        event := <-events
+        fmt.Printf("vehículo %d %s, largo %d\n", event.Changed.ID, event.ChangeType, event.CurrentLen)
+    }
+    sim.Stop()
+    // Output:
+    // vehículo 1 entró a la cola, largo 1
+    // vehículo 2 entró a la cola, largo 2
+}
+
+func ExampleSimulation_GetRunSummary() {
+    config := services.DefaultConfig()
+    config.ParkingCapacity = 2
+    config.MaxQueueSize = 0
+    config.MinParkTime = 60
+    config.MaxParkTime = 60
+    sim := services.NewSimulationWithConfig(config, func(int, string) {})
+    sim.SetArrivalSource(&fixedArrivals{n: 3})
+    if err := sim.Start(); err != nil {
+        fmt.Println(err)
+        return
+    }
+    time.Sleep(300 * time.Millisecond)
+    sim.Stop()
+
+    summary := sim.GetRunSummary()
+    fmt.Print(services.FormatSummaryTable(summary[:3], false))
+    // Output:
+    // Indicador   Valor
+    // ----------  -----
+    // Llegadas        3
+    // Entraron        2
+    // Rechazados      1
+}
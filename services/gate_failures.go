@@ -0,0 +1,119 @@
+package services
+
+import (
+    "errors"
+    "math/rand"
+    "sync/atomic"
+    "time"
+)
+
+// GateFailureConfig describe las fallas de la pluma: MTBF es el tiempo medio
+// entre fallas en segundos (cero las desactiva) y la reparación sigue la
+// distribución indicada, exponencial con RepairMean o uniforme entre
+// RepairMin y RepairMax.
+type GateFailureConfig struct {
+    MTBF               float64
+    RepairDistribution string
+    RepairMean         float64
+    RepairMin          float64
+    RepairMax          float64
+}
+
+func (c GateFailureConfig) validate() error {
+    if c.MTBF < 0 {
+        return errors.New("el tiempo medio entre fallas no puede ser negativo")
+    }
+    if c.MTBF == 0 {
+        return nil
+    }
+    if c.RepairDistribution == DISTRIBUTION_UNIFORM {
+        if c.RepairMin < 0 || c.RepairMax < c.RepairMin {
+            return errors.New("el rango del tiempo de reparación no es válido")
+        }
+        return nil
+    }
+    if c.RepairMean <= 0 {
+        return errors.New("el tiempo medio de reparación debe ser positivo")
+    }
+    return nil
+}
+
+func (c GateFailureConfig) sampleRepair(rng *rand.Rand) time.Duration {
+    var seconds float64
+    if c.RepairDistribution == DISTRIBUTION_UNIFORM {
+        seconds = c.RepairMin + rng.Float64()*(c.RepairMax-c.RepairMin)
+    } else {
+        seconds = rng.ExpFloat64() * c.RepairMean
+    }
+    return time.Duration(seconds * float64(time.Second))
+}
+
+// runGateFailures alterna periodos en servicio y fuera de servicio hasta que
+// se detienen las llegadas. Al salir repara la pluma, así la cola y las
+// salidas que Stop espera después no quedan bloqueadas.
+func (s *Simulation) runGateFailures(config GateFailureConfig) {
+    defer s.arrivalWg.Done()
+    defer s.RepairGate()
+
+    rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+    for {
+        uptime := time.Duration(rng.ExpFloat64() * config.MTBF * float64(time.Second))
+        if !s.sleepGateTimer(uptime) {
+            return
+        }
+        s.FailGate()
+        if !s.sleepGateTimer(config.sampleRepair(rng)) {
+            return
+        }
+        s.RepairGate()
+    }
+}
+
+func (s *Simulation) sleepGateTimer(d time.Duration) bool {
+    return s.sleepPausable(s.arrivalCtx, d)
+}
+
+// FailGate deja la pluma fuera de servicio: se congelan las entradas y las
+// salidas, pero los vehículos estacionados siguen con su estancia. Stop la
+// repara antes de esperar a los vehículos.
+func (s *Simulation) FailGate() {
+    if !s.parking.FailGate() {
+        return
+    }
+    atomic.AddInt64(&s.metrics.GateFailures, 1)
+    s.notifyEntrance("🔧 Pluma fuera de servicio")
+    s.notifyGateFailure(true)
+}
+
+func (s *Simulation) RepairGate() {
+    if !s.parking.RepairGate() {
+        return
+    }
+    s.notifyEntrance("Pluma reparada")
+    s.notifyGateFailure(false)
+}
+
+func (s *Simulation) IsGateOutOfService() bool {
+    return s.parking.IsGateOutOfService()
+}
+
+func (s *Simulation) GetGateDowntime() time.Duration {
+    return s.parking.GetGateDowntime()
+}
+
+// SetGateFailureCallback registra la función que se llama cuando la pluma
+// falla y cuando se repara.
+func (s *Simulation) SetGateFailureCallback(callback func(outOfService bool)) {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+    s.onGateFailure = callback
+}
+
+func (s *Simulation) notifyGateFailure(outOfService bool) {
+    s.stateMutex.Lock()
+    callback := s.onGateFailure
+    s.stateMutex.Unlock()
+    if callback != nil {
+        callback(outOfService)
+    }
+}
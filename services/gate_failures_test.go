@@ -0,0 +1,146 @@
+package services
+
+import (
+    "context"
+    "math"
+    "math/rand"
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+// channelArrivals entrega los vehículos que el test manda por el canal.
+type channelArrivals chan *models.Vehicle
+
+func (a channelArrivals) Next(ctx context.Context) (*models.Vehicle, bool) {
+    select {
+    case <-ctx.Done():
+        return nil, false
+    case vehicle := <-a:
+        return vehicle, true
+    }
+}
+
+func TestScriptedGateFailureWindows(t *testing.T) {
+    tests := []struct {
+        name    string
+        windows []time.Duration
+    }{
+        {"una falla", []time.Duration{200 * time.Millisecond}},
+        {"dos fallas", []time.Duration{150 * time.Millisecond, 100 * time.Millisecond}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := drainConfig(30, 60)
+            config.ParkingCapacity = 10
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            arrivals := make(channelArrivals)
+            if err := sim.SetArrivalSource(arrivals); err != nil {
+                t.Fatal(err)
+            }
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            defer sim.Stop()
+
+            id := 0
+            var downtime time.Duration
+            for i, window := range tt.windows {
+                id++
+                arrivals <- models.NewVehicle(id)
+                if !waitForCounter(&sim.metrics.TotalEntered, int64(id)) {
+                    t.Fatalf("falla %d: el vehículo %d no entró con la pluma en servicio", i+1, id)
+                }
+                parked := sim.GetOccupancy()
+
+                sim.FailGate()
+                if !sim.IsGateOutOfService() {
+                    t.Fatalf("falla %d: la pluma sigue en servicio", i+1)
+                }
+                id++
+                arrivals <- models.NewVehicle(id)
+                time.Sleep(window)
+                if entered := sim.GetMetrics().TotalEntered; entered != int64(id-1) {
+                    t.Errorf("falla %d: entradas = %d con la pluma fuera de servicio, want %d", i+1, entered, id-1)
+                }
+                // El que espera la pluma ya tiene su espacio apartado.
+                if occupancy := sim.GetOccupancy(); occupancy != parked+1 {
+                    t.Errorf("falla %d: ocupación = %d, want %d", i+1, occupancy, parked+1)
+                }
+                if exited := sim.GetMetrics().TotalExited; exited != 0 {
+                    t.Errorf("falla %d: salidas = %d, want 0: los estacionados siguen su estancia", i+1, exited)
+                }
+
+                sim.RepairGate()
+                downtime += window
+                if !waitForCounter(&sim.metrics.TotalEntered, int64(id)) {
+                    t.Fatalf("falla %d: el vehículo %d no entró después de la reparación", i+1, id)
+                }
+            }
+
+            if failures := sim.GetMetrics().GateFailures; failures != int64(len(tt.windows)) {
+                t.Errorf("fallas = %d, want %d", failures, len(tt.windows))
+            }
+            if got := sim.GetGateDowntime(); got < downtime || got > downtime+100*time.Millisecond*time.Duration(len(tt.windows)) {
+                t.Errorf("tiempo fuera de servicio = %v, want cerca de %v", got, downtime)
+            }
+        })
+    }
+}
+
+func TestStopRepairsFailedGate(t *testing.T) {
+    config := drainConfig(30, 60)
+    sim := NewSimulationWithConfig(config, func(int, string) {})
+    if err := sim.Start(); err != nil {
+        t.Fatal(err)
+    }
+    time.Sleep(200 * time.Millisecond)
+    sim.FailGate()
+    time.Sleep(100 * time.Millisecond)
+
+    stopped := make(chan struct{})
+    go func() {
+        sim.Stop()
+        close(stopped)
+    }()
+    select {
+    case <-stopped:
+    case <-time.After(5 * time.Second):
+        t.Fatal("Stop no terminó con la pluma fuera de servicio")
+    }
+    if sim.IsGateOutOfService() {
+        t.Error("la pluma quedó fuera de servicio después de Stop")
+    }
+}
+
+func TestSampleRepair(t *testing.T) {
+    const samples = 20000
+    tests := []struct {
+        name     string
+        config   GateFailureConfig
+        wantMean float64
+        min, max float64
+    }{
+        {"exponencial", GateFailureConfig{MTBF: 60, RepairMean: 5}, 5, 0, math.Inf(1)},
+        {"uniforme", GateFailureConfig{MTBF: 60, RepairDistribution: DISTRIBUTION_UNIFORM, RepairMin: 2, RepairMax: 6}, 4, 2, 6},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if err := tt.config.validate(); err != nil {
+                t.Fatal(err)
+            }
+            rng := rand.New(rand.NewSource(1))
+            total := 0.0
+            for i := 0; i < samples; i++ {
+                seconds := tt.config.sampleRepair(rng).Seconds()
+                if seconds < tt.min || seconds > tt.max {
+                    t.Fatalf("reparación de %.3fs fuera de [%v, %v]", seconds, tt.min, tt.max)
+                }
+                total += seconds
+            }
+            if mean := total / samples; math.Abs(mean-tt.wantMean) > 0.05*tt.wantMean {
+                t.Errorf("media = %.3fs, want %.3fs", mean, tt.wantMean)
+            }
+        })
+    }
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+    "encoding/csv"
+    "io"
+    "strconv"
+    "sync"
+    "sync/atomic"
+    "time"
+)
+
+const (
+    INTAKE_SAMPLE_INTERVAL = 500 * time.Millisecond
+    MAX_INTAKE_SAMPLES     = 3600
+)
+
+// IntakeSample es una foto de los contadores: cuántos vehículos se generaron
+// (TotalArrivals) y cuántos se admitieron (TotalEntered) hasta SimTime, el
+// tiempo simulado desde que se reiniciaron las estadísticas. La diferencia
+// es la demanda rechazada, abandonada o todavía en la cola.
+type IntakeSample struct {
+    SimTime   time.Duration
+    Generated int64
+    Admitted  int64
+}
+
+type intakeSeries struct {
+    mu      sync.Mutex
+    simTime time.Duration
+    samples []IntakeSample
+}
+
+// advance suma d de tiempo simulado y guarda la foto de los contadores.
+func (t *intakeSeries) advance(d time.Duration, generated, admitted int64) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    t.simTime += d
+    t.samples = append(t.samples, IntakeSample{SimTime: t.simTime, Generated: generated, Admitted: admitted})
+    if len(t.samples) > MAX_INTAKE_SAMPLES {
+        t.samples = t.samples[len(t.samples)-MAX_INTAKE_SAMPLES:]
+    }
+}
+
+func (t *intakeSeries) reset() {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    t.simTime = 0
+    t.samples = nil
+}
+
+// runIntakeSampler toma una foto de los contadores cada
+// INTAKE_SAMPLE_INTERVAL hasta que se detiene la simulación, también
+// mientras la cola se vacía después de cortar las llegadas. En pausa no
+// avanza el tiempo simulado ni se toman fotos.
+func (s *Simulation) runIntakeSampler() {
+    defer s.wg.Done()
+
+    s.sampleIntake(0)
+    ticker := time.NewTicker(INTAKE_SAMPLE_INTERVAL)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-s.ctx.Done():
+            return
+        case <-ticker.C:
+            if !s.IsPaused() {
+                s.sampleIntake(time.Duration(float64(INTAKE_SAMPLE_INTERVAL) * s.GetSpeed()))
+            }
+        }
+    }
+}
+
+func (s *Simulation) sampleIntake(d time.Duration) {
+    s.intake.advance(d, atomic.LoadInt64(&s.metrics.TotalArrivals), atomic.LoadInt64(&s.metrics.TotalEntered))
+}
+
+// GetIntakeSeries devuelve las fotos de generados y admitidos en orden.
+func (s *Simulation) GetIntakeSeries() []IntakeSample {
+    s.intake.mu.Lock()
+    defer s.intake.mu.Unlock()
+    return append([]IntakeSample(nil), s.intake.samples...)
+}
+
+// WriteIntakeSeries escribe en w un CSV con una fila por foto: el tiempo
+// simulado en segundos y los generados y admitidos acumulados.
+func (s *Simulation) WriteIntakeSeries(w io.Writer) error {
+    writer := csv.NewWriter(w)
+    writer.Write([]string{"simTime", "generated", "admitted"})
+    for _, sample := range s.GetIntakeSeries() {
+        writer.Write([]string{
+            strconv.FormatFloat(sample.SimTime.Seconds(), 'f', 3, 64),
+            strconv.FormatInt(sample.Generated, 10),
+            strconv.FormatInt(sample.Admitted, 10),
+        })
+    }
+    writer.Flush()
+    return writer.Error()
+}
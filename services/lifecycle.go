@@ -0,0 +1,99 @@
+package services
+
+import (
+    "context"
+    "fmt"
+    "sync"
+    "time"
+)
+
+// COMPONENT_STOP_TIMEOUT es lo que se espera a cada componente al detenerlo.
+const COMPONENT_STOP_TIMEOUT = 3 * time.Second
+
+type lifecycleComponent struct {
+    name  string
+    start func() error
+    stop  func(ctx context.Context) error
+}
+
+// Lifecycle arranca y detiene los componentes opcionales de la aplicación
+// (servidor de métricas, etc.). Un componente que no arranca no impide que
+// arranquen los demás, y se detienen en orden inverso.
+type Lifecycle struct {
+    mu          sync.Mutex
+    components  []lifecycleComponent
+    started     []lifecycleComponent
+    StopTimeout time.Duration
+}
+
+func NewLifecycle() *Lifecycle {
+    return &Lifecycle{StopTimeout: COMPONENT_STOP_TIMEOUT}
+}
+
+// Register agrega un componente. stop puede ser nil si no hay nada que
+// liberar.
+func (l *Lifecycle) Register(name string, start func() error, stop func(ctx context.Context) error) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    l.components = append(l.components, lifecycleComponent{name: name, start: start, stop: stop})
+}
+
+// Start arranca los componentes registrados que aún no arrancaron y devuelve
+// un error por cada uno que falló.
+func (l *Lifecycle) Start() []error {
+    l.mu.Lock()
+    pending := l.components
+    l.components = nil
+    l.mu.Unlock()
+
+    var errs []error
+    for _, component := range pending {
+        if err := component.start(); err != nil {
+            errs = append(errs, fmt.Errorf("%s: %w", component.name, err))
+            continue
+        }
+        l.mu.Lock()
+        l.started = append(l.started, component)
+        l.mu.Unlock()
+    }
+    return errs
+}
+
+// Stop detiene los componentes en orden inverso al de arranque, cada uno con
+// su propio límite de StopTimeout. Un componente que no termina a tiempo se
+// reporta y se sigue con el siguiente.
+func (l *Lifecycle) Stop() []error {
+    l.mu.Lock()
+    started := l.started
+    l.started = nil
+    timeout := l.StopTimeout
+    l.mu.Unlock()
+
+    var errs []error
+    for i := len(started) - 1; i >= 0; i-- {
+        component := started[i]
+        if component.stop == nil {
+            continue
+        }
+        if err := stopComponent(component, timeout); err != nil {
+            errs = append(errs, fmt.Errorf("%s: %w", component.name, err))
+        }
+    }
+    return errs
+}
+
+func stopComponent(component lifecycleComponent, timeout time.Duration) error {
+    ctx, cancel := context.WithTimeout(context.Background(), timeout)
+    defer cancel()
+
+    done := make(chan error, 1)
+    go func() {
+        done <- component.stop(ctx)
+    }()
+    select {
+    case err := <-done:
+        return err
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "reflect"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+)
+
+var errPortInUse = errors.New("address already in use")
+
+func TestLifecycleStopEnforcesTimeout(t *testing.T) {
+    const timeout = 50 * time.Millisecond
+    tests := []struct {
+        name string
+        // delays es lo que tarda en detenerse cada componente; un valor
+        // negativo indica que no arranca.
+        delays      []time.Duration
+        ignoresCtx  bool
+        wantStopped []string
+        wantErrs    []string
+    }{
+        {"todos a tiempo", []time.Duration{0, 0, 0}, false, []string{"c", "b", "a"}, nil},
+        {"uno lento respeta el contexto", []time.Duration{0, time.Second, 0}, false,
+            []string{"c", "a"}, []string{"b: context deadline exceeded"}},
+        {"uno lento ignora el contexto", []time.Duration{0, time.Second, 0}, true,
+            []string{"c", "a"}, []string{"b: context deadline exceeded"}},
+        {"el que no arrancó no se detiene", []time.Duration{0, -1, 0}, false, []string{"c", "a"}, nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lifecycle := NewLifecycle()
+            lifecycle.StopTimeout = timeout
+            var mu sync.Mutex
+            var stopped []string
+            for i, delay := range tt.delays {
+                name := string(rune('a' + i))
+                delay := delay
+                lifecycle.Register(name, func() error {
+                    if delay < 0 {
+                        return errPortInUse
+                    }
+                    return nil
+                }, func(ctx context.Context) error {
+                    if tt.ignoresCtx {
+                        time.Sleep(delay)
+                    } else {
+                        select {
+                        case <-time.After(delay):
+                        case <-ctx.Done():
+                            return ctx.Err()
+                        }
+                    }
+                    mu.Lock()
+                    stopped = append(stopped, name)
+                    mu.Unlock()
+                    return nil
+                })
+            }
+
+            startErrs := lifecycle.Start()
+            for i, delay := range tt.delays {
+                if delay >= 0 {
+                    continue
+                }
+                name := string(rune('a' + i))
+                if len(startErrs) != 1 || !errors.Is(startErrs[0], errPortInUse) || !strings.HasPrefix(startErrs[0].Error(), name+":") {
+                    t.Errorf("Start() = %v, want un error de %s", startErrs, name)
+                }
+            }
+
+            began := time.Now()
+            errs := lifecycle.Stop()
+            if elapsed := time.Since(began); elapsed > time.Duration(len(tt.delays))*timeout+100*time.Millisecond {
+                t.Errorf("Stop tardó %v, más que el límite por componente", elapsed)
+            }
+            var got []string
+            for _, err := range errs {
+                got = append(got, err.Error())
+            }
+            if !reflect.DeepEqual(got, tt.wantErrs) {
+                t.Errorf("Stop() = %q, want %q", got, tt.wantErrs)
+            }
+            mu.Lock()
+            defer mu.Unlock()
+            if !reflect.DeepEqual(stopped, tt.wantStopped) {
+                t.Errorf("detenidos = %v, want %v", stopped, tt.wantStopped)
+            }
+        })
+    }
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+    "encoding/json"
+    "expvar"
+    "net"
+    "net/http"
+    "sync"
+    "sync/atomic"
+    "time"
+    "holafyne/models"
+)
+
+type SimulationMetrics struct {
+    TotalArrivals    int64
+    TotalVehicles    int64
+    TotalEntered     int64
+    TotalExited      int64
+    TotalQueued      int64
+    TotalRejected    int64
+    TotalAbandoned   int64
+    ActiveWorkers    int64
+    DroppedEvents    int64
+    TotalRetries     int64
+    TotalDoubleParks int64
+    TotalStayFloors  int64
+    QueueMismatches  int64
+    ExitClusters     int64
+    TotalCancelled   int64
+    EntranceRejected int64
+    GateFailures     int64
+    SiteFull         int64
+    TotalPreexisting int64
+    TotalNoShows     int64
+}
+
+var (
+    publishOnce   sync.Once
+    activeMetrics atomic.Pointer[Simulation]
+)
+
+func (m *SimulationMetrics) Snapshot() SimulationMetrics {
+    return SimulationMetrics{
+        TotalArrivals:    atomic.LoadInt64(&m.TotalArrivals),
+        TotalVehicles:    atomic.LoadInt64(&m.TotalVehicles),
+        TotalEntered:     atomic.LoadInt64(&m.TotalEntered),
+        TotalExited:      atomic.LoadInt64(&m.TotalExited),
+        TotalQueued:      atomic.LoadInt64(&m.TotalQueued),
+        TotalRejected:    atomic.LoadInt64(&m.TotalRejected),
+        TotalAbandoned:   atomic.LoadInt64(&m.TotalAbandoned),
+        ActiveWorkers:    atomic.LoadInt64(&m.ActiveWorkers),
+        DroppedEvents:    atomic.LoadInt64(&m.DroppedEvents),
+        TotalRetries:     atomic.LoadInt64(&m.TotalRetries),
+        TotalDoubleParks: atomic.LoadInt64(&m.TotalDoubleParks),
+        TotalStayFloors:  atomic.LoadInt64(&m.TotalStayFloors),
+        QueueMismatches:  atomic.LoadInt64(&m.QueueMismatches),
+        ExitClusters:     atomic.LoadInt64(&m.ExitClusters),
+        TotalCancelled:   atomic.LoadInt64(&m.TotalCancelled),
+        EntranceRejected: atomic.LoadInt64(&m.EntranceRejected),
+        GateFailures:     atomic.LoadInt64(&m.GateFailures),
+        SiteFull:         atomic.LoadInt64(&m.SiteFull),
+        TotalPreexisting: atomic.LoadInt64(&m.TotalPreexisting),
+        TotalNoShows:     atomic.LoadInt64(&m.TotalNoShows),
+    }
+}
+
+// Reset pone en cero los contadores acumulados. ActiveWorkers es un valor
+// instantáneo y no se toca.
+func (m *SimulationMetrics) Reset() {
+    atomic.StoreInt64(&m.TotalArrivals, 0)
+    atomic.StoreInt64(&m.TotalVehicles, 0)
+    atomic.StoreInt64(&m.TotalEntered, 0)
+    atomic.StoreInt64(&m.TotalExited, 0)
+    atomic.StoreInt64(&m.TotalQueued, 0)
+    atomic.StoreInt64(&m.TotalRejected, 0)
+    atomic.StoreInt64(&m.TotalAbandoned, 0)
+    atomic.StoreInt64(&m.DroppedEvents, 0)
+    atomic.StoreInt64(&m.TotalRetries, 0)
+    atomic.StoreInt64(&m.TotalDoubleParks, 0)
+    atomic.StoreInt64(&m.TotalStayFloors, 0)
+    atomic.StoreInt64(&m.QueueMismatches, 0)
+    atomic.StoreInt64(&m.ExitClusters, 0)
+    atomic.StoreInt64(&m.TotalCancelled, 0)
+    atomic.StoreInt64(&m.EntranceRejected, 0)
+    atomic.StoreInt64(&m.GateFailures, 0)
+    atomic.StoreInt64(&m.SiteFull, 0)
+    atomic.StoreInt64(&m.TotalPreexisting, 0)
+    atomic.StoreInt64(&m.TotalNoShows, 0)
+}
+
+func (m SimulationMetrics) toMap() map[string]int64 {
+    return map[string]int64{
+        "total_arrivals":     m.TotalArrivals,
+        "total_vehicles":     m.TotalVehicles,
+        "total_entered":      m.TotalEntered,
+        "total_exited":       m.TotalExited,
+        "total_queued":       m.TotalQueued,
+        "total_rejected":     m.TotalRejected,
+        "total_abandoned":    m.TotalAbandoned,
+        "active_workers":     m.ActiveWorkers,
+        "dropped_events":     m.DroppedEvents,
+        "total_retries":      m.TotalRetries,
+        "total_double_parks": m.TotalDoubleParks,
+        "total_stay_floors":  m.TotalStayFloors,
+        "queue_mismatches":   m.QueueMismatches,
+        "exit_clusters":      m.ExitClusters,
+        "total_cancelled":    m.TotalCancelled,
+        "entrance_rejected":  m.EntranceRejected,
+        "gate_failures":      m.GateFailures,
+        "site_full":          m.SiteFull,
+        "total_preexisting":  m.TotalPreexisting,
+        "total_no_shows":     m.TotalNoShows,
+    }
+}
+
+// PublishExpvar expone las métricas de la simulación en /debug/vars.
+// expvar no permite registrar dos veces el mismo nombre, así que solo se
+// publica una variable que siempre lee de la última simulación registrada.
+func PublishExpvar(sim *Simulation) {
+    activeMetrics.Store(sim)
+    publishOnce.Do(func() {
+        expvar.Publish("simulation", expvar.Func(func() any {
+            current := activeMetrics.Load()
+            if current == nil {
+                return nil
+            }
+            return current.expvarSnapshot()
+        }))
+    })
+}
+
+// expvarSnapshot arma el mapa que se publica en /debug/vars.
+func (s *Simulation) expvarSnapshot() map[string]int64 {
+    metrics := s.metrics.Snapshot().toMap()
+    metrics["queue_length"] = int64(s.GetQueueLength())
+    metrics["gate_max_wait_entry_ms"] = s.GetGateMaxWait(models.GateEntry).Milliseconds()
+    metrics["gate_max_wait_exit_ms"] = s.GetGateMaxWait(models.GateExit).Milliseconds()
+    metrics["gate_downtime_ms"] = s.GetGateDowntime().Milliseconds()
+    metrics["vehicles_on_site"] = int64(s.GetVehiclesOnSite())
+    return metrics
+}
+
+// ReplaceExpvarSimulation apunta las métricas publicadas a otra simulación,
+// solo si PublishExpvar ya se llamó.
+func ReplaceExpvarSimulation(sim *Simulation) {
+    if activeMetrics.Load() != nil {
+        activeMetrics.Store(sim)
+    }
+}
+
+func StartMetricsServer(addr string) (*http.Server, error) {
+    listener, err := net.Listen("tcp", addr)
+    if err != nil {
+        return nil, err
+    }
+
+    mux := http.NewServeMux()
+    mux.Handle("/debug/vars", expvar.Handler())
+    mux.HandleFunc("/prediction", handlePrediction)
+    server := &http.Server{Handler: mux}
+    go server.Serve(listener)
+
+    return server, nil
+}
+
+// handlePrediction responde GET /prediction?at=5m con los espacios libres
+// previstos por la última simulación registrada dentro de at de tiempo
+// simulado.
+func handlePrediction(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        http.Error(w, "método no permitido", http.StatusMethodNotAllowed)
+        return
+    }
+    at, err := time.ParseDuration(r.URL.Query().Get("at"))
+    if err != nil || at < 0 {
+        http.Error(w, "parámetro at inválido", http.StatusBadRequest)
+        return
+    }
+    current := activeMetrics.Load()
+    if current == nil {
+        http.Error(w, "no hay simulación registrada", http.StatusServiceUnavailable)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]any{
+        "at_seconds":  at.Seconds(),
+        "free_spaces": current.GetFreeSpacePrediction(at),
+    })
+}
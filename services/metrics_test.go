@@ -0,0 +1,56 @@
+package services
+
+import (
+    "encoding/json"
+    "expvar"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestExpvarDuringRun(t *testing.T) {
+    sim := NewSimulationWithConfig(drainConfig(1, 2), func(int, string) {})
+    PublishExpvar(sim)
+    if err := sim.Start(); err != nil {
+        t.Fatal(err)
+    }
+    defer sim.Stop()
+    time.Sleep(300 * time.Millisecond)
+
+    recorder := httptest.NewRecorder()
+    expvar.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+    var vars map[string]json.RawMessage
+    if err := json.Unmarshal(recorder.Body.Bytes(), &vars); err != nil {
+        t.Fatalf("/debug/vars no es JSON válido: %v", err)
+    }
+    var metrics map[string]int64
+    if err := json.Unmarshal(vars["simulation"], &metrics); err != nil {
+        t.Fatalf("la variable simulation no es un mapa de contadores: %v", err)
+    }
+
+    tests := []struct {
+        key      string
+        positive bool
+    }{
+        {"total_arrivals", true},
+        {"total_entered", true},
+        {"total_rejected", false},
+        {"queue_length", false},
+        {"active_workers", false},
+        {"gate_max_wait_entry_ms", false},
+        {"gate_downtime_ms", false},
+        {"vehicles_on_site", false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.key, func(t *testing.T) {
+            value, ok := metrics[tt.key]
+            if !ok {
+                t.Fatalf("falta %s en /debug/vars", tt.key)
+            }
+            if tt.positive && value <= 0 {
+                t.Errorf("%s = %d, want > 0", tt.key, value)
+            }
+        })
+    }
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+    "errors"
+    "math/rand"
+    "sync"
+    "sync/atomic"
+    "time"
+    "holafyne/models"
+)
+
+const OUTCOME_NO_SHOW = "no se presentó"
+
+var ErrInvalidNoShow = errors.New("la probabilidad de no presentarse debe estar entre 0 y 1")
+
+// noShowFilter descarta una fracción de las llegadas generadas antes de que
+// lleguen a la pluma: el conductor cambió de idea en el camino. Con
+// probability en cero no descarta nada.
+type noShowFilter struct {
+    mu          sync.Mutex
+    probability float64
+    seed        int64
+    rng         *rand.Rand
+}
+
+// newNoShowFilter usa seed para que los descartes se puedan repetir; con
+// seed en cero toma la hora actual.
+func newNoShowFilter(probability float64, seed int64) *noShowFilter {
+    if seed == 0 {
+        seed = time.Now().UnixNano()
+    }
+    return &noShowFilter{probability: probability, seed: seed, rng: rand.New(rand.NewSource(seed))}
+}
+
+func validateNoShow(probability float64) error {
+    if probability < 0 || probability >= 1 {
+        return ErrInvalidNoShow
+    }
+    return nil
+}
+
+// skip indica si la siguiente llegada no se presenta.
+func (f *noShowFilter) skip() bool {
+    if f.probability <= 0 {
+        return false
+    }
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    return f.rng.Float64() < f.probability
+}
+
+// reseed cambia la semilla y vuelve a empezar su secuencia.
+func (f *noShowFilter) reseed(seed int64) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.seed = seed
+    f.rng = rand.New(rand.NewSource(seed))
+}
+
+// reset vuelve a la semilla inicial, para que un reinicio descarte las
+// mismas llegadas.
+func (f *noShowFilter) reset() {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.rng = rand.New(rand.NewSource(f.seed))
+}
+
+// recordNoShow cuenta la llegada en la demanda de la calle, pero no en las
+// llegadas a la entrada. En una población cerrada el vehículo vuelve a salir
+// como si hubiera terminado su visita.
+func (s *Simulation) recordNoShow(vehicle *models.Vehicle) {
+    atomic.AddInt64(&s.metrics.TotalNoShows, 1)
+    s.vehicleLog.record(vehicle, OUTCOME_NO_SHOW, 0, vehicle.GetTimeInState())
+    s.notifyObserver(vehicle)
+}
+
+// GetStreetDemand es la demanda generada, incluidas las llegadas que no se
+// presentaron, frente a TotalArrivals, que solo cuenta las que llegaron a la
+// entrada.
+func (s *Simulation) GetStreetDemand() int64 {
+    metrics := s.GetMetrics()
+    return metrics.TotalArrivals + metrics.TotalNoShows
+}
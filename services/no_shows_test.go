@@ -0,0 +1,80 @@
+package services
+
+import (
+    "math"
+    "testing"
+)
+
+func TestNoShowFilterProportion(t *testing.T) {
+    const samples = 100000
+    tests := []struct {
+        name        string
+        probability float64
+        seed        int64
+    }{
+        {"sin descartes", 0, 1},
+        {"uno de cada diez", 0.1, 1},
+        {"la mitad", 0.5, 2},
+        {"casi todos", 0.9, 3},
+        {"semilla por hora", 0.3, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            filter := newNoShowFilter(tt.probability, tt.seed)
+            skipped := 0
+            for i := 0; i < samples; i++ {
+                if filter.skip() {
+                    skipped++
+                }
+            }
+            got := float64(skipped) / samples
+            // Cuatro desviaciones estándar de la proporción binomial
+            tolerance := 4 * math.Sqrt(tt.probability*(1-tt.probability)/samples)
+            if math.Abs(got-tt.probability) > tolerance {
+                t.Errorf("proporción = %.4f, want %.4f ± %.4f", got, tt.probability, tolerance)
+            }
+        })
+    }
+}
+
+func TestNoShowFilterRepeatsAfterReset(t *testing.T) {
+    filter := newNoShowFilter(0.4, 7)
+    first := make([]bool, 1000)
+    for i := range first {
+        first[i] = filter.skip()
+    }
+    filter.reset()
+    for i, want := range first {
+        if got := filter.skip(); got != want {
+            t.Fatalf("llegada %d: skip = %v después de reset, want %v", i, got, want)
+        }
+    }
+}
+
+func TestShareArrivalsDropsTheSameArrivals(t *testing.T) {
+    tests := []struct {
+        name  string
+        seedA int64
+        seedB int64
+    }{
+        {"semillas por hora", 0, 0},
+        {"semillas distintas", 11, 12},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            configA, configB := DefaultConfig(), DefaultConfig()
+            configA.NoShowProbability, configA.NoShowSeed = 0.3, tt.seedA
+            configB.NoShowProbability, configB.NoShowSeed = 0.3, tt.seedB
+            simA := NewSimulationWithConfig(configA, func(int, string) {})
+            simB := NewSimulationWithConfig(configB, func(int, string) {})
+            if err := ShareArrivals(simA, simB); err != nil {
+                t.Fatalf("ShareArrivals: %v", err)
+            }
+            for i := 0; i < 1000; i++ {
+                if a, b := simA.noShows.skip(), simB.noShows.skip(); a != b {
+                    t.Fatalf("llegada %d: A descarta %v y B %v", i, a, b)
+                }
+            }
+        })
+    }
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+    "errors"
+    "sync"
+    "time"
+)
+
+const (
+    OCCUPANCY_CONTROL_INTERVAL = time.Second
+    MAX_LAMBDA_SAMPLES         = 3600
+)
+
+// OccupancyControl ajusta λ durante la corrida para sostener la ocupación en
+// Target (una fracción entre 0 y 1). Cada segundo simulado suma Gain veces el
+// error de ocupación a λ y lo limita a [MinRate, MaxRate]. Con Target en cero
+// no hay control y λ queda fijo en ArrivalRate.
+type OccupancyControl struct {
+    Target  float64
+    Gain    float64
+    MinRate float64
+    MaxRate float64
+}
+
+func (c OccupancyControl) IsSet() bool {
+    return c.Target > 0
+}
+
+func (c OccupancyControl) validate() error {
+    if c.Target < 0 || c.Target > 1 {
+        return errors.New("la ocupación objetivo debe estar entre 0 y 1")
+    }
+    if !c.IsSet() {
+        return nil
+    }
+    if c.Gain <= 0 {
+        return errors.New("la ganancia del control de ocupación debe ser positiva")
+    }
+    if c.MinRate <= 0 || c.MaxRate < c.MinRate {
+        return errors.New("el rango de λ del control de ocupación no es válido")
+    }
+    return nil
+}
+
+// LambdaSample es el λ que fijó el control en un momento de la corrida.
+type LambdaSample struct {
+    Elapsed   time.Duration
+    Occupancy float64
+    Lambda    float64
+}
+
+type lambdaTrajectory struct {
+    mu      sync.Mutex
+    samples []LambdaSample
+}
+
+func (t *lambdaTrajectory) add(sample LambdaSample) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    t.samples = append(t.samples, sample)
+    if len(t.samples) > MAX_LAMBDA_SAMPLES {
+        t.samples = t.samples[len(t.samples)-MAX_LAMBDA_SAMPLES:]
+    }
+}
+
+func (t *lambdaTrajectory) reset() {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    t.samples = nil
+}
+
+// runOccupancyControl corre el control proporcional hasta que se detienen
+// las llegadas. Los pasos van con el reloj de la simulación: en pausa no
+// avanza y con otra velocidad la ganancia por segundo simulado es la misma.
+func (s *Simulation) runOccupancyControl(control OccupancyControl) {
+    defer s.arrivalWg.Done()
+
+    for s.sleepPausable(s.arrivalCtx, OCCUPANCY_CONTROL_INTERVAL) {
+        s.stepOccupancyControl(control)
+    }
+}
+
+func (s *Simulation) stepOccupancyControl(control OccupancyControl) {
+    capacity := s.GetConfig().ParkingCapacity
+    if capacity <= 0 {
+        return
+    }
+    occupancy := float64(s.parking.GetOccupancy()) / float64(capacity)
+    lambda := s.poissonGen.GetLambda() + control.Gain*(control.Target-occupancy)
+    lambda = min(max(lambda, control.MinRate), control.MaxRate)
+    s.poissonGen.SetLambda(lambda)
+    s.lambdas.add(LambdaSample{Elapsed: s.GetElapsed(), Occupancy: occupancy, Lambda: lambda})
+}
+
+// GetArrivalRate es el λ con que se generan las llegadas ahora mismo: el de
+// la configuración o, con control de ocupación, el último que fijó el control.
+func (s *Simulation) GetArrivalRate() float64 {
+    return s.poissonGen.GetLambda()
+}
+
+// GetLambdaTrajectory devuelve los λ que fijó el control de ocupación, en
+// orden.
+func (s *Simulation) GetLambdaTrajectory() []LambdaSample {
+    s.lambdas.mu.Lock()
+    defer s.lambdas.mu.Unlock()
+    return append([]LambdaSample(nil), s.lambdas.samples...)
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+    "context"
+    "math"
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+// steppedLot hace de reloj falso: cada advance es un segundo simulado
+// en que entran λ vehículos y sale la fracción 1/stay de los estacionados.
+// Lleva el estado continuo en level y estaciona o saca vehículos para que la
+// ocupación del lote sea su redondeo.
+type steppedLot struct {
+    sim    *Simulation
+    stay   float64
+    level  float64
+    parked []*models.Vehicle
+    nextID int
+}
+
+func (l *steppedLot) advance(t *testing.T) {
+    capacity := float64(l.sim.GetConfig().ParkingCapacity)
+    l.level += l.sim.GetArrivalRate() - l.level/l.stay
+    l.level = min(max(l.level, 0), capacity)
+    want := int(math.Round(l.level))
+    for len(l.parked) < want {
+        l.nextID++
+        vehicle := models.NewVehicle(l.nextID)
+        if !l.sim.parking.TryEnter(vehicle) {
+            t.Fatalf("el vehículo %d no pudo estacionarse", vehicle.ID)
+        }
+        l.parked = append(l.parked, vehicle)
+    }
+    for len(l.parked) > want {
+        l.sim.parking.Exit(l.parked[0])
+        l.parked = l.parked[1:]
+    }
+}
+
+func TestOccupancyControlConverges(t *testing.T) {
+    tests := []struct {
+        name   string
+        target float64
+        gain   float64
+        start  float64
+    }{
+        {"objetivo bajo", 0.3, 0.5, 2},
+        {"objetivo alto", 0.7, 0.5, 0.2},
+        {"ganancia mayor", 0.5, 1, 0.2},
+    }
+    const (
+        capacity  = 20
+        steps     = 200
+        tolerance = 0.05
+    )
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.ParkingCapacity = capacity
+            config.ArrivalRate = tt.start
+            config.OccupancyControl = OccupancyControl{Target: tt.target, Gain: tt.gain, MinRate: 0.05, MaxRate: 5}
+            if err := config.Validate(); err != nil {
+                t.Fatal(err)
+            }
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            lot := &steppedLot{sim: sim, stay: 10}
+
+            sum := 0.0
+            for step := 0; step < steps; step++ {
+                sim.stepOccupancyControl(config.OccupancyControl)
+                if lambda := sim.GetArrivalRate(); lambda < 0.05 || lambda > 5 {
+                    t.Fatalf("paso %d: λ = %v fuera de [0.05, 5]", step, lambda)
+                }
+                lot.advance(t)
+                if step >= steps-40 {
+                    sum += float64(sim.GetOccupancy()) / capacity
+                }
+            }
+            if mean := sum / 40; math.Abs(mean-tt.target) > tolerance {
+                t.Errorf("ocupación media al final = %.3f, want %.2f ± %.2f", mean, tt.target, tolerance)
+            }
+            // En equilibrio entran tantos como salen: λ = objetivo·capacidad/estancia.
+            if lambda, want := sim.GetArrivalRate(), tt.target*capacity/lot.stay; math.Abs(lambda-want) > 0.25 {
+                t.Errorf("λ final = %.3f, want cerca de %.2f", lambda, want)
+            }
+            if trajectory := sim.GetLambdaTrajectory(); len(trajectory) != steps {
+                t.Errorf("trayectoria con %d muestras, want %d", len(trajectory), steps)
+            }
+        })
+    }
+}
+
+func TestOccupancyControlClampsLambda(t *testing.T) {
+    tests := []struct {
+        name     string
+        parked   int
+        lambda   float64
+        wantRate float64
+    }{
+        {"lote vacío empuja hasta el máximo", 0, 1.9, 2},
+        {"lote lleno baja hasta el mínimo", 10, 0.15, 0.1},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.ParkingCapacity = 10
+            config.ArrivalRate = tt.lambda
+            control := OccupancyControl{Target: 0.5, Gain: 1, MinRate: 0.1, MaxRate: 2}
+            config.OccupancyControl = control
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            for id := 1; id <= tt.parked; id++ {
+                sim.parking.TryEnter(models.NewVehicle(id))
+            }
+
+            sim.stepOccupancyControl(control)
+            if got := sim.GetArrivalRate(); got != tt.wantRate {
+                t.Errorf("λ = %v, want %v", got, tt.wantRate)
+            }
+            trajectory := sim.GetLambdaTrajectory()
+            if len(trajectory) != 1 || trajectory[0].Lambda != tt.wantRate || trajectory[0].Occupancy != float64(tt.parked)/10 {
+                t.Errorf("trayectoria = %+v", trajectory)
+            }
+        })
+    }
+}
+
+func TestOccupancyControlFollowsSimClock(t *testing.T) {
+    tests := []struct {
+        name     string
+        speed    float64
+        minSteps int
+        maxSteps int
+    }{
+        {"velocidad normal", 1, 0, 0},
+        {"velocidad 40x", 40, 4, 10},
+    }
+    const window = 250 * time.Millisecond
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.OccupancyControl = OccupancyControl{Target: 0.5, Gain: 0.5, MinRate: 0.1, MaxRate: 2}
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if err := sim.SetSpeed(tt.speed); err != nil {
+                t.Fatal(err)
+            }
+            ctx, cancel := context.WithCancel(context.Background())
+            sim.arrivalCtx = ctx
+            sim.arrivalWg.Add(1)
+            go sim.runOccupancyControl(config.OccupancyControl)
+            time.Sleep(window)
+            cancel()
+            sim.arrivalWg.Wait()
+
+            // Un paso por segundo simulado: en window caben window·speed segundos.
+            if steps := len(sim.GetLambdaTrajectory()); steps < tt.minSteps || steps > tt.maxSteps {
+                t.Errorf("%d pasos en %v a %vx, want entre %d y %d", steps, window, tt.speed, tt.minSteps, tt.maxSteps)
+            }
+        })
+    }
+}
+
+func TestOccupancyControlValidation(t *testing.T) {
+    tests := []struct {
+        name    string
+        control OccupancyControl
+        closed  int
+        daily   bool
+        wantErr bool
+    }{
+        {"sin control", OccupancyControl{}, 0, false, false},
+        {"válido", OccupancyControl{Target: 0.5, Gain: 1, MinRate: 0.1, MaxRate: 2}, 0, false, false},
+        {"objetivo mayor que uno", OccupancyControl{Target: 1.5, Gain: 1, MinRate: 0.1, MaxRate: 2}, 0, false, true},
+        {"ganancia cero", OccupancyControl{Target: 0.5, MinRate: 0.1, MaxRate: 2}, 0, false, true},
+        {"rango invertido", OccupancyControl{Target: 0.5, Gain: 1, MinRate: 2, MaxRate: 1}, 0, false, true},
+        {"con población cerrada", OccupancyControl{Target: 0.5, Gain: 1, MinRate: 0.1, MaxRate: 2}, 5, false, true},
+        {"con patrón diario", OccupancyControl{Target: 0.5, Gain: 1, MinRate: 0.1, MaxRate: 2}, 0, true, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.OccupancyControl = tt.control
+            config.ClosedPopulation = tt.closed
+            config.UseDailyPattern = tt.daily
+            if err := config.Validate(); (err != nil) != tt.wantErr {
+                t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+            }
+        })
+    }
+}
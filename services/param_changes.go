@@ -0,0 +1,105 @@
+package services
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+)
+
+// MAX_PARAM_CHANGES limita cuántos cambios de parámetros se conservan.
+const MAX_PARAM_CHANGES = 200
+
+// ParamChange es un ajuste hecho en vivo. Elapsed se mide desde que arrancó
+// la simulación; los cambios hechos antes de iniciar quedan en cero.
+type ParamChange struct {
+    Elapsed   time.Duration
+    Parameter string
+    Old       string
+    New       string
+}
+
+func (c ParamChange) String() string {
+    return c.Format(TimeMapping{})
+}
+
+// Format muestra el cambio con el tiempo según el mapeo de reloj.
+func (c ParamChange) Format(mapping TimeMapping) string {
+    return fmt.Sprintf("%7s  %s: %s → %s", mapping.Format(c.Elapsed), c.Parameter, c.Old, c.New)
+}
+
+type paramChangeLog struct {
+    mu       sync.Mutex
+    changes  []ParamChange
+    onChange func(change ParamChange)
+}
+
+// recordParamChange anota el cambio si el valor realmente cambió y avisa al
+// callback fuera del lock.
+func (s *Simulation) recordParamChange(parameter string, old, new any) {
+    oldText, newText := fmt.Sprint(old), fmt.Sprint(new)
+    if oldText == newText {
+        return
+    }
+
+    change := ParamChange{Elapsed: s.GetElapsed(), Parameter: parameter, Old: oldText, New: newText}
+    l := &s.paramChanges
+    l.mu.Lock()
+    l.changes = append(l.changes, change)
+    if len(l.changes) > MAX_PARAM_CHANGES {
+        l.changes = l.changes[len(l.changes)-MAX_PARAM_CHANGES:]
+    }
+    callback := l.onChange
+    l.mu.Unlock()
+
+    if callback != nil {
+        callback(change)
+    }
+}
+
+// reset borra los cambios anotados; el callback sigue registrado.
+func (l *paramChangeLog) reset() {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    l.changes = nil
+}
+
+func yesNo(value bool) string {
+    if value {
+        return "sí"
+    }
+    return "no"
+}
+
+func (s *Simulation) GetParamChanges() []ParamChange {
+    s.paramChanges.mu.Lock()
+    defer s.paramChanges.mu.Unlock()
+    return append([]ParamChange(nil), s.paramChanges.changes...)
+}
+
+func (s *Simulation) SetParamChangeCallback(callback func(change ParamChange)) {
+    s.paramChanges.mu.Lock()
+    defer s.paramChanges.mu.Unlock()
+    s.paramChanges.onChange = callback
+}
+
+// GetParamChangesScenario arma un archivo de escenario con la configuración
+// actual, que ya incluye los cambios, y los describe en orden.
+func (s *Simulation) GetParamChangesScenario(title string) ([]byte, error) {
+    current := s.GetConfig()
+    current.VehicleLogPath = ""
+    config, err := json.Marshal(current)
+    if err != nil {
+        return nil, err
+    }
+    lines := []string{"Cambios durante la corrida:"}
+    for _, change := range s.GetParamChanges() {
+        lines = append(lines, change.Format(current.TimeMapping))
+    }
+    return json.MarshalIndent(scenarioFile{
+        Title:       title,
+        Description: strings.Join(lines, "\n"),
+        Config:      config,
+    }, "", "  ")
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+    "encoding/json"
+    "strings"
+    "testing"
+)
+
+func TestEachParamChangeSourceLogsOneEntry(t *testing.T) {
+    type entry struct{ parameter, old, new string }
+    tests := []struct {
+        name   string
+        change func(sim *Simulation)
+        want   []entry
+    }{
+        {"capacidad de la cola", func(sim *Simulation) { sim.SetQueueCapacity(7) },
+            []entry{{"Capacidad cola", "10", "7"}}},
+        {"misma capacidad de la cola", func(sim *Simulation) { sim.SetQueueCapacity(10) }, nil},
+        {"velocidad", func(sim *Simulation) { sim.SetSpeed(4) },
+            []entry{{"Velocidad", "1", "4"}}},
+        {"misma velocidad", func(sim *Simulation) { sim.SetSpeed(1) }, nil},
+        {"cerrar la entrada", func(sim *Simulation) { sim.CloseEntrance(false) },
+            []entry{{"Entrada", "abierta", "cerrada"}}},
+        {"cerrar la entrada con cola afuera", func(sim *Simulation) { sim.CloseEntrance(true) },
+            []entry{{"Cola afuera", "no", "sí"}, {"Entrada", "abierta", "cerrada"}}},
+        {"cerrar dos veces", func(sim *Simulation) {
+            sim.CloseEntrance(false)
+            sim.CloseEntrance(false)
+        }, []entry{{"Entrada", "abierta", "cerrada"}}},
+        {"abrir la entrada abierta", func(sim *Simulation) { sim.OpenEntrance() }, nil},
+        {"cerrar y abrir", func(sim *Simulation) {
+            sim.CloseEntrance(false)
+            sim.OpenEntrance()
+        }, []entry{{"Entrada", "abierta", "cerrada"}, {"Entrada", "cerrada", "abierta"}}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.MaxQueueSize = 10
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            var notified int
+            sim.SetParamChangeCallback(func(ParamChange) { notified++ })
+
+            tt.change(sim)
+
+            changes := sim.GetParamChanges()
+            if len(changes) != len(tt.want) || notified != len(tt.want) {
+                t.Fatalf("cambios = %v (%d avisos), want %v", changes, notified, tt.want)
+            }
+            for i, w := range tt.want {
+                if got := changes[i]; got.Parameter != w.parameter || got.Old != w.old || got.New != w.new {
+                    t.Errorf("cambio %d = %s: %s → %s, want %s: %s → %s", i, got.Parameter, got.Old, got.New, w.parameter, w.old, w.new)
+                }
+            }
+        })
+    }
+}
+
+func TestParamChangesScenarioCarriesChanges(t *testing.T) {
+    config := DefaultConfig()
+    config.MaxQueueSize = 10
+    sim := NewSimulationWithConfig(config, func(int, string) {})
+    sim.SetQueueCapacity(4)
+    sim.CloseEntrance(false)
+
+    data, err := sim.GetParamChangesScenario("Exploración")
+    if err != nil {
+        t.Fatal(err)
+    }
+    var scenario scenarioFile
+    if err := json.Unmarshal(data, &scenario); err != nil {
+        t.Fatalf("el escenario no es JSON válido: %v", err)
+    }
+    var saved SimulationConfig
+    if err := json.Unmarshal(scenario.Config, &saved); err != nil {
+        t.Fatal(err)
+    }
+    if saved.MaxQueueSize != 4 {
+        t.Errorf("MaxQueueSize guardado = %d, want 4", saved.MaxQueueSize)
+    }
+    for _, want := range []string{"Capacidad cola: 10 → 4", "Entrada: abierta → cerrada"} {
+        if !strings.Contains(scenario.Description, want) {
+            t.Errorf("la descripción no menciona %q:\n%s", want, scenario.Description)
+        }
+    }
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+    "math/rand"
+    "sync"
+    "sync/atomic"
+    "time"
+    "holafyne/models"
+)
+
+const (
+    PATIENCE_NONE         = "none"
+    PATIENCE_FIXED        = "fixed"
+    PATIENCE_DISTRIBUTION = "distribution"
+
+    DISTRIBUTION_EXPONENTIAL = "exponential"
+    DISTRIBUTION_UNIFORM     = "uniform"
+
+    HAZARD_BUCKET_WIDTH = 5 * time.Second
+    HAZARD_BUCKETS      = 12
+)
+
+type PatienceConfig struct {
+    Mode         string
+    MaxWaitTime  float64
+    Distribution string
+    Mean         float64
+    Min          float64
+    Max          float64
+}
+
+type HazardBucket struct {
+    MinWait   time.Duration
+    MaxWait   time.Duration
+    Left      int64
+    Abandoned int64
+}
+
+func (b HazardBucket) Fraction() float64 {
+    if b.Left == 0 {
+        return 0
+    }
+    return float64(b.Abandoned) / float64(b.Left)
+}
+
+type patienceSampler struct {
+    config PatienceConfig
+    rng    *rand.Rand
+    mu     sync.Mutex
+}
+
+func newPatienceSampler(config PatienceConfig) *patienceSampler {
+    if config.Mode == "" {
+        config.Mode = PATIENCE_NONE
+    }
+    return &patienceSampler{
+        config: config,
+        rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+    }
+}
+
+// sample devuelve la paciencia de un vehículo; cero significa que espera
+// indefinidamente.
+func (ps *patienceSampler) sample() time.Duration {
+    ps.mu.Lock()
+    defer ps.mu.Unlock()
+
+    var seconds float64
+    switch ps.config.Mode {
+    case PATIENCE_FIXED:
+        seconds = ps.config.MaxWaitTime
+    case PATIENCE_DISTRIBUTION:
+        if ps.config.Distribution == DISTRIBUTION_UNIFORM {
+            seconds = ps.config.Min + ps.rng.Float64()*(ps.config.Max-ps.config.Min)
+        } else if ps.config.Mean > 0 {
+            seconds = ps.rng.ExpFloat64() * ps.config.Mean
+        }
+    }
+    return time.Duration(seconds * float64(time.Second))
+}
+
+type abandonmentHazard struct {
+    buckets [HAZARD_BUCKETS]HazardBucket
+    mu      sync.Mutex
+}
+
+func (h *abandonmentHazard) record(wait time.Duration, abandoned bool) {
+    index := int(wait / HAZARD_BUCKET_WIDTH)
+    if index >= HAZARD_BUCKETS {
+        index = HAZARD_BUCKETS - 1
+    }
+
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    h.buckets[index].Left++
+    if abandoned {
+        h.buckets[index].Abandoned++
+    }
+}
+
+func (h *abandonmentHazard) snapshot() []HazardBucket {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    buckets := make([]HazardBucket, HAZARD_BUCKETS)
+    for i := range buckets {
+        buckets[i] = h.buckets[i]
+        buckets[i].MinWait = time.Duration(i) * HAZARD_BUCKET_WIDTH
+        buckets[i].MaxWait = time.Duration(i+1) * HAZARD_BUCKET_WIDTH
+    }
+    return buckets
+}
+
+func (h *abandonmentHazard) reset() {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    h.buckets = [HAZARD_BUCKETS]HazardBucket{}
+}
+
+// GetAbandonmentHazard devuelve, por intervalo de espera, cuántos vehículos
+// dejaron la cola y cuántos de ellos la abandonaron. El último intervalo
+// acumula las esperas más largas.
+func (s *Simulation) GetAbandonmentHazard() []HazardBucket {
+    return s.hazard.snapshot()
+}
+
+func (s *Simulation) removeImpatientVehicles() {
+    s.queueMutex.Lock()
+    defer s.queueMutex.Unlock()
+
+    remaining := s.queue[:0]
+    abandoned := []*models.Vehicle{}
+    for _, vehicle := range s.queue {
+        if vehicle.Patience > 0 && vehicle.GetWaitDuration() >= vehicle.Patience {
+            abandoned = append(abandoned, vehicle)
+            continue
+        }
+        remaining = append(remaining, vehicle)
+    }
+    if len(abandoned) == 0 {
+        return
+    }
+
+    previousLen := len(s.queue)
+    s.queue = remaining
+    for _, vehicle := range abandoned {
+        previousLen--
+        atomic.AddInt64(&s.metrics.TotalAbandoned, 1)
+        s.hazard.record(vehicle.GetWaitDuration(), true)
+        s.recordWait(vehicle.GetWaitDuration())
+        s.recordOutcome(vehicle, OUTCOME_ABANDONED, 0)
+        s.notifyQueueChange(Abandoned, vehicle, previousLen+1)
+        s.notifyDeparture(vehicle)
+    }
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+    "math"
+    "math/rand"
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+func TestPatienceSamplerModes(t *testing.T) {
+    const samples = 20000
+    tests := []struct {
+        name     string
+        config   PatienceConfig
+        min, max time.Duration
+        mean     time.Duration
+    }{
+        {"sin modo espera siempre", PatienceConfig{}, 0, 0, 0},
+        {"none espera siempre", PatienceConfig{Mode: PATIENCE_NONE, MaxWaitTime: 5}, 0, 0, 0},
+        {"fija", PatienceConfig{Mode: PATIENCE_FIXED, MaxWaitTime: 5}, 5 * time.Second, 5 * time.Second, 5 * time.Second},
+        {"exponencial", PatienceConfig{Mode: PATIENCE_DISTRIBUTION, Distribution: DISTRIBUTION_EXPONENTIAL, Mean: 8},
+            0, time.Duration(math.MaxInt64), 8 * time.Second},
+        {"uniforme", PatienceConfig{Mode: PATIENCE_DISTRIBUTION, Distribution: DISTRIBUTION_UNIFORM, Min: 2, Max: 6},
+            2 * time.Second, 6 * time.Second, 4 * time.Second},
+        {"exponencial sin media", PatienceConfig{Mode: PATIENCE_DISTRIBUTION, Distribution: DISTRIBUTION_EXPONENTIAL}, 0, 0, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sampler := newPatienceSampler(tt.config)
+            sampler.rng = rand.New(rand.NewSource(1))
+            var total time.Duration
+            for i := 0; i < samples; i++ {
+                patience := sampler.sample()
+                if patience < tt.min || patience > tt.max {
+                    t.Fatalf("paciencia %v fuera de [%v, %v]", patience, tt.min, tt.max)
+                }
+                total += patience
+            }
+            mean := total / samples
+            if diff := (mean - tt.mean).Seconds(); math.Abs(diff) > 0.05*tt.mean.Seconds() {
+                t.Errorf("media = %v, want %v ± 5%%", mean, tt.mean)
+            }
+        })
+    }
+}
+
+func TestRemoveImpatientVehicles(t *testing.T) {
+    tests := []struct {
+        name          string
+        patience      []time.Duration
+        waited        time.Duration
+        wantAbandoned int64
+    }{
+        {"sin paciencia nadie abandona", []time.Duration{0, 0, 0}, time.Minute, 0},
+        {"todos se cansan", []time.Duration{time.Second, 2 * time.Second}, 3 * time.Second, 2},
+        {"solo los impacientes", []time.Duration{time.Second, 0, 10 * time.Second, 2 * time.Second}, 3 * time.Second, 2},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.MaxQueueSize = len(tt.patience)
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            for i, patience := range tt.patience {
+                vehicle := models.NewVehicle(i + 1)
+                vehicle.Patience = patience
+                vehicle.ArrivalTime = time.Now().Add(-tt.waited)
+                if !sim.addToQueue(vehicle) {
+                    t.Fatal("no se pudo encolar")
+                }
+            }
+
+            sim.removeImpatientVehicles()
+
+            if abandoned := sim.GetMetrics().TotalAbandoned; abandoned != tt.wantAbandoned {
+                t.Errorf("abandonos = %d, want %d", abandoned, tt.wantAbandoned)
+            }
+            if queue := sim.GetQueueLength(); queue != len(tt.patience)-int(tt.wantAbandoned) {
+                t.Errorf("cola = %d, want %d", queue, len(tt.patience)-int(tt.wantAbandoned))
+            }
+            var hazard int64
+            for _, bucket := range sim.GetAbandonmentHazard() {
+                hazard += bucket.Abandoned
+            }
+            if hazard != tt.wantAbandoned {
+                t.Errorf("abandonos en el riesgo = %d, want %d", hazard, tt.wantAbandoned)
+            }
+        })
+    }
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+    "context"
+    "math"
+    "sync"
+    "time"
+)
+
+// simClock avisa a los temporizadores de la simulación cuándo se pausa,
+// cuándo se reanuda y cuándo cambia la velocidad: pausing se cierra al
+// pausar, resumed al reanudar y speedChanged al cambiar la velocidad, y cada
+// uno se vuelve a crear cuando hace falta esperar el siguiente cambio.
+type simClock struct {
+    mu           sync.Mutex
+    paused       bool
+    pausing      chan struct{}
+    resumed      chan struct{}
+    pausedAt     time.Time
+    total        time.Duration
+    speed        float64
+    speedChanged chan struct{}
+}
+
+func newSimClock(speed float64) *simClock {
+    if speed <= 0 {
+        speed = 1
+    }
+    resumed := make(chan struct{})
+    close(resumed)
+    return &simClock{
+        pausing:      make(chan struct{}),
+        resumed:      resumed,
+        speed:        speed,
+        speedChanged: make(chan struct{}),
+    }
+}
+
+func (p *simClock) state() (paused bool, pausing, resumed chan struct{}) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    return p.paused, p.pausing, p.resumed
+}
+
+func (p *simClock) speedState() (speed float64, changed chan struct{}) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    return p.speed, p.speedChanged
+}
+
+// SetSpeed cambia la velocidad de la simulación, también en marcha. Con
+// factor 2 las llegadas y las estancias transcurren el doble de rápido; las
+// que ya estaban en curso siguen con lo que les faltaba a la nueva velocidad.
+func (s *Simulation) SetSpeed(factor float64) error {
+    if factor <= 0 || math.IsNaN(factor) || math.IsInf(factor, 0) {
+        return ErrInvalidSpeed
+    }
+    p := s.clock
+    p.mu.Lock()
+    previous := p.speed
+    p.speed = factor
+    close(p.speedChanged)
+    p.speedChanged = make(chan struct{})
+    p.mu.Unlock()
+
+    s.stateMutex.Lock()
+    s.config.SpeedFactor = factor
+    s.stateMutex.Unlock()
+    if previous != factor {
+        s.recordParamChange("Velocidad", previous, factor)
+    }
+    return nil
+}
+
+func (s *Simulation) GetSpeed() float64 {
+    speed, _ := s.clock.speedState()
+    return speed
+}
+
+// toRealTime convierte un tiempo simulado en el tiempo real que tarda a la
+// velocidad actual.
+func (s *Simulation) toRealTime(d time.Duration) time.Duration {
+    return time.Duration(float64(d) / s.GetSpeed())
+}
+
+// Pause congela la simulación: no se generan llegadas, la cola no avanza y
+// las estancias en curso dejan de correr. Un vehículo que ya cruzaba la pluma
+// termina de entrar y su estancia queda congelada desde ahí.
+func (s *Simulation) Pause() {
+    p := s.clock
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    if p.paused || !s.IsRunning() {
+        return
+    }
+    p.paused = true
+    p.pausedAt = time.Now()
+    p.resumed = make(chan struct{})
+    close(p.pausing)
+}
+
+// Resume reanuda la simulación. Cada estancia sigue con el tiempo que le
+// quedaba al pausar, así que nadie sale por el tiempo que duró la pausa. La
+// paciencia de los que esperan en la cola se alarga lo mismo que la pausa.
+func (s *Simulation) Resume() {
+    p := s.clock
+    p.mu.Lock()
+    if !p.paused {
+        p.mu.Unlock()
+        return
+    }
+    paused := time.Since(p.pausedAt)
+    p.paused = false
+    p.total += paused
+    p.pausing = make(chan struct{})
+    close(p.resumed)
+    p.mu.Unlock()
+
+    s.queueMutex.Lock()
+    defer s.queueMutex.Unlock()
+    for _, vehicle := range s.queue {
+        if vehicle.Patience > 0 {
+            vehicle.Patience += paused
+        }
+    }
+}
+
+func (s *Simulation) IsPaused() bool {
+    paused, _, _ := s.clock.state()
+    return paused
+}
+
+// GetPausedDuration es el tiempo total que la simulación estuvo en pausa,
+// incluida la pausa en curso.
+func (s *Simulation) GetPausedDuration() time.Duration {
+    p := s.clock
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    if p.paused {
+        return p.total + time.Since(p.pausedAt)
+    }
+    return p.total
+}
+
+// waitResumed espera a que se reanude la simulación si está en pausa. Devuelve
+// falso si ctx termina antes.
+func (s *Simulation) waitResumed(ctx context.Context) bool {
+    for {
+        paused, _, resumed := s.clock.state()
+        if !paused {
+            return true
+        }
+        select {
+        case <-ctx.Done():
+            return false
+        case <-resumed:
+        }
+    }
+}
+
+// sleepPausable espera d de tiempo simulado: no cuenta el tiempo en pausa y
+// sigue los cambios de velocidad. Devuelve falso si ctx termina antes.
+func (s *Simulation) sleepPausable(ctx context.Context, d time.Duration) bool {
+    remaining := d
+    for {
+        if !s.waitResumed(ctx) {
+            return false
+        }
+        _, pausing, _ := s.clock.state()
+        speed, speedChanged := s.clock.speedState()
+        started := time.Now()
+        timer := time.NewTimer(time.Duration(float64(remaining) / speed))
+        select {
+        case <-ctx.Done():
+            timer.Stop()
+            return false
+        case <-timer.C:
+            return true
+        case <-pausing:
+        case <-speedChanged:
+        }
+        timer.Stop()
+        remaining -= time.Duration(float64(time.Since(started)) * speed)
+        if remaining <= 0 {
+            return true
+        }
+    }
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+    "math"
+    "time"
+)
+
+// GetFreeSpacePrediction estima cuántos espacios habrá libres dentro de at:
+// a los libres de ahora se suman las salidas previstas antes de ese momento
+// y se restan la cola actual y las llegadas esperadas. El resultado queda
+// entre 0 y la capacidad. at es tiempo simulado, como λ; las salidas
+// previstas están en tiempo real, así que se comparan con at convertido a
+// la velocidad actual.
+func (s *Simulation) GetFreeSpacePrediction(at time.Duration) int {
+    config := s.GetConfig()
+    deadline := time.Now().Add(s.toRealTime(at))
+
+    exits := 0
+    for _, space := range s.GetSpaces() {
+        if space.OccupiedBy == nil {
+            continue
+        }
+        if exitAt := space.OccupiedBy.GetExpectedExitTime(); !exitAt.IsZero() && !exitAt.After(deadline) {
+            exits++
+        }
+    }
+
+    prediction := float64(s.GetAvailableSpaces()+exits-s.GetQueueLength()) - s.expectedArrivals(at)
+    prediction = math.Round(prediction)
+    return int(math.Max(0, math.Min(float64(config.ParkingCapacity), prediction)))
+}
+
+// expectedArrivals usa el generador de Poisson o, con población cerrada, la
+// tasa observada, que se mide en tiempo real. No cuenta más llegadas de las
+// que faltan por generar y descuenta las que no se presentan.
+func (s *Simulation) expectedArrivals(at time.Duration) float64 {
+    config := s.GetConfig()
+    if config.ClosedPopulation > 0 {
+        lambda, _ := s.observedRates()
+        return lambda * s.toRealTime(at).Seconds()
+    }
+    expected := s.poissonGen.ExpectedArrivalCount(at)
+    remaining := float64(config.MaxVehicles) - float64(s.GetStreetDemand())
+    return math.Max(0, math.Min(expected, remaining)) * (1 - config.NoShowProbability)
+}
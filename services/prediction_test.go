@@ -0,0 +1,121 @@
+package services
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+func TestGetFreeSpacePrediction(t *testing.T) {
+    s := time.Second
+    tests := []struct {
+        name string
+        // exits son las salidas previstas de los estacionados, en tiempo
+        // real desde ahora.
+        exits       []time.Duration
+        queued      int
+        lambda      float64
+        speed       float64
+        maxVehicles int
+        arrived     int64
+        at          time.Duration
+        want        int
+    }{
+        {"lote vacío", nil, 0, 0.1, 1, 1000, 0, 10 * s, 9},
+        // 4 libres + 2 salidas - 1 en cola - 3 llegadas
+        {"salidas, cola y llegadas", []time.Duration{10 * s, 20 * s, 100 * s, 100 * s, 200 * s, 300 * s}, 1, 0.1, 1, 1000, 0, 30 * s, 2},
+        {"demanda que satura", []time.Duration{100 * s}, 2, 1, 1, 1000, 0, 60 * s, 0},
+        {"ya llegaron todos", nil, 0, 1, 1, 5, 5, 60 * s, 10},
+        {"quedan pocos por llegar", nil, 0, 1, 1, 5, 2, 60 * s, 7},
+        // A velocidad 2, 20 s simulados son 10 s reales.
+        {"a velocidad doble", []time.Duration{8 * s, 15 * s}, 0, 0.05, 2, 1000, 0, 20 * s, 8},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.ParkingCapacity = 10
+            config.ArrivalRate = tt.lambda
+            config.MaxVehicles = tt.maxVehicles
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if err := sim.SetSpeed(tt.speed); err != nil {
+                t.Fatal(err)
+            }
+            sim.metrics.TotalArrivals = tt.arrived
+
+            now := time.Now()
+            id := 0
+            for _, exit := range tt.exits {
+                id++
+                vehicle := models.NewVehicle(id)
+                if !sim.parking.TryEnter(vehicle) {
+                    t.Fatalf("el vehículo %d no pudo estacionarse", id)
+                }
+                vehicle.SetExpectedExitTime(now.Add(exit))
+            }
+            for i := 0; i < tt.queued; i++ {
+                id++
+                if !sim.addToQueue(models.NewVehicle(id)) {
+                    t.Fatalf("no se pudo encolar el vehículo %d", id)
+                }
+            }
+
+            if got := sim.GetFreeSpacePrediction(tt.at); got != tt.want {
+                t.Errorf("GetFreeSpacePrediction(%v) = %d, want %d", tt.at, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestHandlePrediction(t *testing.T) {
+    config := DefaultConfig()
+    config.ParkingCapacity = 10
+    config.ArrivalRate = 0.1
+    sim := NewSimulationWithConfig(config, func(int, string) {})
+    previous := activeMetrics.Load()
+    defer activeMetrics.Store(previous)
+
+    tests := []struct {
+        name       string
+        method     string
+        query      string
+        registered bool
+        wantStatus int
+        wantFree   int
+    }{
+        {"predicción a 30 s", http.MethodGet, "?at=30s", true, http.StatusOK, 7},
+        {"sin parámetro", http.MethodGet, "", true, http.StatusBadRequest, 0},
+        {"duración negativa", http.MethodGet, "?at=-1m", true, http.StatusBadRequest, 0},
+        {"método no permitido", http.MethodPost, "?at=30s", true, http.StatusMethodNotAllowed, 0},
+        {"sin simulación", http.MethodGet, "?at=30s", false, http.StatusServiceUnavailable, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if tt.registered {
+                activeMetrics.Store(sim)
+            } else {
+                activeMetrics.Store(nil)
+            }
+            recorder := httptest.NewRecorder()
+            handlePrediction(recorder, httptest.NewRequest(tt.method, "/prediction"+tt.query, nil))
+            if recorder.Code != tt.wantStatus {
+                t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+            }
+            if tt.wantStatus != http.StatusOK {
+                return
+            }
+            var body struct {
+                AtSeconds  float64 `json:"at_seconds"`
+                FreeSpaces int     `json:"free_spaces"`
+            }
+            if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+                t.Fatalf("la respuesta no es JSON válido: %v", err)
+            }
+            if body.AtSeconds != 30 || body.FreeSpaces != tt.wantFree {
+                t.Errorf("respuesta = %+v, want at_seconds 30, free_spaces %d", body, tt.wantFree)
+            }
+        })
+    }
+}
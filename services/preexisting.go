@@ -0,0 +1,115 @@
+package services
+
+import (
+    "errors"
+    "math"
+    "math/rand"
+    "sync/atomic"
+    "time"
+    "holafyne/models"
+)
+
+const OUTCOME_PREEXISTING = "preexistente"
+
+// InitialOccupancy llena el estacionamiento al arrancar con vehículos que ya
+// estaban estacionados: Count vehículos o, si Count es cero, la fracción
+// Fraction de la capacidad. Lo que les queda de estancia sigue la
+// distribución indicada, exponencial con ResidualMean o uniforme entre
+// ResidualMin y ResidualMax; sin parámetros, uniforme entre cero y
+// MaxParkTime.
+type InitialOccupancy struct {
+    Count                int
+    Fraction             float64
+    ResidualDistribution string
+    ResidualMean         float64
+    ResidualMin          float64
+    ResidualMax          float64
+}
+
+func (o InitialOccupancy) validate() error {
+    if o.Count < 0 {
+        return errors.New("la ocupación inicial no puede ser negativa")
+    }
+    if o.Fraction < 0 || o.Fraction > 1 {
+        return errors.New("la fracción de ocupación inicial debe estar entre 0 y 1")
+    }
+    if o.Count > 0 && o.Fraction > 0 {
+        return errors.New("la ocupación inicial se indica como cantidad o como fracción, no ambas")
+    }
+    if o.ResidualDistribution == DISTRIBUTION_UNIFORM && (o.ResidualMin < 0 || o.ResidualMax < o.ResidualMin) {
+        return errors.New("el rango de la estancia restante no es válido")
+    }
+    if o.ResidualMean < 0 {
+        return errors.New("la estancia restante media no puede ser negativa")
+    }
+    return nil
+}
+
+func (o InitialOccupancy) count(capacity int) int {
+    n := o.Count
+    if n == 0 {
+        n = int(math.Round(o.Fraction * float64(capacity)))
+    }
+    return min(n, capacity)
+}
+
+func (o InitialOccupancy) sampleResidual(rng *rand.Rand, maxParkTime float64) time.Duration {
+    var seconds float64
+    switch {
+    case o.ResidualDistribution == DISTRIBUTION_UNIFORM:
+        seconds = o.ResidualMin + rng.Float64()*(o.ResidualMax-o.ResidualMin)
+    case o.ResidualMean > 0:
+        seconds = rng.ExpFloat64() * o.ResidualMean
+    default:
+        seconds = rng.Float64() * maxParkTime
+    }
+    return time.Duration(seconds * float64(time.Second))
+}
+
+// preloadVehicles estaciona a los vehículos preexistentes antes de que
+// empiecen las llegadas. Tienen ID negativo para no chocar con los que
+// genera la fuente de llegadas, y no pasan por la cola ni por la pluma.
+// Con SiteCapacity no se precargan más vehículos de los que caben en el
+// predio.
+func (s *Simulation) preloadVehicles() {
+    config := s.GetConfig()
+    n := config.InitialOccupancy.count(config.ParkingCapacity)
+    if config.SiteCapacity > 0 {
+        n = min(n, config.SiteCapacity-s.GetVehiclesOnSite())
+    }
+    if n <= 0 {
+        return
+    }
+
+    vehicles := make([]*models.Vehicle, n)
+    for i := range vehicles {
+        vehicles[i] = models.NewVehicle(-(i + 1))
+        vehicles[i].Preexisting = true
+    }
+    rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+    for _, vehicle := range s.parking.Preload(vehicles) {
+        atomic.AddInt64(&s.metrics.TotalPreexisting, 1)
+        s.enterSite()
+        residual := config.InitialOccupancy.sampleResidual(rng, config.MaxParkTime)
+        vehicle.SetExpectedExitTime(time.Now().Add(s.toRealTime(residual)))
+        s.planDeparture(vehicle.GetExpectedExitTime())
+        s.wg.Add(1)
+        go s.departPreexisting(vehicle, residual)
+    }
+}
+
+// departPreexisting saca al vehículo cuando termina su estancia restante. No
+// suma a las estadísticas de espera ni de estancia, que solo cuentan
+// recorridos completos.
+func (s *Simulation) departPreexisting(vehicle *models.Vehicle, residual time.Duration) {
+    defer s.wg.Done()
+    atomic.AddInt64(&s.metrics.ActiveWorkers, 1)
+    defer atomic.AddInt64(&s.metrics.ActiveWorkers, -1)
+    completed := s.sleepPausable(s.ctx, residual)
+    s.parking.Exit(vehicle)
+    s.leaveSite()
+    s.vehicleLog.record(vehicle, OUTCOME_PREEXISTING, vehicle.GetParkingDuration(), vehicle.GetTimeInState())
+    if completed {
+        s.wakeQueue()
+    }
+}
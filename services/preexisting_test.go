@@ -0,0 +1,133 @@
+package services
+
+import (
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestInitialOccupancyCount(t *testing.T) {
+    tests := []struct {
+        name      string
+        occupancy InitialOccupancy
+        capacity  int
+        want      int
+    }{
+        {"sin precarga", InitialOccupancy{}, 10, 0},
+        {"cantidad", InitialOccupancy{Count: 4}, 10, 4},
+        {"fracción", InitialOccupancy{Fraction: 0.25}, 10, 3},
+        {"lleno", InitialOccupancy{Fraction: 1}, 10, 10},
+        {"cantidad mayor que la capacidad", InitialOccupancy{Count: 20}, 10, 10},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := tt.occupancy.count(tt.capacity); got != tt.want {
+                t.Errorf("count(%d) = %d, want %d", tt.capacity, got, tt.want)
+            }
+        })
+    }
+}
+
+// preloadConfig tiene capacidad 10 y estancias largas para los que llegan.
+func preloadConfig(occupancy InitialOccupancy) SimulationConfig {
+    config := drainConfig(60, 60)
+    config.ParkingCapacity = 10
+    config.InitialOccupancy = occupancy
+    return config
+}
+
+func TestPreloadFillsLotAtStart(t *testing.T) {
+    long := InitialOccupancy{Count: 4, ResidualDistribution: DISTRIBUTION_UNIFORM, ResidualMin: 60, ResidualMax: 60}
+    tests := []struct {
+        name      string
+        occupancy InitialOccupancy
+        site      int
+        want      int
+    }{
+        {"cantidad", long, 0, 4},
+        {"fracción", InitialOccupancy{Fraction: 0.7, ResidualMean: 60}, 0, 7},
+        {"capacidad del sitio", long, 3, 3},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := preloadConfig(tt.occupancy)
+            config.SiteCapacity = tt.site
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if err := sim.SetArrivalSource(&burstArrivals{}); err != nil {
+                t.Fatal(err)
+            }
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            defer sim.Stop()
+
+            metrics := sim.GetMetrics()
+            if got := sim.GetOccupancy(); got != tt.want {
+                t.Errorf("ocupación al arrancar = %d, want %d", got, tt.want)
+            }
+            if metrics.TotalPreexisting != int64(tt.want) || metrics.TotalEntered != 0 {
+                t.Errorf("preexistentes %d y entrados %d, want %d y 0", metrics.TotalPreexisting, metrics.TotalEntered, tt.want)
+            }
+            if got := sim.GetVehiclesOnSite(); got != tt.want {
+                t.Errorf("vehículos en el sitio = %d, want %d", got, tt.want)
+            }
+            if errs := sim.ValidateParking(); len(errs) != 0 {
+                t.Errorf("ValidateParking: %v", errs)
+            }
+        })
+    }
+}
+
+func TestPreexistingDepartAfterResidual(t *testing.T) {
+    config := preloadConfig(InitialOccupancy{Count: 5, ResidualDistribution: DISTRIBUTION_UNIFORM, ResidualMin: 0.2, ResidualMax: 0.4})
+    sim := NewSimulationWithConfig(config, func(int, string) {})
+    if err := sim.SetArrivalSource(&burstArrivals{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := sim.Start(); err != nil {
+        t.Fatal(err)
+    }
+    defer sim.Stop()
+
+    time.Sleep(100 * time.Millisecond)
+    if got := sim.GetOccupancy(); got != 5 {
+        t.Fatalf("ocupación antes de las estancias restantes = %d, want 5", got)
+    }
+    deadline := time.Now().Add(2 * time.Second)
+    for sim.GetOccupancy() > 0 {
+        if time.Now().After(deadline) {
+            t.Fatalf("quedan %d preexistentes estacionados", sim.GetOccupancy())
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+    if got := sim.GetVehiclesOnSite(); got != 0 {
+        t.Errorf("vehículos en el sitio = %d, want 0", got)
+    }
+    if metrics := sim.GetMetrics(); metrics.TotalPreexisting != 5 || metrics.TotalExited != 0 {
+        t.Errorf("preexistentes %d y salidas %d, want 5 y 0: las salidas solo cuentan recorridos completos",
+            metrics.TotalPreexisting, metrics.TotalExited)
+    }
+}
+
+func TestQueueWaitsForPreexistingToLeave(t *testing.T) {
+    config := preloadConfig(InitialOccupancy{Fraction: 1, ResidualDistribution: DISTRIBUTION_UNIFORM, ResidualMin: 0.4, ResidualMax: 0.4})
+    sim := NewSimulationWithConfig(config, func(int, string) {})
+    if err := sim.SetArrivalSource(&burstArrivals{n: 2}); err != nil {
+        t.Fatal(err)
+    }
+    if err := sim.Start(); err != nil {
+        t.Fatal(err)
+    }
+    defer sim.Stop()
+
+    time.Sleep(200 * time.Millisecond)
+    if entered, queued := atomic.LoadInt64(&sim.metrics.TotalEntered), sim.GetQueueLength(); entered != 0 || queued != 2 {
+        t.Fatalf("con el lote precargado: entrados %d, en cola %d, want 0 y 2", entered, queued)
+    }
+    if !waitForCounter(&sim.metrics.TotalEntered, 2) {
+        t.Fatalf("no entraron los de la cola al liberarse espacios: %+v", sim.GetMetrics())
+    }
+    if elapsed := sim.GetElapsed(); elapsed < 400*time.Millisecond {
+        t.Errorf("entraron a los %v, antes de que saliera algún preexistente", elapsed)
+    }
+}
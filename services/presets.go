@@ -0,0 +1,58 @@
+package services
+
+import "fmt"
+
+// presetConfigs son configuraciones listas para usar desde la interfaz.
+// Cada una parte de DefaultConfig y solo cambia lo que la distingue.
+var presetConfigs = map[string]SimulationConfig{
+    "Normal": DefaultConfig(),
+    "Ocupado": presetFrom(func(c *SimulationConfig) {
+        c.ArrivalRate = 5.0
+        c.MaxQueueSize = 3
+    }),
+    "Tranquilo": presetFrom(func(c *SimulationConfig) {
+        c.ArrivalRate = 0.5
+        c.ParkingCapacity = 30
+        c.MaxQueueSize = 15
+    }),
+    "Estacionamiento Pequeño": presetFrom(func(c *SimulationConfig) {
+        c.ParkingCapacity = 5
+        c.MaxQueueSize = 2
+    }),
+}
+
+// presetScenarios son los presets que también son escenarios: se leen de su
+// archivo para no repetirlos.
+var presetScenarios = map[string]string{
+    "Hora Pico": "03_hora_pico.json",
+}
+
+// LoadPresets devuelve los presets por nombre. Los que vienen de un
+// escenario se leen en cada llamada; si alguno no se puede leer, devuelve
+// el error.
+func LoadPresets() (map[string]SimulationConfig, error) {
+    presets := make(map[string]SimulationConfig, len(presetConfigs)+len(presetScenarios))
+    for name, config := range presetConfigs {
+        presets[name] = config
+    }
+    for name, file := range presetScenarios {
+        scenario, err := loadScenario(file)
+        if err != nil {
+            return nil, fmt.Errorf("preset %s: %w", name, err)
+        }
+        presets[name] = scenario.Config
+    }
+    return presets, nil
+}
+
+// PresetNames devuelve los nombres de los presets en el orden en que se
+// muestran.
+func PresetNames() []string {
+    return []string{"Normal", "Ocupado", "Tranquilo", "Hora Pico", "Estacionamiento Pequeño"}
+}
+
+func presetFrom(modify func(c *SimulationConfig)) SimulationConfig {
+    config := DefaultConfig()
+    modify(&config)
+    return config
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+    "math"
+    "testing"
+    "time"
+)
+
+func TestPresetsValidate(t *testing.T) {
+    presets, err := LoadPresets()
+    if err != nil {
+        t.Fatal(err)
+    }
+    names := PresetNames()
+    if len(names) != len(presets) {
+        t.Errorf("PresetNames tiene %d nombres, LoadPresets devuelve %d", len(names), len(presets))
+    }
+    for _, name := range names {
+        t.Run(name, func(t *testing.T) {
+            config, ok := presets[name]
+            if !ok {
+                t.Fatalf("PresetNames incluye %q, que no está en los presets", name)
+            }
+            if err := config.Validate(); err != nil {
+                t.Errorf("Validate() = %v", err)
+            }
+        })
+    }
+}
+
+func TestHoraPicoPresetIsABurst(t *testing.T) {
+    presets, err := LoadPresets()
+    if err != nil {
+        t.Fatal(err)
+    }
+    preset := presets["Hora Pico"]
+    scenario, err := loadScenario("03_hora_pico.json")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if preset != scenario.Config {
+        t.Errorf("el preset no coincide con el escenario:\n%+v\n%+v", preset, scenario.Config)
+    }
+    if !preset.UseDailyPattern || preset.DailyAmplitude < 0.5 {
+        t.Fatalf("Hora Pico no usa un patrón con ráfaga: patrón %v, amplitud %v", preset.UseDailyPattern, preset.DailyAmplitude)
+    }
+
+    // El pico tiene que caer dentro de la corrida, que dura unas
+    // MaxVehicles/λ segundos.
+    day := time.Duration(preset.DayLength * float64(time.Second))
+    peak := time.Duration((math.Pi/2 + dailyPhase(preset, day)) / (2 * math.Pi) * float64(day))
+    if run := time.Duration(float64(preset.MaxVehicles) / preset.ArrivalRate * float64(time.Second)); peak <= 0 || peak >= run {
+        t.Errorf("el pico cae a los %v, fuera de la corrida de %v", peak, run)
+    }
+}
+
+func TestLoadPresetsReportsBrokenScenario(t *testing.T) {
+    previous := presetScenarios
+    defer func() { presetScenarios = previous }()
+    presetScenarios = map[string]string{"Roto": "no_existe.json"}
+
+    if presets, err := LoadPresets(); err == nil {
+        t.Errorf("LoadPresets() = %v, want un error por el escenario que falta", presets)
+    }
+}
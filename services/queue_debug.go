@@ -0,0 +1,75 @@
+package services
+
+import (
+    "log"
+    "sync/atomic"
+    "holafyne/models"
+)
+
+type QueueSnapshot struct {
+    Simulation []int
+    Lot        []int
+    InBoth     []int
+}
+
+// SetQueueDebug activa la comparación entre la cola de la simulación y la
+// cola interna del estacionamiento. Cada vehículo presente en ambas, o que
+// entra antes que otro que llegó primero, se registra como advertencia y
+// cuenta en QueueMismatches.
+func (s *Simulation) SetQueueDebug(enabled bool) {
+    s.queueDebug.Store(enabled)
+}
+
+func (s *Simulation) GetQueueSnapshot() QueueSnapshot {
+    s.queueMutex.RLock()
+    simQueue := make([]int, len(s.queue))
+    for i, vehicle := range s.queue {
+        simQueue[i] = vehicle.ID
+    }
+    s.queueMutex.RUnlock()
+
+    lotQueue := []int{}
+    inLot := map[int]bool{}
+    for _, vehicle := range s.parking.GetWaitingVehicles() {
+        lotQueue = append(lotQueue, vehicle.ID)
+        inLot[vehicle.ID] = true
+    }
+
+    inBoth := []int{}
+    for _, id := range simQueue {
+        if inLot[id] {
+            inBoth = append(inBoth, id)
+        }
+    }
+    return QueueSnapshot{Simulation: simQueue, Lot: lotQueue, InBoth: inBoth}
+}
+
+// checkQueued debe llamarse con queueMutex tomado, justo después de agregar
+// el vehículo a la cola de la simulación.
+func (s *Simulation) checkQueued(vehicle *models.Vehicle) {
+    if !s.queueDebug.Load() {
+        return
+    }
+    for _, waiting := range s.parking.GetWaitingVehicles() {
+        if waiting.ID == vehicle.ID {
+            atomic.AddInt64(&s.metrics.QueueMismatches, 1)
+            log.Printf("advertencia: %s está en la cola de la simulación y en la del estacionamiento", vehicle)
+            return
+        }
+    }
+}
+
+func (s *Simulation) checkAdmission(vehicle *models.Vehicle) {
+    if !s.queueDebug.Load() {
+        return
+    }
+    s.queueMutex.RLock()
+    defer s.queueMutex.RUnlock()
+    for _, waiting := range s.queue {
+        if waiting.ArrivalTime.Before(vehicle.ArrivalTime) {
+            atomic.AddInt64(&s.metrics.QueueMismatches, 1)
+            log.Printf("advertencia: %s entró antes que %s, que llegó primero", vehicle, waiting)
+            return
+        }
+    }
+}
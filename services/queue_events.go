@@ -0,0 +1,108 @@
+package services
+
+import (
+    "context"
+    "sync/atomic"
+    "time"
+    "holafyne/models"
+)
+
+type QueueChangeType int
+
+const (
+    Added QueueChangeType = iota
+    Removed
+    Abandoned
+    Cancelled
+    Rejected
+)
+
+var queueChangeStrings = map[QueueChangeType]string{
+    Added:     "entró a la cola",
+    Removed:   "salió de la cola",
+    Abandoned: "abandonó",
+    Cancelled: "cancelado",
+    Rejected:  "rechazado",
+}
+
+func (t QueueChangeType) String() string {
+    return queueChangeStrings[t]
+}
+
+// QueueChangeEvent describe un cambio de la cola. Dropped es cuántos eventos
+// se perdieron en este canal justo antes de este, porque el búfer estaba
+// lleno; con Dropped mayor que cero, el estado armado con los eventos
+// anteriores ya no es confiable y conviene releer la cola con
+// GetQueuedVehicles.
+type QueueChangeEvent struct {
+    PreviousLen int
+    CurrentLen  int
+    Changed     *models.Vehicle
+    ChangeType  QueueChangeType
+    Timestamp   time.Time
+    Position    int
+    Dropped     int
+}
+
+type queueWatcher struct {
+    ch      chan QueueChangeEvent
+    dropped int
+}
+
+// WatchQueue entrega cada cambio de la cola en un canal con búfer de
+// EventBufferSize, que se cierra cuando termina ctx. La cola no espera a un
+// observador lento: si su búfer está lleno, el evento se descarta, se cuenta
+// en DroppedEvents y el siguiente que sí se entrega lo indica en Dropped.
+func (s *Simulation) WatchQueue(ctx context.Context) <-chan QueueChangeEvent {
+    s.stateMutex.Lock()
+    bufferSize := s.config.EventBufferSize
+    s.stateMutex.Unlock()
+    watcher := &queueWatcher{ch: make(chan QueueChangeEvent, bufferSize)}
+
+    s.watchMutex.Lock()
+    s.queueWatchers = append(s.queueWatchers, watcher)
+    s.watchMutex.Unlock()
+
+    go func() {
+        <-ctx.Done()
+        s.watchMutex.Lock()
+        defer s.watchMutex.Unlock()
+        for i, w := range s.queueWatchers {
+            if w == watcher {
+                s.queueWatchers = append(s.queueWatchers[:i], s.queueWatchers[i+1:]...)
+                break
+            }
+        }
+        close(watcher.ch)
+    }()
+
+    return watcher.ch
+}
+
+// notifyQueueChange debe llamarse con queueMutex tomado para que los
+// observadores reciban los cambios en el mismo orden en que ocurrieron.
+func (s *Simulation) notifyQueueChange(changeType QueueChangeType, vehicle *models.Vehicle, previousLen int) {
+    event := QueueChangeEvent{
+        PreviousLen: previousLen,
+        CurrentLen:  len(s.queue),
+        Changed:     vehicle,
+        ChangeType:  changeType,
+        Timestamp:   time.Now(),
+        Position:    s.queuePosition(vehicle),
+    }
+
+    // queueMutex serializa las llamadas, así que dropped no necesita su
+    // propio lock.
+    s.watchMutex.RLock()
+    defer s.watchMutex.RUnlock()
+    for _, watcher := range s.queueWatchers {
+        event.Dropped = watcher.dropped
+        select {
+        case watcher.ch <- event:
+            watcher.dropped = 0
+        default:
+            watcher.dropped++
+            atomic.AddInt64(&s.metrics.DroppedEvents, 1)
+        }
+    }
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+    "context"
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+func TestWatchQueueEmitsEveryChangeType(t *testing.T) {
+    config := DefaultConfig()
+    config.ParkingCapacity = 1
+    sim := NewSimulationWithConfig(config, func(int, string) {})
+    defer sim.Stop()
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    events := sim.WatchQueue(ctx)
+
+    entering := models.NewVehicle(1)
+    impatient := models.NewVehicle(2)
+    impatient.Patience = time.Nanosecond
+
+    sim.addToQueue(entering)
+    sim.tryProcessNextInQueue()
+    sim.addToQueue(impatient)
+    time.Sleep(time.Millisecond)
+    sim.removeImpatientVehicles()
+
+    want := []struct {
+        changeType  QueueChangeType
+        vehicleID   int
+        previousLen int
+        currentLen  int
+    }{
+        {Added, 1, 0, 1},
+        {Removed, 1, 1, 0},
+        {Added, 2, 0, 1},
+        {Abandoned, 2, 1, 0},
+    }
+    for i, w := range want {
+        select {
+        case event := <-events:
+            if event.ChangeType != w.changeType || event.Changed.ID != w.vehicleID {
+                t.Errorf("evento %d = tipo %d, vehículo %d; want tipo %d, vehículo %d",
+                    i, event.ChangeType, event.Changed.ID, w.changeType, w.vehicleID)
+            }
+            if event.PreviousLen != w.previousLen || event.CurrentLen != w.currentLen {
+                t.Errorf("evento %d: largo %d→%d, want %d→%d",
+                    i, event.PreviousLen, event.CurrentLen, w.previousLen, w.currentLen)
+            }
+            if event.Dropped != 0 {
+                t.Errorf("evento %d: Dropped = %d, want 0", i, event.Dropped)
+            }
+        case <-time.After(time.Second):
+            t.Fatalf("no llegó el evento %d", i)
+        }
+    }
+}
+
+func TestWatchQueueClosesWithContext(t *testing.T) {
+    sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+    ctx, cancel := context.WithCancel(context.Background())
+    events := sim.WatchQueue(ctx)
+    cancel()
+
+    select {
+    case _, ok := <-events:
+        if ok {
+            t.Fatal("llegó un evento sin cambios en la cola")
+        }
+    case <-time.After(time.Second):
+        t.Fatal("el canal no se cerró al cancelar el contexto")
+    }
+}
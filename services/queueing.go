@@ -0,0 +1,40 @@
+package services
+
+import "holafyne/utils"
+
+// QueueingParams describe el estacionamiento como una cola M/M/c/K: c
+// espacios y K vehículos como máximo entre espacios y cola.
+type QueueingParams struct {
+    Lambda                float64
+    Mu                    float64
+    C                     float64
+    K                     float64
+    Rho                   float64
+    TheoreticalRejectProb float64
+    ObservedRejectProb    float64
+}
+
+// GetQueueingTheoryParams calcula los parámetros M/M/c/K con la tasa de
+// llegadas y la estancia observadas, y compara la probabilidad de rechazo
+// teórica con la observada.
+func (s *Simulation) GetQueueingTheoryParams() QueueingParams {
+    config := s.GetConfig()
+    lambda, avgPark := s.observedRates()
+
+    params := QueueingParams{
+        Lambda: lambda,
+        C:      float64(config.ParkingCapacity),
+        K:      float64(config.ParkingCapacity + config.MaxQueueSize),
+    }
+    if avgPark > 0 {
+        params.Mu = 1 / avgPark
+    }
+    if params.C > 0 && params.Mu > 0 {
+        params.Rho = lambda / (params.C * params.Mu)
+    }
+    params.TheoreticalRejectProb = utils.MMCKBlockingProbability(lambda, params.Mu, config.ParkingCapacity, config.ParkingCapacity+config.MaxQueueSize)
+    if samples := s.samples.rejection.Samples(); len(samples) > 0 {
+        params.ObservedRejectProb = utils.Mean(samples)
+    }
+    return params
+}
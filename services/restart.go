@@ -0,0 +1,44 @@
+package services
+
+import "sync/atomic"
+
+// reset deja la simulación como recién creada para arrancarla otra vez
+// después de Stop: contextos nuevos, entrada abierta, cola vacía, la fuente
+// de llegadas desde el principio con el λ de la configuración, y en cero las
+// estadísticas, la trayectoria de λ, los cambios de parámetros, los cruces y
+// el tiempo fuera de servicio de la pluma y los conflictos. Las fuentes
+// externas que no saben reiniciarse siguen desde donde quedaron.
+func (s *Simulation) reset() {
+    s.stateMutex.Lock()
+    s.initContexts(s.parent)
+    s.queueDone = make(chan struct{})
+    arrivals := s.arrivals
+    s.stateMutex.Unlock()
+
+    s.queueFrozen.Store(false)
+    s.parking.OpenEntry()
+
+    s.queueMutex.Lock()
+    for len(s.queue) > 0 {
+        previousLen := len(s.queue)
+        vehicle := s.queue[previousLen-1]
+        s.queue = s.queue[:previousLen-1]
+        s.notifyQueueChange(Removed, vehicle, previousLen)
+    }
+    if s.onQueueUpdate != nil {
+        s.onQueueUpdate(0)
+    }
+    s.queueMutex.Unlock()
+
+    atomic.StoreInt64(&s.onSite, 0)
+    if source, ok := arrivals.(resettableSource); ok {
+        source.reset()
+    }
+    s.noShows.reset()
+    s.poissonGen.SetLambda(s.GetConfig().ArrivalRate)
+    s.lambdas.reset()
+    s.paramChanges.reset()
+    s.parking.ResetRunHistory()
+    s.resetStatistics()
+    s.notifyEntrance("Simulación reiniciada")
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+    "embed"
+    "encoding/json"
+    "fmt"
+    "path"
+    "sort"
+)
+
+//go:embed scenarios/*.json
+var scenarioFiles embed.FS
+
+type Scenario struct {
+    Name        string
+    Title       string
+    Description string
+    Config      SimulationConfig
+}
+
+type scenarioFile struct {
+    Title       string          `json:"title"`
+    Description string          `json:"description"`
+    Config      json.RawMessage `json:"config"`
+}
+
+// LoadScenarios lee los escenarios incluidos en el binario. Cada archivo solo
+// declara los campos que cambian; el resto toma los valores de DefaultConfig.
+func LoadScenarios() ([]Scenario, error) {
+    entries, err := scenarioFiles.ReadDir("scenarios")
+    if err != nil {
+        return nil, err
+    }
+    sort.Slice(entries, func(i, j int) bool {
+        return entries[i].Name() < entries[j].Name()
+    })
+
+    scenarios := make([]Scenario, 0, len(entries))
+    for _, entry := range entries {
+        scenario, err := loadScenario(entry.Name())
+        if err != nil {
+            return nil, err
+        }
+        scenarios = append(scenarios, scenario)
+    }
+    return scenarios, nil
+}
+
+func loadScenario(name string) (Scenario, error) {
+    data, err := scenarioFiles.ReadFile(path.Join("scenarios", name))
+    if err != nil {
+        return Scenario{}, err
+    }
+
+    var file scenarioFile
+    if err := json.Unmarshal(data, &file); err != nil {
+        return Scenario{}, fmt.Errorf("escenario %s: %w", name, err)
+    }
+
+    config := DefaultConfig()
+    if err := json.Unmarshal(file.Config, &config); err != nil {
+        return Scenario{}, fmt.Errorf("escenario %s: %w", name, err)
+    }
+    if err := config.Validate(); err != nil {
+        return Scenario{}, fmt.Errorf("escenario %s: %w", name, err)
+    }
+
+    return Scenario{
+        Name:        name,
+        Title:       file.Title,
+        Description: file.Description,
+        Config:      config,
+    }, nil
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+    "testing"
+    "time"
+)
+
+func TestBundledScenariosRun(t *testing.T) {
+    const (
+        speed   = 50
+        simTime = 20 * time.Second
+    )
+    scenarios, err := LoadScenarios()
+    if err != nil {
+        t.Fatalf("LoadScenarios: %v", err)
+    }
+    if len(scenarios) == 0 {
+        t.Fatal("no hay escenarios incluidos")
+    }
+    for _, scenario := range scenarios {
+        t.Run(scenario.Name, func(t *testing.T) {
+            if scenario.Title == "" || scenario.Description == "" {
+                t.Errorf("título %q o descripción %q vacíos", scenario.Title, scenario.Description)
+            }
+            config := scenario.Config
+            config.SpeedFactor = speed
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            time.Sleep(simTime / speed)
+            sim.Stop()
+
+            if arrivals := sim.GetMetrics().TotalArrivals; arrivals == 0 {
+                t.Errorf("sin llegadas en %v simulados", simTime)
+            }
+            for _, err := range sim.ValidateParking() {
+                t.Errorf("estado inconsistente: %v", err)
+            }
+        })
+    }
+}
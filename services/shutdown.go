@@ -0,0 +1,122 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "time"
+)
+
+const DRAIN_POLL_INTERVAL = 100 * time.Millisecond
+
+var ErrSimulationNotRunning = errors.New("la simulación no está en ejecución")
+
+type SimulationPhase int
+
+const (
+    PhaseIdle SimulationPhase = iota
+    PhaseRunning
+    PhaseStoppingArrivals
+    PhaseStoppingQueue
+    PhaseStoppingDepartures
+    PhaseStopped
+)
+
+var phaseStrings = map[SimulationPhase]string{
+    PhaseIdle:               "sin iniciar",
+    PhaseRunning:            "en ejecución",
+    PhaseStoppingArrivals:   "deteniendo llegadas",
+    PhaseStoppingQueue:      "congelando la cola",
+    PhaseStoppingDepartures: "esperando salidas",
+    PhaseStopped:            "detenida",
+}
+
+func (p SimulationPhase) String() string {
+    return phaseStrings[p]
+}
+
+func (s *Simulation) GetPhase() SimulationPhase {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+    return s.phase
+}
+
+func (s *Simulation) setPhase(phase SimulationPhase) {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+    s.phase = phase
+}
+
+// Stop detiene la simulación en orden, esperando cada paso antes del
+// siguiente para que el estado final no dependa de carreras:
+//  1. se detienen las llegadas y los reintentos, y se repara la pluma si
+//     quedó fuera de servicio, para que no queden vehículos esperándola;
+//  2. se congela la cola y se cierra la entrada, de modo que nadie más entra;
+//  3. se cancelan las estancias de los vehículos estacionados.
+func (s *Simulation) Stop() {
+    if !s.IsRunning() {
+        s.cancel()
+        s.wg.Wait()
+        return
+    }
+
+    s.setPhase(PhaseStoppingArrivals)
+    s.haltArrivals()
+    s.arrivalWg.Wait()
+    s.RepairGate()
+
+    s.setPhase(PhaseStoppingQueue)
+    s.queueFrozen.Store(true)
+    s.stopQueue()
+    <-s.queueDone
+    s.parking.CloseEntry()
+
+    s.setPhase(PhaseStoppingDepartures)
+    s.cancel()
+    s.wg.Wait()
+    s.stopVehicleLog()
+
+    s.stateMutex.Lock()
+    s.running = false
+    s.phase = PhaseStopped
+    s.stateMutex.Unlock()
+    s.Resume()
+}
+
+// StopArrivals corta solo la generación de llegadas y los reintentos; la cola
+// y los vehículos estacionados siguen su curso.
+func (s *Simulation) StopArrivals() {
+    s.haltArrivals()
+    s.arrivalWg.Wait()
+}
+
+// WaitForDrain espera a que el estacionamiento y la cola queden vacíos. Deja
+// de esperar si ctx termina o si la simulación se detiene.
+func (s *Simulation) WaitForDrain(ctx context.Context) error {
+    ticker := time.NewTicker(DRAIN_POLL_INTERVAL)
+    defer ticker.Stop()
+    for {
+        if s.GetOccupancy() == 0 && s.GetQueueLength() == 0 {
+            return nil
+        }
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case <-s.ctx.Done():
+            return s.ctx.Err()
+        case <-ticker.C:
+        }
+    }
+}
+
+// RunUntilEmpty deja de generar llegadas, espera a que salgan todos los
+// vehículos y luego detiene la simulación. Si ctx termina antes de vaciarse,
+// la detiene igual y devuelve el error del contexto.
+func (s *Simulation) RunUntilEmpty(ctx context.Context) error {
+    if !s.IsRunning() {
+        return ErrSimulationNotRunning
+    }
+    s.StopArrivals()
+    err := s.WaitForDrain(ctx)
+    s.Stop()
+    return err
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+)
+
+func drainConfig(minPark, maxPark float64) SimulationConfig {
+    config := DefaultConfig()
+    config.ParkingCapacity = 3
+    config.MaxQueueSize = 5
+    config.ArrivalRate = 20
+    config.MaxVehicles = 15
+    config.MinParkTime = minPark
+    config.MaxParkTime = maxPark
+    return config
+}
+
+func TestRunUntilEmpty(t *testing.T) {
+    tests := []struct {
+        name    string
+        config  SimulationConfig
+        timeout time.Duration
+        wantErr error
+    }{
+        {"se vacía", drainConfig(0.1, 0.3), 20 * time.Second, nil},
+        {"se acaba el plazo", drainConfig(30, 60), 300 * time.Millisecond, context.DeadlineExceeded},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sim := NewSimulationWithConfig(tt.config, func(int, string) {})
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            time.Sleep(300 * time.Millisecond)
+
+            ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+            defer cancel()
+            err := sim.RunUntilEmpty(ctx)
+            if !errors.Is(err, tt.wantErr) {
+                t.Fatalf("RunUntilEmpty() = %v, want %v", err, tt.wantErr)
+            }
+            if sim.IsRunning() {
+                t.Error("la simulación sigue corriendo")
+            }
+            if tt.wantErr == nil {
+                if occupancy := sim.GetOccupancy(); occupancy != 0 {
+                    t.Errorf("ocupación = %d, want 0", occupancy)
+                }
+                if queue := sim.GetQueueLength(); queue != 0 {
+                    t.Errorf("cola = %d, want 0", queue)
+                }
+            }
+        })
+    }
+}
+
+func TestRunUntilEmptyNotRunning(t *testing.T) {
+    sim := NewSimulationWithConfig(drainConfig(0.1, 0.3), func(int, string) {})
+    if err := sim.RunUntilEmpty(context.Background()); !errors.Is(err, ErrSimulationNotRunning) {
+        t.Fatalf("RunUntilEmpty() = %v, want ErrSimulationNotRunning", err)
+    }
+}
+
+func TestParentContextCancellationStops(t *testing.T) {
+    tests := []struct {
+        name     string
+        minPark  float64
+        maxPark  float64
+        cancelAt time.Duration
+    }{
+        {"al arrancar", 30, 60, 0},
+        {"con vehículos estacionados", 30, 60, 300 * time.Millisecond},
+        {"con estancias cortas", 0.05, 0.1, 300 * time.Millisecond},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sim := NewSimulationWithConfig(drainConfig(tt.minPark, tt.maxPark), func(int, string) {})
+            ctx, cancel := context.WithCancel(context.Background())
+            defer cancel()
+            if err := sim.SetContext(ctx); err != nil {
+                t.Fatal(err)
+            }
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            if err := sim.SetContext(context.Background()); !errors.Is(err, ErrSimulationRunning) {
+                t.Errorf("SetContext en ejecución = %v, want ErrSimulationRunning", err)
+            }
+            time.Sleep(tt.cancelAt)
+
+            cancel()
+            deadline := time.Now().Add(500 * time.Millisecond)
+            for sim.IsRunning() {
+                if time.Now().After(deadline) {
+                    t.Fatalf("la simulación sigue corriendo 500 ms después de cancelar; fase %v", sim.GetPhase())
+                }
+                time.Sleep(5 * time.Millisecond)
+            }
+            if phase := sim.GetPhase(); phase != PhaseStopped {
+                t.Errorf("fase = %v, want %v", phase, PhaseStopped)
+            }
+        })
+    }
+}
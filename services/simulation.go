@@ -1,200 +1,851 @@
-package services
-
-import (
-    "math/rand"
-    "sync"
-    "time"
-    "context"
-    "holafyne/models"
-    "holafyne/utils"
-)
-
-const (
-    PARKING_CAPACITY = 20 
-    MAX_VEHICLES     = 100 
-    MIN_PARK_TIME    = 10  
-    MAX_PARK_TIME    = 20  
-    MAX_QUEUE_SIZE   = 10  
-)
-
-
-type SimulationConfig struct {
-    ParkingCapacity int
-    MaxVehicles     int
-    MinParkTime     float64
-    MaxParkTime     float64
-    ArrivalRate     float64
-}
-
-type Simulation struct {
-    config       SimulationConfig        
-    parking      *models.ParkingLot      
-    ctx          context.Context        
-    cancel       context.CancelFunc    
-    wg           sync.WaitGroup         
-    poissonGen   *utils.PoissonGenerator 
-    queue        []*models.Vehicle       
-    queueMutex   sync.RWMutex            
-    onQueueUpdate func(queueSize int)    
-}
-
-func (s *Simulation) SetQueueUpdateCallback(callback func(queueSize int)) {
-    s.onQueueUpdate = callback
-}
-
-
-func DefaultConfig() SimulationConfig {
-    return SimulationConfig{
-        ParkingCapacity: PARKING_CAPACITY,
-        MaxVehicles:     MAX_VEHICLES,
-        MinParkTime:     MIN_PARK_TIME,
-        MaxParkTime:     MAX_PARK_TIME,
-        ArrivalRate:     2.0,
-    }
-}
-
-func NewSimulation(updateUI func(spaces int, message string)) *Simulation {
-    return NewSimulationWithConfig(DefaultConfig(), updateUI)
-}
-
-func NewSimulationWithConfig(config SimulationConfig, updateUI func(spaces int, message string)) *Simulation {
-    ctx, cancel := context.WithCancel(context.Background())
-    poissonConfig := utils.DefaultPoissonConfig()
-    poissonConfig.Lambda = config.ArrivalRate 
-    return &Simulation{
-        config:     config,
-        parking:    models.NewParkingLot(config.ParkingCapacity, updateUI),
-        ctx:        ctx,
-        cancel:     cancel,
-        poissonGen: utils.NewPoissonGenerator(poissonConfig),
-        queue:      make([]*models.Vehicle, 0, MAX_QUEUE_SIZE),
-    }
-}
-
-func (s *Simulation) Start() {
-    s.wg.Add(1)
-    go s.runSimulation() 
-    go s.processQueue()  
-}
-
-func (s *Simulation) Stop() {
-    s.cancel()
-    s.wg.Wait() 
-}
-
-func (s *Simulation) processQueue() {
-    ticker := time.NewTicker(100 * time.Millisecond) 
-    defer ticker.Stop()
-
-    for {
-        select {
-        case <-s.ctx.Done(): 
-            return
-        case <-ticker.C:
-            s.tryProcessNextInQueue() 
-        }
-    }
-}
-
-func (s *Simulation) tryProcessNextInQueue() {
-    s.queueMutex.Lock()
-    if len(s.queue) > 0 && s.parking.GetAvailableSpaces() > 0 {
-        vehicle := s.queue[0] 
-        s.queue = s.queue[1:] 
-        s.queueMutex.Unlock()
-
-        s.wg.Add(1)
-        go s.processVehicle(vehicle) 
-    } else {
-        s.queueMutex.Unlock()
-    }
-}
-
-func (s *Simulation) runSimulation() {
-    defer s.wg.Done()
-
-    vehicleCount := 0
-    for vehicleCount < s.config.MaxVehicles {
-        select {
-        case <-s.ctx.Done():
-            return
-        default:
-            vehicleCount++
-            vehicle := models.NewVehicle(vehicleCount) 
-
-            if s.parking.GetAvailableSpaces() > 0 {
-                s.wg.Add(1)
-                go s.processVehicle(vehicle) 
-            } else {
-                s.addToQueue(vehicle) 
-            }
-
-            interval := s.poissonGen.NextInterval() 
-            select {
-            case <-s.ctx.Done(): 
-                return
-            case <-time.After(interval):
-                continue
-            }
-        }
-    }
-}
-
-func (s *Simulation) addToQueue(vehicle *models.Vehicle) bool {
-    s.queueMutex.Lock()
-    defer s.queueMutex.Unlock()
-
-    if len(s.queue) >= MAX_QUEUE_SIZE { 
-        return false
-    }
-
-    s.queue = append(s.queue, vehicle)
-    queueLength := len(s.queue)
-
-
-    if s.onQueueUpdate != nil {
-        s.onQueueUpdate(queueLength)
-    }
-
-    return true
-}
-
-func (s *Simulation) processVehicle(vehicle *models.Vehicle) {
-    defer s.wg.Done()
-
-    entered := s.parking.TryEnter(vehicle) 
-
-    if !entered {
-        if !s.addToQueue(vehicle) { 
-            return
-        }
-        return
-    }
-
-    parkTime := s.generateParkingTime()
-    timer := time.NewTimer(parkTime)
-
-    select {
-    case <-s.ctx.Done(): 
-        timer.Stop()
-        s.parking.Exit(vehicle) 
-        return
-    case <-timer.C:
-        s.parking.Exit(vehicle) 
-   
-    }
-}
-
-func (s *Simulation) GetQueueLength() int {
-    s.queueMutex.RLock()
-    defer s.queueMutex.RUnlock()
-    return len(s.queue)
-}
-
-func (s *Simulation) generateParkingTime() time.Duration {
-    parkTime := s.config.MinParkTime + rand.Float64()*(s.config.MaxParkTime-s.config.MinParkTime)
-    return time.Duration(parkTime * float64(time.Second))
-}
-
-
-
+package services
+
+import (
+    "errors"
+    "fmt"
+    "math/rand"
+    "sync"
+    "sync/atomic"
+    "time"
+    "context"
+    "holafyne/models"
+    "holafyne/utils"
+    "golang.org/x/sync/semaphore"
+)
+
+const (
+    PARKING_CAPACITY  = 20 
+    MAX_VEHICLES      = 100 
+    MIN_PARK_TIME     = 10  
+    MAX_PARK_TIME     = 20  
+    MAX_QUEUE_SIZE    = 10  
+    EVENT_BUFFER_SIZE = 1000
+)
+
+const (
+    DEFAULT_RETRY_BACKOFF = 500 * time.Millisecond
+    DEFAULT_MAX_RETRIES   = 3
+)
+
+var ErrSimulationRunning = errors.New("la simulación ya está en ejecución")
+
+var ErrInvalidSpeed = errors.New("la velocidad debe ser mayor que cero")
+
+
+type SimulationConfig struct {
+    ParkingCapacity  int
+    MaxVehicles      int
+    MinParkTime      float64
+    MaxParkTime      float64
+    ArrivalRate      float64
+    EventBufferSize  int
+    ClosedPopulation int
+    AwayRate         float64
+    Patience         PatienceConfig
+    GatePolicy       models.GatePolicy
+    RetryOnFullQueue bool
+    RetryBackoff     time.Duration
+    MaxRetries       int
+    MaxQueueSize     int
+    MinStay          float64
+    MinStayPolicy    string
+    DepartureJitter  float64
+    ClusterSize      int
+    ClusterWindow    float64
+    VehicleLogPath   string
+    GateFailure      GateFailureConfig
+    UseDailyPattern  bool
+    DayLength        float64
+    DailyAmplitude   float64
+    MaxSimultaneousEntering int
+    TimeMapping      TimeMapping
+    DailyPeak        string
+    SiteCapacity     int
+    OccupancyControl OccupancyControl
+    SpeedFactor      float64
+    InitialOccupancy InitialOccupancy
+    NoShowProbability float64
+    NoShowSeed       int64
+}
+
+type Simulation struct {
+    config       SimulationConfig        
+    parking      *models.ParkingLot      
+    parent       context.Context
+    ctx          context.Context        
+    cancel       context.CancelFunc    
+    wg           sync.WaitGroup         
+    poissonGen   *utils.PoissonGenerator 
+    queue        []*models.Vehicle       
+    queueMutex   sync.RWMutex            
+    onQueueUpdate func(queueSize int)    
+    metrics      SimulationMetrics
+    queueWatchers []*queueWatcher
+    watchMutex   sync.RWMutex
+    updateUI     func(spaces int, message string)
+    statsMutex   sync.RWMutex
+    statsSince   time.Time
+    startedAt    time.Time
+    stateMutex   sync.Mutex
+    running      bool
+    arrivals     ArrivalSource
+    samples      simulationSamples
+    patience     *patienceSampler
+    hazard       abandonmentHazard
+    worstWait    int64
+    onDoublePark func(spaceID int, blocked bool)
+    queueDebug   atomic.Bool
+    busy         busyTracker
+    freeSpaces   *freeSpaceTracker
+    vehicleLog   *vehicleLog
+    stateTimes   *stateTimeStats
+    queueWake    chan struct{}
+    stopMonitor  context.CancelFunc
+    entryClosed  atomic.Bool
+    queueOutside atomic.Bool
+    onFinished   func()
+    moments      runningStats
+    departures   departurePlan
+    tieBreaker   func(a, b *models.Vehicle) bool
+    phase        SimulationPhase
+    arrivalCtx   context.Context
+    stopArrivals context.CancelFunc
+    retryMu      sync.Mutex
+    arrivalWg    sync.WaitGroup
+    queueCtx     context.Context
+    stopQueue    context.CancelFunc
+    queueDone    chan struct{}
+    queueFrozen  atomic.Bool
+    paramChanges paramChangeLog
+    onGateFailure func(outOfService bool)
+    enteringSem  *semaphore.Weighted
+    onSite       int64
+    lambdas      lambdaTrajectory
+    intake       intakeSeries
+    clock        *simClock
+    noShows      *noShowFilter
+    deadlocks    []DeadlockReport
+}
+
+func (s *Simulation) SetQueueUpdateCallback(callback func(queueSize int)) {
+    s.onQueueUpdate = callback
+}
+
+
+func DefaultConfig() SimulationConfig {
+    return SimulationConfig{
+        ParkingCapacity: PARKING_CAPACITY,
+        MaxVehicles:     MAX_VEHICLES,
+        MinParkTime:     MIN_PARK_TIME,
+        MaxParkTime:     MAX_PARK_TIME,
+        ArrivalRate:     2.0,
+        EventBufferSize: EVENT_BUFFER_SIZE,
+        Patience:        PatienceConfig{Mode: PATIENCE_NONE},
+        RetryBackoff:    DEFAULT_RETRY_BACKOFF,
+        MaxRetries:      DEFAULT_MAX_RETRIES,
+        MaxQueueSize:    MAX_QUEUE_SIZE,
+        ClusterSize:     DEFAULT_CLUSTER_SIZE,
+        ClusterWindow:   DEFAULT_CLUSTER_WINDOW,
+        DayLength:       DEFAULT_DAY_LENGTH,
+        DailyAmplitude:  DEFAULT_DAILY_AMPLITUDE,
+        SpeedFactor:     1,
+    }
+}
+
+// ClosedLoopConfig describe un estacionamiento de trabajo: una población fija
+// de vehículos que entra, sale y regresa tras un tiempo fuera.
+func ClosedLoopConfig(population int, awayRate float64) SimulationConfig {
+    config := DefaultConfig()
+    config.ClosedPopulation = population
+    config.AwayRate = awayRate
+    config.MaxVehicles = population * 10
+    return config
+}
+
+func (c SimulationConfig) Validate() error {
+    // Capacidad 0 es válida: sirve para estudiar solo la cola.
+    if c.ParkingCapacity < 0 {
+        return errors.New("la capacidad no puede ser negativa")
+    }
+    if c.MaxVehicles < 1 {
+        return errors.New("el número máximo de vehículos debe ser al menos 1")
+    }
+    if c.MinParkTime < 0 || c.MaxParkTime < c.MinParkTime {
+        return errors.New("el rango de tiempo de estacionamiento no es válido")
+    }
+    if c.MaxQueueSize < 0 {
+        return errors.New("el tamaño de la cola no puede ser negativo")
+    }
+    if c.ClosedPopulation < 0 {
+        return errors.New("la población cerrada no puede ser negativa")
+    }
+    if c.ClosedPopulation == 0 && c.ArrivalRate <= 0 {
+        return errors.New("la tasa de llegadas debe ser positiva")
+    }
+    if c.RetryOnFullQueue && (c.RetryBackoff <= 0 || c.MaxRetries < 1) {
+        return errors.New("los reintentos requieren una espera y un número de intentos positivos")
+    }
+    if c.DepartureJitter < 0 || c.ClusterWindow < 0 {
+        return errors.New("la variación de salidas y la ventana de ráfagas no pueden ser negativas")
+    }
+    if c.MinStay < 0 {
+        return errors.New("la estancia mínima no puede ser negativa")
+    }
+    switch c.MinStayPolicy {
+    case "", MIN_STAY_EXTEND, MIN_STAY_BILL:
+    default:
+        return fmt.Errorf("política de estancia mínima desconocida: %q", c.MinStayPolicy)
+    }
+    switch c.Patience.Mode {
+    case "", PATIENCE_NONE, PATIENCE_FIXED, PATIENCE_DISTRIBUTION:
+    default:
+        return fmt.Errorf("modo de paciencia desconocido: %q", c.Patience.Mode)
+    }
+    if err := validateNoShow(c.NoShowProbability); err != nil {
+        return err
+    }
+    if err := c.InitialOccupancy.validate(); err != nil {
+        return err
+    }
+    if c.SpeedFactor <= 0 {
+        return ErrInvalidSpeed
+    }
+    if c.SiteCapacity < 0 {
+        return errors.New("la capacidad del sitio no puede ser negativa")
+    }
+    if c.MaxSimultaneousEntering < 0 {
+        return errors.New("el máximo de vehículos entrando a la vez no puede ser negativo")
+    }
+    if c.DailyPeak != "" {
+        if _, err := c.TimeMapping.ParseClock(c.DailyPeak); err != nil {
+            return err
+        }
+    }
+    if err := c.TimeMapping.validate(); err != nil {
+        return err
+    }
+    if c.UseDailyPattern && (c.DayLength <= 0 || c.DailyAmplitude < 0 || c.DailyAmplitude > 1) {
+        return errors.New("el patrón diario requiere un día positivo y una amplitud entre 0 y 1")
+    }
+    if c.OccupancyControl.IsSet() && (c.ClosedPopulation > 0 || c.UseDailyPattern) {
+        return errors.New("el control de ocupación solo funciona con llegadas de Poisson")
+    }
+    if err := c.OccupancyControl.validate(); err != nil {
+        return err
+    }
+    return c.GateFailure.validate()
+}
+
+func (s *Simulation) GetConfig() SimulationConfig {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+    return s.config
+}
+
+func NewSimulation(updateUI func(spaces int, message string)) *Simulation {
+    return NewSimulationWithConfig(DefaultConfig(), updateUI)
+}
+
+func NewSimulationWithConfig(config SimulationConfig, updateUI func(spaces int, message string)) *Simulation {
+    if config.EventBufferSize <= 0 {
+        config.EventBufferSize = EVENT_BUFFER_SIZE
+    }
+    poissonConfig := utils.DefaultPoissonConfig()
+    poissonConfig.Lambda = config.ArrivalRate 
+    sim := &Simulation{
+        config:     config,
+        poissonGen: utils.NewPoissonGenerator(poissonConfig),
+        queue:      make([]*models.Vehicle, 0, MAX_QUEUE_SIZE),
+        updateUI:   updateUI,
+        statsSince: time.Now(),
+        samples:    newSimulationSamples(),
+        moments:    newRunningStats(),
+        patience:   newPatienceSampler(config.Patience),
+        freeSpaces: newFreeSpaceTracker(config.ParkingCapacity, time.Now()),
+        stateTimes: newStateTimeStats(),
+        queueWake:  make(chan struct{}, 1),
+        clock:      newSimClock(config.SpeedFactor),
+        noShows:    newNoShowFilter(config.NoShowProbability, config.NoShowSeed),
+    }
+    sim.initContexts(context.Background())
+    sim.queueDone = make(chan struct{})
+    if config.MaxSimultaneousEntering > 0 {
+        sim.enteringSem = semaphore.NewWeighted(int64(config.MaxSimultaneousEntering))
+    }
+    sim.parking = models.NewParkingLot(config.ParkingCapacity, sim.handleLotUpdate)
+    sim.parking.UseExternalQueue()
+    sim.parking.SetGatePolicy(config.GatePolicy)
+    sim.parking.SetDoubleParkingCallback(sim.handleDoublePark)
+    if config.ClosedPopulation > 0 {
+        sim.arrivals = NewClosedLoopArrivalSource(config.ClosedPopulation, config.AwayRate)
+    } else if config.UseDailyPattern {
+        dayLength := time.Duration(config.DayLength * float64(time.Second))
+        sim.poissonGen.SetDailyPattern(config.DailyAmplitude, dailyPhase(config, dayLength))
+        source := NewDailyPatternArrivalSource(sim.poissonGen, dayLength, config.MaxVehicles)
+        source.wait = sim.sleepPausable
+        sim.arrivals = source
+    } else {
+        source := NewPoissonArrivalSource(sim.poissonGen, config.MaxVehicles)
+        source.wait = sim.sleepPausable
+        sim.arrivals = source
+    }
+    return sim
+}
+
+func (s *Simulation) SetArrivalSource(source ArrivalSource) error {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+
+    if s.running {
+        return ErrSimulationRunning
+    }
+    s.arrivals = source
+    return nil
+}
+
+// SetContext hace que la simulación dependa de ctx: al cancelarlo, la
+// simulación se detiene sola. Debe llamarse antes de Start.
+func (s *Simulation) SetContext(ctx context.Context) error {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+
+    if s.running {
+        return ErrSimulationRunning
+    }
+    s.cancel()
+    s.initContexts(ctx)
+    return nil
+}
+
+func (s *Simulation) initContexts(parent context.Context) {
+    s.parent = parent
+    s.ctx, s.cancel = context.WithCancel(parent)
+    s.arrivalCtx, s.stopArrivals = context.WithCancel(s.ctx)
+    s.queueCtx, s.stopQueue = context.WithCancel(s.ctx)
+}
+
+// watchParent detiene la simulación si se cancela el contexto padre. Si es
+// Stop quien cancela ctx, el padre sigue vivo y no hay nada que hacer. Recibe
+// los contextos de la corrida porque reset los reemplaza al reiniciar.
+func (s *Simulation) watchParent(ctx, parent context.Context) {
+    <-ctx.Done()
+    if parent.Err() != nil {
+        s.Stop()
+    }
+}
+
+// Start arranca la simulación. Después de Stop la vuelve a arrancar desde
+// cero, con la cola vacía y las estadísticas reiniciadas. Si ya está
+// corriendo devuelve ErrSimulationRunning.
+func (s *Simulation) Start() error {
+    s.stateMutex.Lock()
+    if s.running {
+        s.stateMutex.Unlock()
+        return ErrSimulationRunning
+    }
+    restart := s.ctx.Err() != nil
+    s.running = true
+    s.phase = PhaseRunning
+    s.stateMutex.Unlock()
+
+    if restart {
+        s.reset()
+    }
+
+    s.statsMutex.Lock()
+    s.statsSince = time.Now()
+    s.freeSpaces.reset(s.statsSince)
+    s.startedAt = s.statsSince
+    s.statsMutex.Unlock()
+
+    s.startVehicleLog()
+    s.preloadVehicles()
+    s.arrivalWg.Add(1)
+    go s.runSimulation() 
+    if failure := s.GetConfig().GateFailure; failure.MTBF > 0 {
+        s.arrivalWg.Add(1)
+        go s.runGateFailures(failure)
+    }
+    if control := s.GetConfig().OccupancyControl; control.IsSet() {
+        s.arrivalWg.Add(1)
+        go s.runOccupancyControl(control)
+    }
+    s.wg.Add(1)
+    go s.runIntakeSampler()
+    go s.processQueue()  
+    go s.watchParent(s.ctx, s.parent)
+    return nil
+}
+
+func (s *Simulation) IsRunning() bool {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+    return s.running
+}
+
+// SetEventBufferSize cambia el tamaño del búfer de los canales de eventos
+// que se creen a partir de ahora. Solo puede llamarse con la simulación
+// detenida. Los canales de WatchQueue ya abiertos conservan su búfer: para
+// usar el nuevo tamaño hay que cancelar la suscripción y volver a llamar a
+// WatchQueue.
+func (s *Simulation) SetEventBufferSize(n int) error {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+
+    if s.running {
+        return ErrSimulationRunning
+    }
+    if n <= 0 {
+        return errors.New("el tamaño del búfer debe ser positivo")
+    }
+    s.config.EventBufferSize = n
+    return nil
+}
+
+// SetQueueCapacity cambia el tamaño máximo de la cola, también durante la
+// simulación. Si hay más vehículos esperando que la nueva capacidad, los
+// últimos en llegar se rechazan de inmediato.
+func (s *Simulation) SetQueueCapacity(n int) error {
+    if n < 0 {
+        return errors.New("el tamaño de la cola no puede ser negativo")
+    }
+    s.stateMutex.Lock()
+    s.queueMutex.Lock()
+    previous := s.config.MaxQueueSize
+    s.config.MaxQueueSize = n
+    s.stateMutex.Unlock()
+
+    var trimmed []*models.Vehicle
+    for len(s.queue) > n {
+        previousLen := len(s.queue)
+        vehicle := s.queue[previousLen-1]
+        s.queue = s.queue[:previousLen-1]
+        s.notifyQueueChange(Rejected, vehicle, previousLen)
+        trimmed = append(trimmed, vehicle)
+    }
+    if len(trimmed) > 0 && s.onQueueUpdate != nil {
+        s.onQueueUpdate(len(s.queue))
+    }
+    s.queueMutex.Unlock()
+
+    for _, vehicle := range trimmed {
+        s.reject(vehicle)
+    }
+    s.recordParamChange("Capacidad cola", previous, n)
+    return nil
+}
+
+func (s *Simulation) processQueue() {
+    defer close(s.queueDone)
+    ticker := time.NewTicker(100 * time.Millisecond) 
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-s.queueCtx.Done(): 
+            return
+        case <-ticker.C:
+            if s.IsPaused() {
+                continue
+            }
+            s.removeImpatientVehicles()
+            s.tryProcessNextInQueue() 
+        case <-s.queueWake:
+            if !s.IsPaused() {
+                s.tryProcessNextInQueue()
+            }
+        }
+    }
+}
+
+// wakeQueue avisa a processQueue que se liberó un espacio, para no esperar al
+// siguiente tick cuando las estancias son más cortas que el sondeo.
+func (s *Simulation) wakeQueue() {
+    select {
+    case s.queueWake <- struct{}{}:
+    default:
+    }
+}
+
+func (s *Simulation) tryProcessNextInQueue() {
+    s.queueMutex.Lock()
+    if !s.queueFrozen.Load() && !s.entryClosed.Load() && len(s.queue) > 0 && s.parking.GetAvailableSpaces() > 0 {
+        vehicle := s.queue[0] 
+        s.queue = s.queue[1:] 
+        s.notifyQueueChange(Removed, vehicle, len(s.queue)+1)
+        s.hazard.record(vehicle.GetWaitDuration(), false)
+        s.queueMutex.Unlock()
+
+        s.wg.Add(1)
+        go s.processVehicle(vehicle) 
+    } else {
+        s.queueMutex.Unlock()
+    }
+}
+
+func (s *Simulation) runSimulation() {
+    defer s.arrivalWg.Done()
+    defer s.notifyFinished()
+
+    visits := 0
+    for visits < s.config.MaxVehicles {
+        vehicle, ok := s.arrivals.Next(s.arrivalCtx)
+        if !ok || !s.waitResumed(s.arrivalCtx) {
+            return
+        }
+        visits++
+        if s.noShows.skip() {
+            s.recordNoShow(vehicle)
+            continue
+        }
+        vehicle.Patience = s.patience.sample()
+        atomic.AddInt64(&s.metrics.TotalArrivals, 1)
+        if vehicle.Visit == 1 {
+            atomic.AddInt64(&s.metrics.TotalVehicles, 1)
+        }
+        if !s.enterSite() {
+            s.shedAtSite(vehicle)
+            continue
+        }
+        if s.config.ParkingCapacity > 0 {
+            s.samples.occupancy.Add(float64(s.parking.GetOccupancy()) / float64(s.config.ParkingCapacity))
+        }
+
+        if s.entryClosed.Load() {
+            s.handleClosedEntrance(vehicle)
+        } else if s.parking.GetAvailableSpaces() > 0 {
+            s.wg.Add(1)
+            go s.processVehicle(vehicle) 
+        } else {
+            s.queueOrReject(vehicle)
+        }
+    }
+}
+
+// SetFinishedCallback registra la función que se llama cuando terminan las
+// llegadas por haber alcanzado MaxVehicles, no cuando se detiene con Stop.
+func (s *Simulation) SetFinishedCallback(callback func()) {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+    s.onFinished = callback
+}
+
+func (s *Simulation) notifyFinished() {
+    if s.arrivalCtx.Err() != nil {
+        return
+    }
+    s.stateMutex.Lock()
+    callback := s.onFinished
+    s.stateMutex.Unlock()
+    if callback != nil {
+        callback()
+    }
+}
+
+func (s *Simulation) notifyDeparture(vehicle *models.Vehicle) {
+    s.leaveSite()
+    s.notifyObserver(vehicle)
+}
+
+func (s *Simulation) notifyObserver(vehicle *models.Vehicle) {
+    if observer, ok := s.arrivals.(DepartureObserver); ok {
+        observer.OnDeparture(vehicle)
+    }
+}
+
+// queueOrReject intenta poner al vehículo en la cola. Si está llena y los
+// reintentos están activados, lo vuelve a intentar en segundo plano; si no,
+// el vehículo queda rechazado.
+func (s *Simulation) queueOrReject(vehicle *models.Vehicle) {
+    if s.addToQueue(vehicle) {
+        return
+    }
+    if s.config.RetryOnFullQueue && s.config.MaxRetries > 0 && s.startRetry() {
+        go s.retryQueue(vehicle)
+        return
+    }
+    s.reject(vehicle)
+}
+
+// startRetry suma el reintento a arrivalWg, salvo que las llegadas ya se
+// hayan detenido: Stop podría estar esperando arrivalWg, y un reintento
+// nuevo no debe alargar esa fase.
+func (s *Simulation) startRetry() bool {
+    s.retryMu.Lock()
+    defer s.retryMu.Unlock()
+    if s.arrivalCtx.Err() != nil {
+        return false
+    }
+    s.arrivalWg.Add(1)
+    return true
+}
+
+// haltArrivals cancela las llegadas con retryMu tomado, así ningún reintento
+// se suma a arrivalWg después de que Stop empieza a esperarlo.
+func (s *Simulation) haltArrivals() {
+    s.retryMu.Lock()
+    defer s.retryMu.Unlock()
+    s.stopArrivals()
+}
+
+// retryQueue reintenta entrar a la cola con espera exponencial:
+// RetryBackoff, 2*RetryBackoff, 4*RetryBackoff... La espera es de tiempo
+// simulado, así que no avanza en pausa.
+func (s *Simulation) retryQueue(vehicle *models.Vehicle) {
+    defer s.arrivalWg.Done()
+
+    backoff := s.config.RetryBackoff
+    for attempt := 0; attempt < s.config.MaxRetries; attempt++ {
+        if !s.sleepPausable(s.arrivalCtx, backoff) {
+            s.reject(vehicle)
+            return
+        }
+
+        vehicle.EntryAttempts++
+        if s.parking.GetAvailableSpaces() > 0 {
+            atomic.AddInt64(&s.metrics.TotalRetries, 1)
+            s.wg.Add(1)
+            go s.processVehicle(vehicle)
+            return
+        }
+        if s.addToQueue(vehicle) {
+            atomic.AddInt64(&s.metrics.TotalRetries, 1)
+            return
+        }
+        backoff *= 2
+    }
+    s.reject(vehicle)
+}
+
+func (s *Simulation) reject(vehicle *models.Vehicle) {
+    atomic.AddInt64(&s.metrics.TotalRejected, 1)
+    s.samples.rejection.Add(1)
+    s.recordOutcome(vehicle, OUTCOME_REJECTED, 0)
+    s.notifyDeparture(vehicle)
+}
+
+func (s *Simulation) addToQueue(vehicle *models.Vehicle) bool {
+    s.queueMutex.Lock()
+    defer s.queueMutex.Unlock()
+
+    if len(s.queue) >= s.config.MaxQueueSize { 
+        return false
+    }
+
+    vehicle.QueuedAt = time.Now()
+    s.insertQueued(vehicle)
+    atomic.AddInt64(&s.metrics.TotalQueued, 1)
+    queueLength := len(s.queue)
+    s.notifyQueueChange(Added, vehicle, queueLength-1)
+    s.checkQueued(vehicle)
+
+
+    if s.onQueueUpdate != nil {
+        s.onQueueUpdate(queueLength)
+    }
+
+    return true
+}
+
+func (s *Simulation) processVehicle(vehicle *models.Vehicle) {
+    defer s.wg.Done()
+    atomic.AddInt64(&s.metrics.ActiveWorkers, 1)
+    defer atomic.AddInt64(&s.metrics.ActiveWorkers, -1)
+
+    if s.queueFrozen.Load() {
+        s.notifyDeparture(vehicle)
+        return
+    }
+    if s.entryClosed.Load() {
+        s.handleClosedEntrance(vehicle)
+        return
+    }
+    entered, ok := s.enterThrottled(vehicle)
+    if !ok {
+        s.notifyDeparture(vehicle)
+        return
+    }
+
+    if !entered {
+        if s.queueFrozen.Load() {
+            s.notifyDeparture(vehicle)
+            return
+        }
+        s.queueOrReject(vehicle)
+        return
+    }
+    atomic.AddInt64(&s.metrics.TotalEntered, 1)
+    s.checkAdmission(vehicle)
+    s.samples.wait.Add(vehicle.GetWaitDuration().Seconds())
+    s.moments.wait.Update(vehicle.GetWaitDuration().Seconds())
+    s.recordWait(vehicle.GetWaitDuration())
+    s.samples.rejection.Add(0)
+
+    parkTime := s.applyStay(vehicle)
+    if !s.sleepPausable(s.ctx, parkTime) {
+        s.parking.Exit(vehicle) 
+        s.leaveSite()
+        atomic.AddInt64(&s.metrics.TotalExited, 1)
+        s.recordExit(vehicle)
+        return
+    }
+    s.parking.Exit(vehicle) 
+    s.wakeQueue()
+    atomic.AddInt64(&s.metrics.TotalExited, 1)
+    s.recordExit(vehicle)
+    s.notifyDeparture(vehicle)
+}
+
+// enterThrottled limita cuántos vehículos pueden estar entrando a la vez
+// según MaxSimultaneousEntering. TryEnter regresa ya en Parked, así que el
+// lugar se libera en la transición Entering→Parked. ok es falso si la
+// simulación terminó mientras esperaba turno.
+func (s *Simulation) enterThrottled(vehicle *models.Vehicle) (entered, ok bool) {
+    if s.enteringSem == nil {
+        return s.parking.TryEnter(vehicle), true
+    }
+    if err := s.enteringSem.Acquire(s.ctx, 1); err != nil {
+        return false, false
+    }
+    defer s.enteringSem.Release(1)
+    return s.parking.TryEnter(vehicle), true
+}
+
+func (s *Simulation) recordExit(vehicle *models.Vehicle) {
+    park := vehicle.GetParkingDuration().Seconds()
+    response := vehicle.GetResponseTime().Seconds()
+    s.samples.park.Add(park)
+    s.samples.response.Add(response)
+    s.moments.park.Update(park)
+    s.moments.response.Update(response)
+    s.recordOutcome(vehicle, OUTCOME_EXITED, vehicle.GetParkingDuration())
+}
+
+func (s *Simulation) GetMetrics() SimulationMetrics {
+    return s.metrics.Snapshot()
+}
+
+// ResetStatistics reinicia las estadísticas sin detener la simulación. La
+// ocupación y la cola no cambian. Cada evento se atribuye al periodo en que
+// ocurre: un vehículo que entró a la cola antes del reinicio y se admite
+// después cuenta como entrada en el nuevo periodo, pero no como encolado.
+func (s *Simulation) ResetStatistics() {
+    s.resetStatistics()
+    if s.updateUI != nil {
+        s.updateUI(int(s.parking.GetAvailableSpaces()), "Estadísticas reiniciadas")
+    }
+}
+
+func (s *Simulation) resetStatistics() {
+    s.statsMutex.Lock()
+    s.metrics.Reset()
+    s.samples.reset()
+    s.moments.reset()
+    s.departures.reset()
+    s.hazard.reset()
+    s.stateTimes.reset()
+    s.intake.reset()
+    atomic.StoreInt64(&s.worstWait, 0)
+    s.statsSince = time.Now()
+    s.busy.reset(s.statsSince)
+    s.freeSpaces.reset(s.statsSince)
+    s.statsMutex.Unlock()
+}
+
+func (s *Simulation) GetStatisticsSince() time.Time {
+    s.statsMutex.RLock()
+    defer s.statsMutex.RUnlock()
+    return s.statsSince
+}
+
+func (s *Simulation) GetAvailableSpaces() int {
+    return int(s.parking.GetAvailableSpaces())
+}
+
+func (s *Simulation) GetOccupancy() int {
+    return s.parking.GetOccupancy()
+}
+
+func (s *Simulation) SetSpaceLabel(spaceID int, label string) error {
+    return s.parking.SetSpaceLabel(spaceID, label)
+}
+
+func (s *Simulation) GetSpaceLabel(spaceID int) (string, error) {
+    return s.parking.GetSpaceLabel(spaceID)
+}
+
+func (s *Simulation) SetSpaceLabelCallback(callback func(spaceID int, label string)) {
+    s.parking.SetSpaceLabelCallback(callback)
+}
+
+func (s *Simulation) GetSpaces() []models.ParkingSpace {
+    return s.parking.GetSpaces()
+}
+
+func (s *Simulation) GetLargestFreeBlock() int {
+    return s.parking.GetLargestFreeBlock()
+}
+
+func (s *Simulation) SetOccupancyAlertThreshold(pct float64, callback func(float64)) {
+    s.parking.SetOccupancyAlertThreshold(pct, callback)
+}
+
+func (s *Simulation) ClearOccupancyAlert() {
+    s.parking.ClearAlert()
+}
+
+func (s *Simulation) ValidateParking() []error {
+    return append(s.parking.Validate(), s.validateSite()...)
+}
+
+func (s *Simulation) SetDoubleParking(allowed bool, penalty time.Duration) {
+    s.parking.SetDoubleParking(allowed, penalty)
+}
+
+func (s *Simulation) SetDoubleParkingCallback(callback func(spaceID int, blocked bool)) {
+    s.stateMutex.Lock()
+    defer s.stateMutex.Unlock()
+    s.onDoublePark = callback
+}
+
+// handleDoublePark cuenta los bloqueos y, cuando el espacio se libera, despierta
+// la cola igual que una salida normal.
+func (s *Simulation) handleDoublePark(spaceID int, blocked bool) {
+    if blocked {
+        atomic.AddInt64(&s.metrics.TotalDoubleParks, 1)
+    } else {
+        s.wakeQueue()
+    }
+    s.stateMutex.Lock()
+    callback := s.onDoublePark
+    s.stateMutex.Unlock()
+    if callback != nil {
+        callback(spaceID, blocked)
+    }
+}
+
+func (s *Simulation) GetGateMaxWait(direction models.GateDirection) time.Duration {
+    return s.parking.GetGateMaxWait(direction)
+}
+
+func (s *Simulation) GetGateCrossings() []models.GateCrossing {
+    return s.parking.GetGateCrossings()
+}
+
+func (s *Simulation) GetConflictHistory() []models.SpaceConflict {
+    return s.parking.GetConflictHistory()
+}
+
+func (s *Simulation) GetQueueLength() int {
+    s.queueMutex.RLock()
+    defer s.queueMutex.RUnlock()
+    return len(s.queue)
+}
+
+func (s *Simulation) generateParkingTime() time.Duration {
+    parkTime := s.config.MinParkTime + rand.Float64()*(s.config.MaxParkTime-s.config.MinParkTime)
+    return s.applyJitter(time.Duration(parkTime * float64(time.Second)))
+}
+
+
+
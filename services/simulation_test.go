@@ -0,0 +1,380 @@
+package services
+
+import (
+    "context"
+    "fmt"
+    "reflect"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+// waitForCounter espera hasta que counter llegue a want o pase un segundo.
+func waitForCounter(counter *int64, want int64) bool {
+    deadline := time.Now().Add(time.Second)
+    for time.Now().Before(deadline) {
+        if atomic.LoadInt64(counter) >= want {
+            return true
+        }
+        time.Sleep(5 * time.Millisecond)
+    }
+    return false
+}
+
+// burstArrivals entrega n vehículos seguidos y luego espera a que termine la
+// corrida sin generar más llegadas.
+type burstArrivals struct {
+    n, next int
+}
+
+func (b *burstArrivals) Next(ctx context.Context) (*models.Vehicle, bool) {
+    if b.next < b.n {
+        b.next++
+        return models.NewVehicle(b.next), true
+    }
+    <-ctx.Done()
+    return nil, false
+}
+
+func TestResetStatisticsAttributesInFlightVehicles(t *testing.T) {
+    const (
+        beforeQueue = iota
+        whileQueued
+        afterAdmission
+    )
+    tests := []struct {
+        name        string
+        resetAt     int
+        wantQueued  int64
+        wantEntered int64
+    }{
+        {"reinicio antes de encolar", beforeQueue, 1, 1},
+        {"encolado antes y admitido después", whileQueued, 0, 1},
+        {"reinicio después de admitir", afterAdmission, 0, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := drainConfig(30, 60)
+            config.ParkingCapacity = 1
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            defer sim.Stop()
+
+            blocker := models.NewVehicle(100)
+            if !sim.parking.TryEnter(blocker) {
+                t.Fatal("el primer vehículo no pudo entrar")
+            }
+            if tt.resetAt == beforeQueue {
+                sim.ResetStatistics()
+            }
+            if !sim.addToQueue(models.NewVehicle(1)) {
+                t.Fatal("no se pudo encolar")
+            }
+            if tt.resetAt == whileQueued {
+                sim.ResetStatistics()
+            }
+            sim.parking.Exit(blocker)
+            sim.tryProcessNextInQueue()
+            if !waitForCounter(&sim.metrics.TotalEntered, 1) {
+                t.Fatal("el vehículo encolado no entró")
+            }
+            if tt.resetAt == afterAdmission {
+                sim.ResetStatistics()
+            }
+
+            metrics := sim.GetMetrics()
+            if metrics.TotalQueued != tt.wantQueued || metrics.TotalEntered != tt.wantEntered {
+                t.Errorf("encolados = %d, entradas = %d, want %d y %d",
+                    metrics.TotalQueued, metrics.TotalEntered, tt.wantQueued, tt.wantEntered)
+            }
+        })
+    }
+}
+
+func TestRetryOnFullQueueEventuallyEnters(t *testing.T) {
+    tests := []struct {
+        name         string
+        retry        bool
+        maxRetries   int
+        wantEntered  int64
+        wantRejected int64
+    }{
+        {"sin reintentos se rechazan", false, 0, 2, 2},
+        {"con reintentos entran todos", true, 8, 4, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := drainConfig(0.1, 0.2)
+            config.ParkingCapacity = 1
+            config.MaxQueueSize = 1
+            config.RetryOnFullQueue = tt.retry
+            config.RetryBackoff = 50 * time.Millisecond
+            config.MaxRetries = tt.maxRetries
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if err := sim.SetArrivalSource(&burstArrivals{n: 4}); err != nil {
+                t.Fatal(err)
+            }
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            defer sim.Stop()
+
+            deadline := time.Now().Add(5 * time.Second)
+            for sim.GetOccupancy() > 0 || sim.GetQueueLength() > 0 ||
+                atomic.LoadInt64(&sim.metrics.TotalExited)+atomic.LoadInt64(&sim.metrics.TotalRejected) < 4 {
+                if time.Now().After(deadline) {
+                    t.Fatalf("no se resolvieron las 4 llegadas: ocupación %d, cola %d, %+v",
+                        sim.GetOccupancy(), sim.GetQueueLength(), sim.GetMetrics())
+                }
+                time.Sleep(10 * time.Millisecond)
+            }
+
+            metrics := sim.GetMetrics()
+            if metrics.TotalEntered != tt.wantEntered || metrics.TotalRejected != tt.wantRejected {
+                t.Errorf("entradas = %d, rechazos = %d, want %d y %d",
+                    metrics.TotalEntered, metrics.TotalRejected, tt.wantEntered, tt.wantRejected)
+            }
+            if tt.retry && metrics.TotalRetries < 2 {
+                t.Errorf("reintentos exitosos = %d, want al menos 2", metrics.TotalRetries)
+            }
+        })
+    }
+}
+
+func TestSetQueueCapacityTrimsNewest(t *testing.T) {
+    tests := []struct {
+        name        string
+        queued      int
+        capacity    int
+        wantTrimmed []int
+    }{
+        {"sin cambios", 5, 5, nil},
+        {"más grande", 5, 10, nil},
+        {"recorta los más nuevos", 5, 3, []int{5, 4}},
+        {"vacía la cola", 3, 0, []int{3, 2, 1}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.ParkingCapacity = 1
+            config.MaxQueueSize = tt.queued
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            for id := 1; id <= tt.queued; id++ {
+                vehicle := models.NewVehicle(id)
+                vehicle.ArrivalTime = time.Now()
+                sim.enterSite()
+                if !sim.addToQueue(vehicle) {
+                    t.Fatalf("no se pudo encolar el vehículo %d", id)
+                }
+            }
+            ctx, cancel := context.WithCancel(context.Background())
+            defer cancel()
+            events := sim.WatchQueue(ctx)
+
+            if err := sim.SetQueueCapacity(tt.capacity); err != nil {
+                t.Fatal(err)
+            }
+
+            var trimmed []int
+            for range tt.wantTrimmed {
+                select {
+                case event := <-events:
+                    if event.ChangeType != Rejected {
+                        t.Fatalf("evento de tipo %d, want Rejected", event.ChangeType)
+                    }
+                    trimmed = append(trimmed, event.Changed.ID)
+                case <-time.After(time.Second):
+                    t.Fatalf("llegaron %d recortes, want %d", len(trimmed), len(tt.wantTrimmed))
+                }
+            }
+            if !reflect.DeepEqual(trimmed, tt.wantTrimmed) {
+                t.Errorf("recortados = %v, want %v", trimmed, tt.wantTrimmed)
+            }
+            wantLen := min(tt.queued, tt.capacity)
+            if got := sim.GetQueueLength(); got != wantLen {
+                t.Errorf("largo de la cola = %d, want %d", got, wantLen)
+            }
+            if got := sim.GetMetrics().TotalRejected; got != int64(len(tt.wantTrimmed)) {
+                t.Errorf("rechazos = %d, want %d", got, len(tt.wantTrimmed))
+            }
+            if got := sim.GetConfig().MaxQueueSize; got != tt.capacity {
+                t.Errorf("MaxQueueSize = %d, want %d", got, tt.capacity)
+            }
+            if errs := sim.ValidateParking(); len(errs) != 0 {
+                t.Errorf("ValidateParking() = %v", errs)
+            }
+        })
+    }
+}
+
+func TestSetQueueCapacityRejectsNegative(t *testing.T) {
+    sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+    if err := sim.SetQueueCapacity(-1); err == nil {
+        t.Error("SetQueueCapacity(-1) no devolvió error")
+    }
+}
+
+func TestDoubleParkReleaseWakesQueue(t *testing.T) {
+    tests := []struct {
+        name    string
+        blocked bool
+        wake    bool
+    }{
+        {"bloqueo", true, false},
+        {"liberación", false, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+            sim.handleDoublePark(0, tt.blocked)
+            select {
+            case <-sim.queueWake:
+                if !tt.wake {
+                    t.Error("un bloqueo despertó la cola")
+                }
+            default:
+                if tt.wake {
+                    t.Error("liberar el espacio no despertó la cola")
+                }
+            }
+        })
+    }
+}
+
+// tracedArrivals entrega n vehículos separados por gap y guarda los que
+// dejan el sistema.
+type tracedArrivals struct {
+    n, next  int
+    gap      time.Duration
+    mu       sync.Mutex
+    departed []*models.Vehicle
+}
+
+func (a *tracedArrivals) Next(ctx context.Context) (*models.Vehicle, bool) {
+    if a.next >= a.n {
+        <-ctx.Done()
+        return nil, false
+    }
+    select {
+    case <-ctx.Done():
+        return nil, false
+    case <-time.After(a.gap):
+    }
+    a.next++
+    return models.NewVehicle(a.next), true
+}
+
+func (a *tracedArrivals) OnDeparture(vehicle *models.Vehicle) {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    a.departed = append(a.departed, vehicle)
+}
+
+func (a *tracedArrivals) departures() []*models.Vehicle {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    return append([]*models.Vehicle(nil), a.departed...)
+}
+
+func TestVeryShortStaysKeepCountersAndTracesConsistent(t *testing.T) {
+    tests := []struct {
+        name     string
+        capacity int
+        vehicles int
+        gap      time.Duration
+    }{
+        {"un espacio", 1, 200, 2 * time.Millisecond},
+        {"cinco espacios", 5, 300, time.Millisecond},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := drainConfig(0.01, 0.01)
+            config.ParkingCapacity = tt.capacity
+            config.MaxVehicles = tt.vehicles
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            source := &tracedArrivals{n: tt.vehicles, gap: tt.gap}
+            if err := sim.SetArrivalSource(source); err != nil {
+                t.Fatal(err)
+            }
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            deadline := time.Now().Add(10 * time.Second)
+            for len(source.departures()) < tt.vehicles {
+                if time.Now().After(deadline) {
+                    t.Fatalf("salieron %d de %d vehículos: %+v", len(source.departures()), tt.vehicles, sim.GetMetrics())
+                }
+                time.Sleep(10 * time.Millisecond)
+            }
+            sim.Stop()
+
+            metrics := sim.GetMetrics()
+            if metrics.TotalArrivals != int64(tt.vehicles) {
+                t.Errorf("llegadas = %d, want %d", metrics.TotalArrivals, tt.vehicles)
+            }
+            if metrics.TotalEntered != metrics.TotalExited {
+                t.Errorf("entradas = %d, salidas = %d", metrics.TotalEntered, metrics.TotalExited)
+            }
+            if resolved := metrics.TotalExited + metrics.TotalRejected + metrics.TotalAbandoned; resolved != int64(tt.vehicles) {
+                t.Errorf("salidas + rechazos + abandonos = %d, want %d", resolved, tt.vehicles)
+            }
+            if metrics.TotalEntered == 0 {
+                t.Error("no entró ningún vehículo")
+            }
+            if errs := sim.ValidateParking(); len(errs) != 0 {
+                t.Errorf("ValidateParking() = %v", errs)
+            }
+
+            for _, vehicle := range source.departures() {
+                summary := vehicle.GetLifecycleSummary()
+                history := summary.StateHistory
+                if summary.EntryTime.IsZero() {
+                    continue
+                }
+                want := models.VehicleStates
+                if len(history) != len(want) {
+                    t.Fatalf("vehículo %d: %d transiciones, want %d: %+v", vehicle.ID, len(history), len(want), history)
+                }
+                for i, transition := range history {
+                    if transition.State != want[i] {
+                        t.Fatalf("vehículo %d: transición %d = %v, want %v", vehicle.ID, i, transition.State, want[i])
+                    }
+                    if i > 0 && transition.At.Before(history[i-1].At) {
+                        t.Fatalf("vehículo %d: transición %d antes que la anterior", vehicle.ID, i)
+                    }
+                }
+            }
+        })
+    }
+}
+
+// BenchmarkEnterThrottled mide cuántos vehículos por segundo pasan por la
+// entrada con distintos topes de vehículos entrando a la vez. Con tope 0 no
+// hay semáforo.
+func BenchmarkEnterThrottled(b *testing.B) {
+    for _, limit := range []int{0, 1, 2, 4} {
+        b.Run(fmt.Sprintf("max=%d", limit), func(b *testing.B) {
+            config := DefaultConfig()
+            config.ParkingCapacity = 1024
+            config.MaxSimultaneousEntering = limit
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            defer sim.cancel()
+            var nextID int64
+
+            b.ResetTimer()
+            b.RunParallel(func(pb *testing.PB) {
+                for pb.Next() {
+                    vehicle := models.NewVehicle(int(atomic.AddInt64(&nextID, 1)))
+                    entered, ok := sim.enterThrottled(vehicle)
+                    if !entered || !ok {
+                        b.Errorf("el vehículo %d no pudo entrar", vehicle.ID)
+                        return
+                    }
+                    sim.parking.Exit(vehicle)
+                }
+            })
+            b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "veh/s")
+        })
+    }
+}
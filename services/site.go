@@ -0,0 +1,63 @@
+package services
+
+import (
+    "fmt"
+    "sync/atomic"
+    "holafyne/models"
+)
+
+// enterSite cuenta al vehículo dentro del predio: en la cola, en la pluma,
+// entrando, estacionado o saliendo. Con SiteCapacity en cero solo cuenta; si
+// no, falla cuando el predio está lleno. notifyDeparture lo descuenta.
+func (s *Simulation) enterSite() bool {
+    capacity := int64(s.config.SiteCapacity)
+    for {
+        current := atomic.LoadInt64(&s.onSite)
+        if capacity > 0 && current >= capacity {
+            return false
+        }
+        if atomic.CompareAndSwapInt64(&s.onSite, current, current+1) {
+            return true
+        }
+    }
+}
+
+func (s *Simulation) leaveSite() {
+    atomic.AddInt64(&s.onSite, -1)
+}
+
+// shedAtSite descarta una llegada con el predio lleno, antes de la cola y de
+// la pluma. Cuenta como rechazo, con su propio motivo.
+func (s *Simulation) shedAtSite(vehicle *models.Vehicle) {
+    atomic.AddInt64(&s.metrics.SiteFull, 1)
+    atomic.AddInt64(&s.metrics.TotalRejected, 1)
+    s.samples.rejection.Add(1)
+    s.recordOutcome(vehicle, OUTCOME_SITE_FULL, 0)
+    s.notifyObserver(vehicle)
+}
+
+// GetVehiclesOnSite es cuántos vehículos hay dentro del predio.
+func (s *Simulation) GetVehiclesOnSite() int {
+    return int(atomic.LoadInt64(&s.onSite))
+}
+
+// validateSite revisa el contador del predio. Con la simulación en marcha los
+// vehículos entran y salen entre lecturas, así que la comparación con la
+// ocupación y la cola solo se hace con la simulación detenida.
+func (s *Simulation) validateSite() []error {
+    var errs []error
+    onSite := s.GetVehiclesOnSite()
+    if onSite < 0 {
+        errs = append(errs, fmt.Errorf("el contador del sitio es negativo: %d", onSite))
+    }
+    if capacity := s.config.SiteCapacity; capacity > 0 && onSite > capacity {
+        errs = append(errs, fmt.Errorf("hay %d vehículos en el sitio, más que su capacidad de %d", onSite, capacity))
+    }
+    if s.IsRunning() {
+        return errs
+    }
+    if inside := s.parking.GetOccupancy() + s.GetQueueLength(); onSite < inside {
+        errs = append(errs, fmt.Errorf("el contador del sitio (%d) es menor que los estacionados y en cola (%d)", onSite, inside))
+    }
+    return errs
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestSiteCapacitySheds(t *testing.T) {
+    tests := []struct {
+        name     string
+        site     int
+        entering int
+        // Con 3 espacios, 5 lugares en la cola y estancias largas, las 12
+        // llegadas se reparten entre adentro, sitio lleno y cola llena.
+        wantOnSite   int
+        wantSiteFull int64
+        wantRejected int64
+    }{
+        {"sin tope", 0, 0, 8, 0, 4},
+        {"tope por debajo de espacios y cola", 4, 0, 4, 8, 8},
+        {"tope mayor que espacios y cola", 20, 0, 8, 0, 4},
+        {"tope con un solo vehículo entrando a la vez", 5, 1, 5, 7, 7},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := drainConfig(60, 60)
+            config.MaxVehicles = 12
+            config.SiteCapacity = tt.site
+            config.MaxSimultaneousEntering = tt.entering
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if err := sim.SetArrivalSource(&burstArrivals{n: 12}); err != nil {
+                t.Fatal(err)
+            }
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+
+            settled := func() bool {
+                return sim.GetOccupancy() == 3 &&
+                    int64(sim.GetOccupancy()+sim.GetQueueLength())+atomic.LoadInt64(&sim.metrics.TotalRejected) == 12
+            }
+            deadline := time.Now().Add(5 * time.Second)
+            for !settled() {
+                if tt.site > 0 && sim.GetVehiclesOnSite() > tt.site {
+                    t.Fatalf("%d vehículos en el sitio, más que el tope de %d", sim.GetVehiclesOnSite(), tt.site)
+                }
+                if time.Now().After(deadline) {
+                    t.Fatalf("no se asentaron las llegadas: ocupación %d, cola %d, %+v",
+                        sim.GetOccupancy(), sim.GetQueueLength(), sim.GetMetrics())
+                }
+                time.Sleep(5 * time.Millisecond)
+            }
+
+            metrics := sim.GetMetrics()
+            if got := sim.GetVehiclesOnSite(); got != tt.wantOnSite {
+                t.Errorf("vehículos en el sitio = %d, want %d", got, tt.wantOnSite)
+            }
+            if metrics.SiteFull != tt.wantSiteFull || metrics.TotalRejected != tt.wantRejected {
+                t.Errorf("sitio lleno %d y rechazados %d, want %d y %d",
+                    metrics.SiteFull, metrics.TotalRejected, tt.wantSiteFull, tt.wantRejected)
+            }
+            if errs := sim.ValidateParking(); len(errs) != 0 {
+                t.Errorf("ValidateParking en marcha: %v", errs)
+            }
+
+            // Stop cancela las estancias pero congela la cola: los que
+            // esperaban siguen dentro del sitio.
+            sim.Stop()
+            if got, queued := sim.GetVehiclesOnSite(), sim.GetQueueLength(); got != queued {
+                t.Errorf("vehículos en el sitio después de Stop = %d, want los %d en cola", got, queued)
+            }
+            if errs := sim.ValidateParking(); len(errs) != 0 {
+                t.Errorf("ValidateParking detenida: %v", errs)
+            }
+        })
+    }
+}
+
+func TestValidateSiteCounter(t *testing.T) {
+    tests := []struct {
+        name    string
+        site    int
+        onSite  int64
+        wantErr bool
+    }{
+        {"vacío", 4, 0, false},
+        {"contador negativo", 4, -1, true},
+        {"por encima del tope", 4, 5, true},
+        {"sin tope no hay máximo", 0, 50, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := drainConfig(1, 2)
+            config.SiteCapacity = tt.site
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            sim.onSite = tt.onSite
+            if errs := sim.validateSite(); (len(errs) > 0) != tt.wantErr {
+                t.Errorf("validateSite() = %v, wantErr %v", errs, tt.wantErr)
+            }
+        })
+    }
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+    "sync"
+    "time"
+    "holafyne/models"
+    "holafyne/utils"
+)
+
+// stateTimeStats acumula, por estado, el tiempo que pasaron en él los
+// vehículos que ya terminaron su recorrido.
+type stateTimeStats struct {
+    mu     sync.Mutex
+    states map[models.VehicleState]*utils.WelfordOnlineStats
+}
+
+func newStateTimeStats() *stateTimeStats {
+    st := &stateTimeStats{states: make(map[models.VehicleState]*utils.WelfordOnlineStats)}
+    for _, state := range models.VehicleStates {
+        st.states[state] = utils.NewWelfordOnlineStats()
+    }
+    return st
+}
+
+func (st *stateTimeStats) record(times map[models.VehicleState]time.Duration) {
+    st.mu.Lock()
+    defer st.mu.Unlock()
+    for _, state := range models.VehicleStates {
+        st.states[state].Update(times[state].Seconds())
+    }
+}
+
+func (st *stateTimeStats) reset() {
+    st.mu.Lock()
+    defer st.mu.Unlock()
+    for _, stats := range st.states {
+        stats.Reset()
+    }
+}
+
+// recordOutcome registra el final del recorrido de un vehículo: su tiempo
+// por estado y su fila en el registro de vehículos.
+func (s *Simulation) recordOutcome(vehicle *models.Vehicle, outcome string, stay time.Duration) {
+    times := vehicle.GetTimeInState()
+    s.stateTimes.record(times)
+    s.vehicleLog.record(vehicle, outcome, stay, times)
+}
+
+// GetMeanTimeInState devuelve el tiempo medio por estado de los vehículos
+// que terminaron su recorrido desde el último reinicio de estadísticas. Los
+// rechazados y los que abandonan la cola solo suman tiempo esperando.
+func (s *Simulation) GetMeanTimeInState() map[models.VehicleState]time.Duration {
+    s.stateTimes.mu.Lock()
+    defer s.stateTimes.mu.Unlock()
+
+    means := make(map[models.VehicleState]time.Duration, len(s.stateTimes.states))
+    for state, stats := range s.stateTimes.states {
+        means[state] = secondsToDuration(stats.Mean())
+    }
+    return means
+}
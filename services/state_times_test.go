@@ -0,0 +1,51 @@
+package services
+
+import (
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+func TestMeanTimeInStateAggregatesIncrementally(t *testing.T) {
+    s := time.Second
+    tests := []struct {
+        name      string
+        recorded  []map[models.VehicleState]time.Duration
+        wantMeans map[models.VehicleState]time.Duration
+    }{
+        {"sin vehículos", nil, map[models.VehicleState]time.Duration{}},
+        {"un recorrido completo", []map[models.VehicleState]time.Duration{
+            {models.Waiting: 3 * s, models.Entering: s, models.Parked: 40 * s, models.Exiting: 2 * s},
+        }, map[models.VehicleState]time.Duration{models.Waiting: 3 * s, models.Entering: s, models.Parked: 40 * s, models.Exiting: 2 * s}},
+        {"un rechazado cuenta cero en los demás estados", []map[models.VehicleState]time.Duration{
+            {models.Waiting: 2 * s, models.Entering: 2 * s, models.Parked: 20 * s, models.Exiting: 2 * s},
+            {models.Waiting: 4 * s},
+        }, map[models.VehicleState]time.Duration{models.Waiting: 3 * s, models.Entering: s, models.Parked: 10 * s, models.Exiting: s}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+            for i, times := range tt.recorded {
+                sim.stateTimes.record(times)
+                if i == 0 {
+                    if got := sim.GetMeanTimeInState()[models.Parked]; got != times[models.Parked] {
+                        t.Errorf("después del primero: estacionado = %v, want %v", got, times[models.Parked])
+                    }
+                }
+            }
+            means := sim.GetMeanTimeInState()
+            for _, state := range models.VehicleStates {
+                if got := means[state]; got != tt.wantMeans[state] {
+                    t.Errorf("media %v = %v, want %v", state, got, tt.wantMeans[state])
+                }
+            }
+
+            sim.ResetStatistics()
+            for state, got := range sim.GetMeanTimeInState() {
+                if got != 0 {
+                    t.Errorf("después de reiniciar: media %v = %v, want 0", state, got)
+                }
+            }
+        })
+    }
+}
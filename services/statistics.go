@@ -0,0 +1,243 @@
+package services
+
+import (
+    "math"
+    "time"
+    "holafyne/utils"
+)
+
+const SAMPLE_RESERVOIR_SIZE = 1000
+
+// Umbrales mínimos de cada calificación de eficiencia.
+const (
+    GRADE_A_PLUS = 0.9
+    GRADE_A      = 0.75
+    GRADE_B      = 0.6
+    GRADE_C      = 0.4
+)
+
+type simulationSamples struct {
+    wait      *utils.Reservoir
+    park      *utils.Reservoir
+    occupancy *utils.Reservoir
+    rejection *utils.Reservoir
+    response  *utils.Reservoir
+}
+
+func newSimulationSamples() simulationSamples {
+    seed := time.Now().UnixNano()
+    return simulationSamples{
+        wait:      utils.NewReservoir(SAMPLE_RESERVOIR_SIZE, seed),
+        park:      utils.NewReservoir(SAMPLE_RESERVOIR_SIZE, seed+1),
+        occupancy: utils.NewReservoir(SAMPLE_RESERVOIR_SIZE, seed+2),
+        rejection: utils.NewReservoir(SAMPLE_RESERVOIR_SIZE, seed+3),
+        response:  utils.NewReservoir(SAMPLE_RESERVOIR_SIZE, seed+4),
+    }
+}
+
+func (ss simulationSamples) reset() {
+    ss.wait.Reset()
+    ss.park.Reset()
+    ss.occupancy.Reset()
+    ss.rejection.Reset()
+    ss.response.Reset()
+}
+
+// runningStats lleva media y varianza exactas de todas las muestras, no solo
+// de las que quedan en los reservorios.
+type runningStats struct {
+    wait     *utils.WelfordOnlineStats
+    park     *utils.WelfordOnlineStats
+    response *utils.WelfordOnlineStats
+}
+
+func newRunningStats() runningStats {
+    return runningStats{
+        wait:     utils.NewWelfordOnlineStats(),
+        park:     utils.NewWelfordOnlineStats(),
+        response: utils.NewWelfordOnlineStats(),
+    }
+}
+
+func (rs runningStats) reset() {
+    rs.wait.Reset()
+    rs.park.Reset()
+    rs.response.Reset()
+}
+
+func (rs runningStats) forMetric(metric string) (*utils.WelfordOnlineStats, bool) {
+    switch metric {
+    case "avgWait":
+        return rs.wait, true
+    case "avgPark":
+        return rs.park, true
+    case "responseTime":
+        return rs.response, true
+    }
+    return nil, false
+}
+
+// GetParkTimeStdDev devuelve la desviación estándar de la estancia, en segundos.
+func (s *Simulation) GetParkTimeStdDev() float64 {
+    return s.moments.park.StdDev()
+}
+
+// GetWaitTimeStdDev devuelve la desviación estándar de la espera, en segundos.
+func (s *Simulation) GetWaitTimeStdDev() float64 {
+    return s.moments.wait.StdDev()
+}
+
+func (s *Simulation) GetSystemResponseTimeStdDev() float64 {
+    return s.moments.response.StdDev()
+}
+
+func (ss simulationSamples) forMetric(metric string) (*utils.Reservoir, bool) {
+    switch metric {
+    case "avgWait":
+        return ss.wait, true
+    case "avgPark":
+        return ss.park, true
+    case "occupancyRate":
+        return ss.occupancy, true
+    case "rejectionRate":
+        return ss.rejection, true
+    case "responseTime":
+        return ss.response, true
+    }
+    return nil, false
+}
+
+// GetConfidenceInterval calcula el intervalo de confianza de la media de una
+// métrica usando la t de Student. Las esperas, estancias y tiempos en el
+// sistema usan la media y varianza exactas; las demás métricas, las muestras
+// del reservorio. Los tiempos se devuelven en segundos y las tasas como fracción. Con menos de dos
+// muestras, o una métrica desconocida, devuelve (0, 0).
+func (s *Simulation) GetConfidenceInterval(metric string, confidence float64) (lower, upper float64) {
+    var n int
+    var mean, stdDev float64
+    if stats, ok := s.moments.forMetric(metric); ok {
+        n = int(stats.Count())
+        mean = stats.Mean()
+        stdDev = stats.StdDev()
+    } else if reservoir, ok := s.samples.forMetric(metric); ok {
+        samples := reservoir.Samples()
+        n = len(samples)
+        mean = utils.Mean(samples)
+        stdDev = utils.StdDev(samples)
+    } else {
+        return 0, 0
+    }
+    if n < 2 {
+        return 0, 0
+    }
+
+    t := utils.TDistQuantile(n-1, 1-confidence)
+    halfWidth := t * stdDev / math.Sqrt(float64(n))
+    return mean - halfWidth, mean + halfWidth
+}
+
+// GetSystemResponseTime devuelve el tiempo medio en el sistema (espera más
+// estancia) de los vehículos que ya salieron. Es la W de la ley de Little.
+func (s *Simulation) GetSystemResponseTime() time.Duration {
+    samples := s.samples.response.Samples()
+    if len(samples) == 0 {
+        return 0
+    }
+    return secondsToDuration(utils.Mean(samples))
+}
+
+// GetSystemResponseTimePercentile devuelve el percentil p (entre 0 y 1) del
+// tiempo en el sistema.
+func (s *Simulation) GetSystemResponseTimePercentile(p float64) time.Duration {
+    return secondsToDuration(utils.Percentile(s.samples.response.Samples(), p))
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+    return time.Duration(seconds * float64(time.Second))
+}
+
+// GetServerUtilization calcula ρ = λ/(cμ) con la λ observada desde el último
+// reinicio y μ = 1/estancia media. Sin observaciones usa la configuración.
+// Un valor mayor o igual a 1 indica que el sistema está sobrecargado. Con
+// capacidad 0 la utilización no está definida y se devuelve NaN.
+func (s *Simulation) GetServerUtilization() float64 {
+    config := s.GetConfig()
+    if config.ParkingCapacity == 0 {
+        return math.NaN()
+    }
+
+    lambda, avgPark := s.observedRates()
+    if avgPark <= 0 {
+        return 0
+    }
+
+    mu := 1 / avgPark
+    return lambda / (float64(config.ParkingCapacity) * mu)
+}
+
+// observedRates devuelve la tasa de llegadas y la estancia media (en
+// segundos) observadas, o las de la configuración si aún no hay datos.
+func (s *Simulation) observedRates() (lambda, avgPark float64) {
+    config := s.GetConfig()
+    lambda = config.ArrivalRate
+    elapsed := time.Since(s.GetStatisticsSince()).Seconds()
+    if arrivals := s.GetMetrics().TotalArrivals; arrivals > 0 && elapsed > 0 {
+        lambda = float64(arrivals) / elapsed
+    }
+
+    avgPark = (config.MinParkTime + config.MaxParkTime) / 2
+    if samples := s.samples.park.Samples(); len(samples) > 0 {
+        avgPark = utils.Mean(samples)
+    }
+    return lambda, avgPark
+}
+
+func (s *Simulation) IsStable() bool {
+    return s.GetServerUtilization() < 1.0
+}
+
+// GetEfficiencyScore combina en un solo valor entre 0 y 1 la proporción de
+// llegadas que entraron, la ocupación media, la tasa de rechazos y el índice
+// de equidad. Si falta alguno de los componentes devuelve 0.
+func (s *Simulation) GetEfficiencyScore() float64 {
+    metrics := s.GetMetrics()
+    occupancy := s.samples.occupancy.Samples()
+    rejection := s.samples.rejection.Samples()
+    if metrics.TotalArrivals == 0 || len(occupancy) == 0 || len(rejection) == 0 {
+        return 0
+    }
+
+    admitted := math.Min(1, float64(metrics.TotalEntered)/float64(metrics.TotalArrivals))
+    return admitted * utils.Mean(occupancy) * (1 - utils.Mean(rejection)) * s.GetFairnessIndex()
+}
+
+func (s *Simulation) GetEfficiencyGrade() string {
+    score := s.GetEfficiencyScore()
+    switch {
+    case score >= GRADE_A_PLUS:
+        return "A+"
+    case score >= GRADE_A:
+        return "A"
+    case score >= GRADE_B:
+        return "B"
+    case score >= GRADE_C:
+        return "C"
+    }
+    return "D"
+}
+
+// GetSpaceTurnoverRates devuelve, por espacio, los vehículos atendidos por
+// hora abierta desde el último reinicio de estadísticas.
+func (s *Simulation) GetSpaceTurnoverRates() []float64 {
+    return s.parking.GetSpaceTurnoverRates(s.GetStatisticsSince(), time.Now())
+}
+
+// GetTurnoverRate es la rotación del estacionamiento: vehículos atendidos por
+// espacio y por hora. Sin espacios devuelve 0.
+func (s *Simulation) GetTurnoverRate() float64 {
+    rates := s.GetSpaceTurnoverRates()
+    if len(rates) == 0 {
+        return 0
+    }
+    return utils.Mean(rates)
+}
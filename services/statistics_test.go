@@ -0,0 +1,238 @@
+package services
+
+import (
+    "math"
+    "math/rand"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestConfidenceIntervalCoversExponentialMean(t *testing.T) {
+    const (
+        repetitions = 400
+        samples     = 200
+    )
+    tests := []struct {
+        name       string
+        metric     string
+        mean       float64
+        confidence float64
+        feed       func(sim *Simulation, x float64)
+    }{
+        {"espera exacta al 95%", "avgWait", 3, 0.95, func(sim *Simulation, x float64) { sim.moments.wait.Update(x) }},
+        {"estancia exacta al 90%", "avgPark", 40, 0.90, func(sim *Simulation, x float64) { sim.moments.park.Update(x) }},
+        {"ocupación del reservorio al 95%", "occupancyRate", 0.5, 0.95, func(sim *Simulation, x float64) { sim.samples.occupancy.Add(x) }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            rng := rand.New(rand.NewSource(1))
+            sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+            covered := 0
+            for r := 0; r < repetitions; r++ {
+                sim.resetStatistics()
+                for i := 0; i < samples; i++ {
+                    tt.feed(sim, rng.ExpFloat64()*tt.mean)
+                }
+                lower, upper := sim.GetConfidenceInterval(tt.metric, tt.confidence)
+                if lower <= tt.mean && tt.mean <= upper {
+                    covered++
+                }
+            }
+            // La cobertura de una exponencial con n = 200 queda un poco por
+            // debajo de la nominal; se admite un margen de cinco puntos.
+            if coverage := float64(covered) / repetitions; coverage < tt.confidence-0.05 {
+                t.Errorf("cobertura = %.3f, want al menos %.3f", coverage, tt.confidence-0.05)
+            }
+        })
+    }
+}
+
+func TestConfidenceIntervalWithoutSamples(t *testing.T) {
+    tests := []struct {
+        name    string
+        metric  string
+        samples []float64
+    }{
+        {"métrica desconocida", "desconocida", []float64{1, 2, 3}},
+        {"sin muestras", "avgWait", nil},
+        {"una sola muestra", "avgWait", []float64{4}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+            for _, x := range tt.samples {
+                sim.moments.wait.Update(x)
+            }
+            if lower, upper := sim.GetConfidenceInterval(tt.metric, 0.95); lower != 0 || upper != 0 {
+                t.Errorf("GetConfidenceInterval = (%v, %v), want (0, 0)", lower, upper)
+            }
+        })
+    }
+}
+
+func TestGetServerUtilization(t *testing.T) {
+    tests := []struct {
+        name       string
+        lambda     float64
+        capacity   int
+        park       float64
+        observed   bool
+        want       float64
+        wantStable bool
+    }{
+        // λ = 10 con c = 5 y μ = 1/5 da ρ = 10/(5·0.2) = 10; el límite ρ = 1
+        // con esa c y esa μ es λ = 1.
+        {"λ = 10, c = 5, μ = 1/5", 10, 5, 5, false, 10, false},
+        {"en el límite", 1, 5, 5, false, 1, false},
+        {"por debajo del límite", 0.5, 5, 5, false, 0.5, true},
+        {"observado estable", 0.8, 5, 5, true, 0.8, true},
+        {"observado sobrecargado", 10, 5, 5, true, 10, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.ArrivalRate = tt.lambda
+            config.ParkingCapacity = tt.capacity
+            config.MinParkTime, config.MaxParkTime = tt.park, tt.park
+            if tt.observed {
+                // La configuración dice otra cosa: deben mandar las
+                // observaciones.
+                config.ArrivalRate = 3 * tt.lambda
+                config.MinParkTime, config.MaxParkTime = 1, 1
+            }
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if tt.observed {
+                const elapsed = 100 * time.Second
+                sim.statsSince = time.Now().Add(-elapsed)
+                atomic.StoreInt64(&sim.metrics.TotalArrivals, int64(tt.lambda*elapsed.Seconds()))
+                for i := 0; i < 10; i++ {
+                    sim.samples.park.Add(tt.park)
+                }
+            }
+
+            if got := sim.GetServerUtilization(); math.Abs(got-tt.want) > 0.01*tt.want {
+                t.Errorf("GetServerUtilization = %.4f, want %.4f", got, tt.want)
+            }
+            if stable := sim.IsStable(); stable != tt.wantStable {
+                t.Errorf("IsStable = %v, want %v", stable, tt.wantStable)
+            }
+        })
+    }
+}
+
+func TestEdgeCapacities(t *testing.T) {
+    tests := []struct {
+        name     string
+        capacity int
+    }{
+        {"solo cola", 0},
+        {"un espacio", 1},
+        {"dos espacios", 2},
+        {"veinte espacios", 20},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := drainConfig(0.05, 0.1)
+            config.ParkingCapacity = tt.capacity
+            if err := config.Validate(); err != nil {
+                t.Fatalf("Validate() = %v", err)
+            }
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if err := sim.SetArrivalSource(&burstArrivals{n: 8}); err != nil {
+                t.Fatal(err)
+            }
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            if !waitForCounter(&sim.metrics.TotalArrivals, 8) {
+                t.Fatalf("llegadas = %d, want 8", sim.GetMetrics().TotalArrivals)
+            }
+            time.Sleep(300 * time.Millisecond)
+            sim.Stop()
+
+            if n := len(sim.GetSpaces()); n != tt.capacity {
+                t.Errorf("espacios = %d, want %d", n, tt.capacity)
+            }
+            if occupancy := sim.GetOccupancy(); occupancy > tt.capacity {
+                t.Errorf("ocupación = %d, mayor que la capacidad %d", occupancy, tt.capacity)
+            }
+            if errs := sim.ValidateParking(); len(errs) != 0 {
+                t.Errorf("ValidateParking() = %v", errs)
+            }
+            metrics := sim.GetMetrics()
+            if tt.capacity == 0 && metrics.TotalEntered != 0 {
+                t.Errorf("entradas = %d sin espacios, want 0", metrics.TotalEntered)
+            }
+            if tt.capacity > 0 && metrics.TotalEntered == 0 {
+                t.Error("no entró ningún vehículo")
+            }
+
+            rho := sim.GetServerUtilization()
+            if math.IsNaN(rho) != (tt.capacity == 0) {
+                t.Errorf("GetServerUtilization = %v, want NaN solo sin espacios", rho)
+            }
+            rates := map[string]float64{
+                "eficiencia":      sim.GetEfficiencyScore(),
+                "rotación":        sim.GetTurnoverRate(),
+                "espacios libres": sim.GetTimeWeightedFreeSpaces(),
+                "equidad":         sim.GetFairnessIndex(),
+            }
+            for name, rate := range rates {
+                if math.IsNaN(rate) || math.IsInf(rate, 0) {
+                    t.Errorf("%s = %v, want un número finito", name, rate)
+                }
+            }
+        })
+    }
+}
+
+func TestGetEfficiencyScore(t *testing.T) {
+    tests := []struct {
+        name      string
+        arrivals  int64
+        entered   int64
+        occupancy []float64
+        rejection []float64
+        waits     []float64
+        want      float64
+        wantGrade string
+    }{
+        // 1 · 1 · 1 · 1
+        {"perfecto", 10, 10, []float64{1, 1}, []float64{0, 0}, []float64{2, 2}, 1, "A+"},
+        // 0.8 · 0.75 · (1 - 0.25) · 1 = 0.45
+        {"componentes conocidos", 10, 8, []float64{0.5, 1}, []float64{0, 0, 0, 1}, []float64{3, 3, 3}, 0.45, "C"},
+        // 1 · 1 · 1 · (1+3)²/(2·(1+9)) = 0.8
+        {"esperas desiguales", 4, 4, []float64{1}, []float64{0}, []float64{1, 3}, 0.8, "A"},
+        // Los reintentos pueden hacer que entren más de los que llegaron.
+        {"admitidos acotados a 1", 5, 6, []float64{0.8}, []float64{0}, []float64{1}, 0.8, "A"},
+        {"todo rechazado", 5, 0, []float64{0}, []float64{1}, nil, 0, "D"},
+        {"sin llegadas", 0, 0, []float64{1}, []float64{0}, []float64{1}, 0, "D"},
+        {"sin ocupación", 5, 5, nil, []float64{0}, []float64{1}, 0, "D"},
+        {"sin rechazos medidos", 5, 5, []float64{1}, nil, []float64{1}, 0, "D"},
+        {"sin esperas", 5, 5, []float64{1}, []float64{0}, nil, 0, "D"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+            sim.metrics.TotalArrivals = tt.arrivals
+            sim.metrics.TotalEntered = tt.entered
+            for _, x := range tt.occupancy {
+                sim.samples.occupancy.Add(x)
+            }
+            for _, x := range tt.rejection {
+                sim.samples.rejection.Add(x)
+            }
+            for _, x := range tt.waits {
+                sim.samples.wait.Add(x)
+            }
+
+            if got := sim.GetEfficiencyScore(); math.Abs(got-tt.want) > 1e-9 {
+                t.Errorf("GetEfficiencyScore = %v, want %v", got, tt.want)
+            }
+            if grade := sim.GetEfficiencyGrade(); grade != tt.wantGrade {
+                t.Errorf("GetEfficiencyGrade = %q, want %q", grade, tt.wantGrade)
+            }
+        })
+    }
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+    "sync/atomic"
+    "time"
+    "holafyne/models"
+)
+
+const (
+    MIN_STAY_EXTEND = "extend"
+    MIN_STAY_BILL   = "bill"
+)
+
+// sampleStay devuelve la estancia que el vehículo pretendía, la que
+// realmente cumple y si el mínimo configurado se aplicó.
+//
+// Con MIN_STAY_EXTEND el vehículo se queda hasta completar el mínimo. Con
+// MIN_STAY_BILL se va cuando pensaba irse, pero se le cobra el mínimo.
+func (s *Simulation) sampleStay() (intended, effective time.Duration, floored bool) {
+    intended = s.generateParkingTime()
+    minimum := time.Duration(s.config.MinStay * float64(time.Second))
+    if minimum <= 0 || intended >= minimum {
+        return intended, intended, false
+    }
+    if s.config.MinStayPolicy == MIN_STAY_BILL {
+        return intended, intended, true
+    }
+    return intended, minimum, true
+}
+
+func (s *Simulation) applyStay(vehicle *models.Vehicle) time.Duration {
+    intended, effective, floored := s.sampleStay()
+    vehicle.IntendedStay = intended
+    vehicle.BilledStay = effective
+    if floored {
+        atomic.AddInt64(&s.metrics.TotalStayFloors, 1)
+        vehicle.BilledStay = time.Duration(s.config.MinStay * float64(time.Second))
+    }
+    exitAt := time.Now().Add(s.toRealTime(effective))
+    vehicle.SetExpectedExitTime(exitAt)
+    s.planDeparture(exitAt)
+    return effective
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+func TestApplyStayMinimumPolicies(t *testing.T) {
+    second := time.Second
+    tests := []struct {
+        name          string
+        policy        string
+        stay          float64
+        minStay       float64
+        wantEffective time.Duration
+        wantBilled    time.Duration
+        wantFloored   bool
+    }{
+        {"extender por debajo del mínimo", MIN_STAY_EXTEND, 4, 5, 5 * second, 5 * second, true},
+        {"extender justo en el mínimo", MIN_STAY_EXTEND, 5, 5, 5 * second, 5 * second, false},
+        {"extender por encima del mínimo", MIN_STAY_EXTEND, 6, 5, 6 * second, 6 * second, false},
+        {"cobrar por debajo del mínimo", MIN_STAY_BILL, 4, 5, 4 * second, 5 * second, true},
+        {"cobrar justo en el mínimo", MIN_STAY_BILL, 5, 5, 5 * second, 5 * second, false},
+        {"cobrar por encima del mínimo", MIN_STAY_BILL, 6, 5, 6 * second, 6 * second, false},
+        {"política por defecto extiende", "", 4, 5, 5 * second, 5 * second, true},
+        {"sin mínimo", MIN_STAY_EXTEND, 4, 0, 4 * second, 4 * second, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.MinParkTime, config.MaxParkTime = tt.stay, tt.stay
+            config.MinStay = tt.minStay
+            config.MinStayPolicy = tt.policy
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+
+            vehicle := models.NewVehicle(1)
+            effective := sim.applyStay(vehicle)
+
+            if want := time.Duration(tt.stay * float64(time.Second)); vehicle.IntendedStay != want {
+                t.Errorf("estancia pretendida = %v, want %v", vehicle.IntendedStay, want)
+            }
+            if effective != tt.wantEffective {
+                t.Errorf("estancia efectiva = %v, want %v", effective, tt.wantEffective)
+            }
+            if vehicle.BilledStay != tt.wantBilled {
+                t.Errorf("estancia cobrada = %v, want %v", vehicle.BilledStay, tt.wantBilled)
+            }
+            if floors := sim.GetMetrics().TotalStayFloors; (floors == 1) != tt.wantFloored {
+                t.Errorf("veces que se aplicó el mínimo = %d, want aplicado %v", floors, tt.wantFloored)
+            }
+        })
+    }
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+    "fmt"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+)
+
+// stopTrace guarda los mensajes del estacionamiento junto con la fase de la
+// simulación en que llegaron.
+type stopTrace struct {
+    mu     sync.Mutex
+    sim    *Simulation
+    events []string
+}
+
+func (t *stopTrace) record(spaces int, message string) {
+    phase := t.sim.GetPhase()
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    t.events = append(t.events, fmt.Sprintf("%s|%s", phase, message))
+}
+
+func (t *stopTrace) admissionsAfterStop() []string {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    var late []string
+    for _, event := range t.events {
+        phase, message, _ := strings.Cut(event, "|")
+        stopped := phase == PhaseStoppingDepartures.String() || phase == PhaseStopped.String()
+        if stopped && strings.Contains(message, "ha entrado") {
+            late = append(late, event)
+        }
+    }
+    return late
+}
+
+func TestStopNeverAdmitsAfterFreezing(t *testing.T) {
+    tests := []struct {
+        name  string
+        retry bool
+    }{
+        {"sin reintentos", false},
+        {"con reintentos", true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            for i := 0; i < 20; i++ {
+                config := DefaultConfig()
+                config.ParkingCapacity = 3
+                config.MaxQueueSize = 2
+                config.ArrivalRate = 200
+                config.MaxVehicles = 1000
+                config.MinParkTime, config.MaxParkTime = 0.01, 0.05
+                config.RetryOnFullQueue = tt.retry
+                config.MaxRetries = 3
+                config.RetryBackoff = 5 * time.Millisecond
+
+                trace := &stopTrace{}
+                sim := NewSimulationWithConfig(config, trace.record)
+                trace.sim = sim
+                if err := sim.Start(); err != nil {
+                    t.Fatal(err)
+                }
+                time.Sleep(time.Duration(20+i*5) * time.Millisecond)
+                sim.Stop()
+                entered := sim.GetMetrics().TotalEntered
+
+                time.Sleep(20 * time.Millisecond)
+                if late := trace.admissionsAfterStop(); len(late) > 0 {
+                    t.Fatalf("corrida %d: entradas después de congelar la cola: %v", i, late)
+                }
+                if after := sim.GetMetrics().TotalEntered; after != entered {
+                    t.Fatalf("corrida %d: TotalEntered pasó de %d a %d después de Stop", i, entered, after)
+                }
+                if entered == 0 {
+                    t.Fatalf("corrida %d: no entró ningún vehículo antes de Stop", i)
+                }
+            }
+        })
+    }
+}
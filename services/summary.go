@@ -0,0 +1,85 @@
+package services
+
+import (
+    "fmt"
+    "strings"
+    "unicode/utf8"
+    "holafyne/utils"
+)
+
+// SummaryRow es una fila del resumen de la corrida, ya formateada.
+type SummaryRow struct {
+    Label string
+    Value string
+}
+
+// GetRunSummary reúne los indicadores principales de la corrida en el orden
+// en que se muestran.
+func (s *Simulation) GetRunSummary() []SummaryRow {
+    metrics := s.GetMetrics()
+    occupancy := 0.0
+    if samples := s.samples.occupancy.Samples(); len(samples) > 0 {
+        occupancy = utils.Mean(samples)
+    }
+    rows := []SummaryRow{}
+    if s.GetConfig().NoShowProbability > 0 {
+        rows = append(rows,
+            SummaryRow{"Demanda en la calle", fmt.Sprintf("%d", metrics.TotalArrivals+metrics.TotalNoShows)},
+            SummaryRow{"No se presentaron", fmt.Sprintf("%d", metrics.TotalNoShows)},
+        )
+    }
+    rows = append(rows, []SummaryRow{
+        {"Llegadas", fmt.Sprintf("%d", metrics.TotalArrivals)},
+        {"Entraron", fmt.Sprintf("%d", metrics.TotalEntered)},
+        {"Rechazados", fmt.Sprintf("%d", metrics.TotalRejected)},
+    }...)
+    if s.GetConfig().SiteCapacity > 0 {
+        rows = append(rows, SummaryRow{"Sitio lleno", fmt.Sprintf("%d", metrics.SiteFull)})
+    }
+    if metrics.TotalPreexisting > 0 {
+        rows = append(rows, SummaryRow{"Preexistentes", fmt.Sprintf("%d", metrics.TotalPreexisting)})
+    }
+    return append(rows, []SummaryRow{
+        {"Abandonos", fmt.Sprintf("%d", metrics.TotalAbandoned)},
+        {"Cancelados", fmt.Sprintf("%d", metrics.TotalCancelled)},
+        {"Espera media", fmt.Sprintf("%.1f s", s.moments.wait.Mean())},
+        {"Espera máxima", fmt.Sprintf("%.1f s", s.GetWorstWait().Seconds())},
+        {"Estancia media", fmt.Sprintf("%.1f s", s.moments.park.Mean())},
+        {"Tiempo en el sistema", fmt.Sprintf("%.1f s", s.GetSystemResponseTime().Seconds())},
+        {"Ocupación media", fmt.Sprintf("%.0f%%", occupancy*100)},
+        {"Rotación", fmt.Sprintf("%.1f veh/espacio/h", s.GetTurnoverRate())},
+        {"Eficiencia", fmt.Sprintf("%.0f%% (%s)", s.GetEfficiencyScore()*100, s.GetEfficiencyGrade())},
+    }...)
+}
+
+// FormatSummaryTable alinea las filas como texto plano o, si markdown es
+// verdadero, como una tabla de Markdown.
+func FormatSummaryTable(rows []SummaryRow, markdown bool) string {
+    labelWidth, valueWidth := utf8.RuneCountInString("Indicador"), utf8.RuneCountInString("Valor")
+    for _, row := range rows {
+        labelWidth = max(labelWidth, utf8.RuneCountInString(row.Label))
+        valueWidth = max(valueWidth, utf8.RuneCountInString(row.Value))
+    }
+
+    var b strings.Builder
+    line := func(label, value string) {
+        labelPad := strings.Repeat(" ", labelWidth-utf8.RuneCountInString(label))
+        valuePad := strings.Repeat(" ", valueWidth-utf8.RuneCountInString(value))
+        if markdown {
+            fmt.Fprintf(&b, "| %s%s | %s%s |\n", label, labelPad, valuePad, value)
+        } else {
+            fmt.Fprintf(&b, "%s%s  %s%s\n", label, labelPad, valuePad, value)
+        }
+    }
+
+    line("Indicador", "Valor")
+    if markdown {
+        fmt.Fprintf(&b, "|%s|%s:|\n", strings.Repeat("-", labelWidth+2), strings.Repeat("-", valueWidth+1))
+    } else {
+        fmt.Fprintf(&b, "%s  %s\n", strings.Repeat("-", labelWidth), strings.Repeat("-", valueWidth))
+    }
+    for _, row := range rows {
+        line(row.Label, row.Value)
+    }
+    return b.String()
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestFormatSummaryTable(t *testing.T) {
+    rows := []SummaryRow{
+        {"Llegadas", "12"},
+        {"Espera máxima", "12.5 s"},
+        {"Ocupación media", "75%"},
+    }
+    tests := []struct {
+        name     string
+        markdown bool
+        want     string
+    }{
+        {"texto plano", false, "" +
+            "Indicador         Valor\n" +
+            "---------------  ------\n" +
+            "Llegadas             12\n" +
+            "Espera máxima    12.5 s\n" +
+            "Ocupación media     75%\n"},
+        {"markdown", true, "" +
+            "| Indicador       |  Valor |\n" +
+            "|-----------------|-------:|\n" +
+            "| Llegadas        |     12 |\n" +
+            "| Espera máxima   | 12.5 s |\n" +
+            "| Ocupación media |    75% |\n"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := FormatSummaryTable(rows, tt.markdown); got != tt.want {
+                t.Errorf("FormatSummaryTable:\n%s\nwant:\n%s", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestGetRunSummaryOptionalRows(t *testing.T) {
+    tests := []struct {
+        name        string
+        noShow      float64
+        site        int
+        preexisting int64
+        want        []string
+    }{
+        {"sin extras", 0, 0, 0, nil},
+        {"con no presentados", 0.2, 0, 0, []string{"Demanda en la calle", "No se presentaron"}},
+        {"con sitio", 0, 10, 0, []string{"Sitio lleno"}},
+        {"con preexistentes", 0, 0, 3, []string{"Preexistentes"}},
+    }
+    base := []string{"Llegadas", "Entraron", "Rechazados", "Abandonos", "Cancelados", "Espera media", "Espera máxima",
+        "Estancia media", "Tiempo en el sistema", "Ocupación media", "Rotación", "Eficiencia"}
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.NoShowProbability = tt.noShow
+            config.SiteCapacity = tt.site
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            sim.metrics.TotalArrivals = 12
+            sim.metrics.TotalEntered = 9
+            sim.metrics.TotalRejected = 3
+            sim.metrics.TotalPreexisting = tt.preexisting
+
+            var labels []string
+            values := map[string]string{}
+            for _, row := range sim.GetRunSummary() {
+                labels = append(labels, row.Label)
+                values[row.Label] = row.Value
+            }
+            for _, label := range tt.want {
+                if _, ok := values[label]; !ok {
+                    t.Errorf("falta la fila %q en %v", label, labels)
+                }
+            }
+            var core []string
+            for _, label := range labels {
+                if !containsLabel(tt.want, label) {
+                    core = append(core, label)
+                }
+            }
+            if !reflect.DeepEqual(core, base) {
+                t.Errorf("filas = %v, want %v", core, base)
+            }
+            if values["Llegadas"] != "12" || values["Entraron"] != "9" || values["Rechazados"] != "3" {
+                t.Errorf("contadores = %v", values)
+            }
+        })
+    }
+}
+
+func containsLabel(list []string, s string) bool {
+    for _, item := range list {
+        if item == s {
+            return true
+        }
+    }
+    return false
+}
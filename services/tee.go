@@ -0,0 +1,168 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "time"
+    "holafyne/models"
+)
+
+// TEE_BUFFER es cuántas llegadas puede adelantarse el primer consumidor de
+// un TeeArrivalSource respecto a los demás.
+const TEE_BUFFER = 1024
+
+// ArrivalRecord es una llegada vista por un consumidor de TeeArrivalSource.
+// At es cuándo la entregó la fuente original, igual para todos los
+// consumidores.
+type ArrivalRecord struct {
+    VehicleID int
+    At        time.Duration
+}
+
+// TeeArrivalSource reparte una misma secuencia de llegadas entre varias
+// simulaciones: el primer consumidor lee de la fuente original, con sus
+// tiempos, y reenvía cada llegada a los demás, que reciben un vehículo
+// nuevo con el mismo ID y quedan registrados con el tiempo de la llegada
+// original. Así se compara el mismo flujo en configuraciones distintas. No sirve con poblaciones cerradas, cuyo flujo depende de las
+// salidas de cada simulación.
+type TeeArrivalSource struct {
+    source    ArrivalSource
+    followers []chan teeArrival
+    startedAt time.Time
+    startOnce sync.Once
+    closeOnce sync.Once
+    records   [][]ArrivalRecord
+    mu        sync.Mutex
+}
+
+type teeArrival struct {
+    vehicle *models.Vehicle
+    at      time.Duration
+}
+
+type teeConsumer struct {
+    tee   *TeeArrivalSource
+    index int
+}
+
+// NewTeeArrivalSource devuelve n fuentes que producen la misma secuencia que
+// source. La primera marca el ritmo.
+func NewTeeArrivalSource(source ArrivalSource, n int) []ArrivalSource {
+    tee := &TeeArrivalSource{source: source, records: make([][]ArrivalRecord, n)}
+    consumers := make([]ArrivalSource, n)
+    for i := range consumers {
+        if i > 0 {
+            tee.followers = append(tee.followers, make(chan teeArrival, TEE_BUFFER))
+        }
+        consumers[i] = &teeConsumer{tee: tee, index: i}
+    }
+    return consumers
+}
+
+func (c *teeConsumer) Next(ctx context.Context) (*models.Vehicle, bool) {
+    tee := c.tee
+    tee.startOnce.Do(func() {
+        tee.mu.Lock()
+        tee.startedAt = time.Now()
+        tee.mu.Unlock()
+    })
+
+    if c.index > 0 {
+        select {
+        case <-ctx.Done():
+            return nil, false
+        case arrival, ok := <-tee.followers[c.index-1]:
+            if !ok {
+                return nil, false
+            }
+            tee.record(c.index, arrival)
+            return arrival.vehicle, true
+        }
+    }
+
+    vehicle, ok := tee.source.Next(ctx)
+    if !ok {
+        tee.closeOnce.Do(func() {
+            for _, follower := range tee.followers {
+                close(follower)
+            }
+        })
+        return nil, false
+    }
+    tee.mu.Lock()
+    at := time.Since(tee.startedAt)
+    tee.mu.Unlock()
+    tee.record(0, teeArrival{vehicle: vehicle, at: at})
+    for _, follower := range tee.followers {
+        clone := models.NewVehicle(vehicle.ID)
+        clone.Visit = vehicle.Visit
+        select {
+        case follower <- teeArrival{vehicle: clone, at: at}:
+        case <-ctx.Done():
+            return vehicle, true
+        }
+    }
+    return vehicle, true
+}
+
+func (tee *TeeArrivalSource) record(index int, arrival teeArrival) {
+    tee.mu.Lock()
+    defer tee.mu.Unlock()
+    tee.records[index] = append(tee.records[index], ArrivalRecord{VehicleID: arrival.vehicle.ID, At: arrival.at})
+}
+
+// GetTeeArrivalRecords devuelve las llegadas que vio consumer, para comprobar
+// que todos los consumidores recibieron la misma secuencia. Si consumer no
+// viene de NewTeeArrivalSource devuelve nil.
+func GetTeeArrivalRecords(consumer ArrivalSource) []ArrivalRecord {
+    c, ok := consumer.(*teeConsumer)
+    if !ok {
+        return nil
+    }
+    c.tee.mu.Lock()
+    defer c.tee.mu.Unlock()
+    return append([]ArrivalRecord(nil), c.tee.records[c.index]...)
+}
+
+// ShareArrivals hace que todas las simulaciones reciban las llegadas de la
+// primera. También les da la semilla de no presentados de la primera: como
+// cada una consulta su filtro una vez por llegada y en el mismo orden, con la
+// misma probabilidad descartan las mismas llegadas. Debe llamarse antes de
+// Start.
+func ShareArrivals(sims ...*Simulation) error {
+    if len(sims) == 0 {
+        return nil
+    }
+    for _, sim := range sims {
+        if sim.GetConfig().ClosedPopulation > 0 {
+            return errors.New("no se pueden compartir las llegadas de una población cerrada")
+        }
+    }
+    sims[0].stateMutex.Lock()
+    source := sims[0].arrivals
+    sims[0].stateMutex.Unlock()
+
+    for i, consumer := range NewTeeArrivalSource(source, len(sims)) {
+        if err := sims[i].SetArrivalSource(consumer); err != nil {
+            return err
+        }
+    }
+    first := sims[0].noShows
+    first.mu.Lock()
+    seed := first.seed
+    first.mu.Unlock()
+    for _, sim := range sims[1:] {
+        sim.noShows.reseed(seed)
+    }
+    return nil
+}
+
+// GetArrivalRecords devuelve las llegadas que recibió la simulación si
+// comparte su flujo con ShareArrivals.
+func (s *Simulation) GetArrivalRecords() []ArrivalRecord {
+    s.stateMutex.Lock()
+    source := s.arrivals
+    s.stateMutex.Unlock()
+    return GetTeeArrivalRecords(source)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+    "context"
+    "reflect"
+    "sync"
+    "testing"
+    "time"
+)
+
+func TestTeeConsumersObserveIdenticalArrivals(t *testing.T) {
+    tests := []struct {
+        name      string
+        consumers int
+        arrivals  int
+        // lag es cuánto tarda cada seguidor en pedir la siguiente llegada.
+        lag time.Duration
+    }{
+        {"dos consumidores", 2, 20, 0},
+        {"tres consumidores", 3, 20, 0},
+        {"seguidor lento", 2, 10, 5 * time.Millisecond},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+            defer cancel()
+            consumers := NewTeeArrivalSource(&tracedArrivals{n: tt.arrivals, gap: time.Millisecond}, tt.consumers)
+
+            ids := make([][]int, tt.consumers)
+            var wg sync.WaitGroup
+            for i, consumer := range consumers {
+                wg.Add(1)
+                go func(i int, consumer ArrivalSource) {
+                    defer wg.Done()
+                    for len(ids[i]) < tt.arrivals {
+                        if i > 0 {
+                            time.Sleep(tt.lag)
+                        }
+                        vehicle, ok := consumer.Next(ctx)
+                        if !ok {
+                            return
+                        }
+                        ids[i] = append(ids[i], vehicle.ID)
+                    }
+                }(i, consumer)
+            }
+            wg.Wait()
+
+            leader := GetTeeArrivalRecords(consumers[0])
+            if len(leader) != tt.arrivals {
+                t.Fatalf("el primer consumidor vio %d llegadas, want %d", len(leader), tt.arrivals)
+            }
+            for i := 1; i < len(leader); i++ {
+                if leader[i].At < leader[i-1].At {
+                    t.Fatalf("tiempos fuera de orden: %v", leader)
+                }
+            }
+            for i, consumer := range consumers[1:] {
+                if got := GetTeeArrivalRecords(consumer); !reflect.DeepEqual(got, leader) {
+                    t.Errorf("consumidor %d vio %v, want %v", i+1, got, leader)
+                }
+                if !reflect.DeepEqual(ids[i+1], ids[0]) {
+                    t.Errorf("consumidor %d recibió los vehículos %v, want %v", i+1, ids[i+1], ids[0])
+                }
+            }
+        })
+    }
+}
+
+func TestShareArrivals(t *testing.T) {
+    t.Run("las dos simulaciones reciben el mismo flujo", func(t *testing.T) {
+        first := NewSimulationWithConfig(drainConfig(50, 100), func(int, string) {})
+        second := NewSimulationWithConfig(drainConfig(200, 300), func(int, string) {})
+        if err := first.SetArrivalSource(&tracedArrivals{n: 8, gap: 5 * time.Millisecond}); err != nil {
+            t.Fatal(err)
+        }
+        if err := ShareArrivals(first, second); err != nil {
+            t.Fatal(err)
+        }
+        for _, sim := range []*Simulation{first, second} {
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+            defer sim.Stop()
+        }
+
+        deadline := time.Now().Add(5 * time.Second)
+        for len(first.GetArrivalRecords()) < 8 || len(second.GetArrivalRecords()) < 8 {
+            if time.Now().After(deadline) {
+                t.Fatalf("llegadas: %d y %d, want 8", len(first.GetArrivalRecords()), len(second.GetArrivalRecords()))
+            }
+            time.Sleep(10 * time.Millisecond)
+        }
+        if a, b := first.GetArrivalRecords(), second.GetArrivalRecords(); !reflect.DeepEqual(a, b) {
+            t.Errorf("llegadas distintas:\n%v\n%v", a, b)
+        }
+    })
+    t.Run("sin flujo compartido no hay registros", func(t *testing.T) {
+        sim := NewSimulationWithConfig(drainConfig(1, 2), func(int, string) {})
+        if records := sim.GetArrivalRecords(); records != nil {
+            t.Errorf("registros = %v, want nil", records)
+        }
+    })
+    t.Run("población cerrada", func(t *testing.T) {
+        config := drainConfig(1, 2)
+        config.ClosedPopulation = 5
+        sim := NewSimulationWithConfig(config, func(int, string) {})
+        if err := ShareArrivals(sim, NewSimulationWithConfig(drainConfig(1, 2), func(int, string) {})); err == nil {
+            t.Error("ShareArrivals aceptó una población cerrada")
+        }
+    })
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+    "math/rand"
+    "reflect"
+    "holafyne/models"
+)
+
+// Los criterios de desempate deciden si a debe quedar antes que b en la cola
+// cuando ambos tienen la misma prioridad. FIFO es el criterio por defecto.
+func FIFOTieBreaker(a, b *models.Vehicle) bool {
+    return a.QueuedAt.Before(b.QueuedAt)
+}
+
+func LIFOTieBreaker(a, b *models.Vehicle) bool {
+    return a.QueuedAt.After(b.QueuedAt)
+}
+
+// RandomTieBreaker compara la clave al azar que recibe cada vehículo al
+// entrar a la cola, así el orden es consistente y cada permutación es
+// igual de probable.
+func RandomTieBreaker(a, b *models.Vehicle) bool {
+    return a.QueueKey < b.QueueKey
+}
+
+// SetTieBreaker cambia el criterio con que se ordenan los vehículos que
+// entran a la cola. Con nil se vuelve a FIFO. Los vehículos que ya están en
+// la cola conservan su lugar.
+func (s *Simulation) SetTieBreaker(fn func(a, b *models.Vehicle) bool) {
+    if fn == nil {
+        fn = FIFOTieBreaker
+    }
+    s.queueMutex.Lock()
+    defer s.queueMutex.Unlock()
+    s.tieBreaker = fn
+}
+
+// StableQueueOrder indica si la cola respeta el orden de llegada.
+func (s *Simulation) StableQueueOrder() bool {
+    s.queueMutex.RLock()
+    defer s.queueMutex.RUnlock()
+    return s.tieBreaker == nil || reflect.ValueOf(s.tieBreaker).Pointer() == reflect.ValueOf(FIFOTieBreaker).Pointer()
+}
+
+// insertQueued le da al vehículo su clave al azar y lo coloca delante del
+// primero que, según el tieBreaker, debe ir después de él. Debe llamarse con
+// queueMutex tomado.
+func (s *Simulation) insertQueued(vehicle *models.Vehicle) {
+    vehicle.QueueKey = rand.Float64()
+    less := s.tieBreaker
+    if less == nil {
+        less = FIFOTieBreaker
+    }
+    index := len(s.queue)
+    for i, queued := range s.queue {
+        if less(vehicle, queued) {
+            index = i
+            break
+        }
+    }
+    s.queue = append(s.queue, nil)
+    copy(s.queue[index+1:], s.queue[index:])
+    s.queue[index] = vehicle
+}
+
+// queuePosition debe llamarse con queueMutex tomado.
+func (s *Simulation) queuePosition(vehicle *models.Vehicle) int {
+    for i, queued := range s.queue {
+        if queued == vehicle {
+            return i
+        }
+    }
+    return -1
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+    "sort"
+    "testing"
+    "time"
+    "holafyne/models"
+)
+
+func queuedIDs(s *Simulation) []int {
+    ids := make([]int, len(s.queue))
+    for i, vehicle := range s.queue {
+        ids[i] = vehicle.ID
+    }
+    return ids
+}
+
+func TestTieBreakerOrdersEqualPriorities(t *testing.T) {
+    base := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+    tests := []struct {
+        name    string
+        breaker func(a, b *models.Vehicle) bool
+        stable  bool
+        want    []int
+    }{
+        {"FIFO por defecto", nil, true, []int{1, 2, 3, 4, 5}},
+        {"FIFO", FIFOTieBreaker, true, []int{1, 2, 3, 4, 5}},
+        {"LIFO", LIFOTieBreaker, false, []int{5, 4, 3, 2, 1}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+            sim.SetTieBreaker(tt.breaker)
+            if got := sim.StableQueueOrder(); got != tt.stable {
+                t.Errorf("StableQueueOrder = %v, want %v", got, tt.stable)
+            }
+            sim.queueMutex.Lock()
+            for i := 1; i <= 5; i++ {
+                vehicle := models.NewVehicle(i)
+                vehicle.QueuedAt = base.Add(time.Duration(i) * time.Second)
+                sim.insertQueued(vehicle)
+            }
+            got := queuedIDs(sim)
+            sim.queueMutex.Unlock()
+            for i := range tt.want {
+                if got[i] != tt.want[i] {
+                    t.Fatalf("cola = %v, want %v", got, tt.want)
+                }
+            }
+        })
+    }
+}
+
+func TestAddToQueueKeepsArrivalOrderWithFIFO(t *testing.T) {
+    config := DefaultConfig()
+    config.MaxQueueSize = 20
+    sim := NewSimulationWithConfig(config, func(int, string) {})
+    for i := 1; i <= 20; i++ {
+        if !sim.addToQueue(models.NewVehicle(i)) {
+            t.Fatalf("no se pudo encolar el vehículo %d", i)
+        }
+    }
+    sim.queueMutex.Lock()
+    got := queuedIDs(sim)
+    sim.queueMutex.Unlock()
+    for i, id := range got {
+        if id != i+1 {
+            t.Fatalf("cola = %v, want orden de llegada", got)
+        }
+    }
+}
+
+func TestRandomTieBreakerIsConsistent(t *testing.T) {
+    sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+    sim.SetTieBreaker(RandomTieBreaker)
+    sim.queueMutex.Lock()
+    defer sim.queueMutex.Unlock()
+    for i := 1; i <= 50; i++ {
+        sim.insertQueued(models.NewVehicle(i))
+    }
+    if !sort.SliceIsSorted(sim.queue, func(i, j int) bool { return sim.queue[i].QueueKey < sim.queue[j].QueueKey }) {
+        t.Error("la cola no está ordenada por la clave al azar")
+    }
+    for i, a := range sim.queue {
+        for _, b := range sim.queue[i+1:] {
+            if RandomTieBreaker(b, a) {
+                t.Fatalf("el vehículo %d quedó después del %d pero va antes", b.ID, a.ID)
+            }
+        }
+    }
+}
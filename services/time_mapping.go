@@ -0,0 +1,89 @@
+package services
+
+import (
+    "fmt"
+    "time"
+)
+
+const CLOCK_DAY = 24 * time.Hour
+
+// TimeMapping traduce el tiempo de la corrida a una hora del día ficticia
+// solo para mostrarla: la corrida empieza a la hora StartClock ("HH:MM") y
+// cada segundo real equivale a Scale segundos simulados. El modelo sigue
+// trabajando con duraciones reales. Con Scale en cero no hay mapeo y los
+// tiempos se muestran en segundos desde el inicio.
+type TimeMapping struct {
+    StartClock string
+    Scale      float64
+}
+
+func (m TimeMapping) IsSet() bool {
+    return m.Scale > 0
+}
+
+func (m TimeMapping) validate() error {
+    if m.Scale < 0 {
+        return fmt.Errorf("la escala del reloj no puede ser negativa")
+    }
+    if !m.IsSet() {
+        return nil
+    }
+    _, err := parseClock(m.StartClock)
+    return err
+}
+
+// Format muestra elapsed como hora del día si hay mapeo y, si no, como
+// segundos desde el inicio.
+func (m TimeMapping) Format(elapsed time.Duration) string {
+    if !m.IsSet() {
+        return fmt.Sprintf("%.1fs", elapsed.Seconds())
+    }
+    start, _ := parseClock(m.StartClock)
+    clock := (start + time.Duration(float64(elapsed)*m.Scale)) % CLOCK_DAY
+    return fmt.Sprintf("%02d:%02d", int(clock.Hours()), int(clock.Minutes())%60)
+}
+
+// ParseClock convierte una hora "HH:MM" en el tiempo real desde el inicio de
+// la corrida en que el reloj ficticio la marca. Las horas anteriores a
+// StartClock se toman del día siguiente. Sin mapeo, "HH:MM" se lee como
+// horas y minutos desde el inicio.
+func (m TimeMapping) ParseClock(text string) (time.Duration, error) {
+    clock, err := parseClock(text)
+    if err != nil {
+        return 0, err
+    }
+    if !m.IsSet() {
+        return clock, nil
+    }
+    start, _ := parseClock(m.StartClock)
+    offset := clock - start
+    if offset < 0 {
+        offset += CLOCK_DAY
+    }
+    return time.Duration(float64(offset) / m.Scale), nil
+}
+
+func parseClock(text string) (time.Duration, error) {
+    var hours, minutes int
+    if _, err := fmt.Sscanf(text, "%d:%d", &hours, &minutes); err != nil || hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+        return 0, fmt.Errorf("hora inválida %q, se espera HH:MM", text)
+    }
+    return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+// FormatElapsed muestra un tiempo de la corrida con el mapeo de la
+// configuración.
+func (s *Simulation) FormatElapsed(elapsed time.Duration) string {
+    return s.GetConfig().TimeMapping.Format(elapsed)
+}
+
+// GetElapsed es el tiempo real desde que arrancó la simulación, o cero si no
+// ha arrancado.
+func (s *Simulation) GetElapsed() time.Duration {
+    s.statsMutex.RLock()
+    defer s.statsMutex.RUnlock()
+    if s.startedAt.IsZero() {
+        return 0
+    }
+    return time.Since(s.startedAt)
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+    "math"
+    "testing"
+    "time"
+    "holafyne/utils"
+)
+
+func TestTimeMappingFormat(t *testing.T) {
+    morning := TimeMapping{StartClock: "08:00", Scale: 60}
+    tests := []struct {
+        name    string
+        mapping TimeMapping
+        elapsed time.Duration
+        want    string
+    }{
+        {"identidad al inicio", TimeMapping{}, 0, "0.0s"},
+        {"identidad en segundos", TimeMapping{}, 1500 * time.Millisecond, "1.5s"},
+        {"inicio del reloj", morning, 0, "08:00"},
+        {"un segundo es un minuto", morning, time.Second, "08:01"},
+        {"una hora simulada", morning, time.Minute, "09:00"},
+        {"pasada la medianoche", morning, 17 * time.Minute, "01:00"},
+        {"un día completo vuelve al inicio", morning, 24 * time.Minute, "08:00"},
+        {"escala fraccionaria", TimeMapping{StartClock: "23:30", Scale: 0.5}, 2 * time.Hour, "00:30"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := tt.mapping.Format(tt.elapsed); got != tt.want {
+                t.Errorf("Format(%v) = %q, want %q", tt.elapsed, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestTimeMappingParseClock(t *testing.T) {
+    morning := TimeMapping{StartClock: "08:00", Scale: 60}
+    tests := []struct {
+        name    string
+        mapping TimeMapping
+        text    string
+        want    time.Duration
+        wantErr bool
+    }{
+        {"identidad: horas y minutos desde el inicio", TimeMapping{}, "01:30", 90 * time.Minute, false},
+        {"a la hora de inicio", morning, "08:00", 0, false},
+        {"una hora después", morning, "09:00", time.Minute, false},
+        {"antes del inicio es del día siguiente", morning, "07:00", 23 * time.Minute, false},
+        {"hora fuera de rango", morning, "24:00", 0, true},
+        {"minutos fuera de rango", morning, "08:60", 0, true},
+        {"sin formato", morning, "ocho", 0, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := tt.mapping.ParseClock(tt.text)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("ParseClock(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+            }
+            if got != tt.want {
+                t.Errorf("ParseClock(%q) = %v, want %v", tt.text, got, tt.want)
+            }
+            if !tt.wantErr && tt.mapping.IsSet() {
+                if back := tt.mapping.Format(got); back != tt.text {
+                    t.Errorf("Format(ParseClock(%q)) = %q", tt.text, back)
+                }
+            }
+        })
+    }
+}
+
+func TestTimeMappingValidation(t *testing.T) {
+    tests := []struct {
+        name      string
+        mapping   TimeMapping
+        dailyPeak string
+        wantErr   bool
+    }{
+        {"sin mapeo", TimeMapping{}, "", false},
+        {"sin mapeo ignora la hora de inicio", TimeMapping{StartClock: "xx"}, "", false},
+        {"mapeo válido", TimeMapping{StartClock: "08:00", Scale: 60}, "09:00", false},
+        {"escala negativa", TimeMapping{StartClock: "08:00", Scale: -1}, "", true},
+        {"hora de inicio inválida", TimeMapping{StartClock: "8", Scale: 60}, "", true},
+        {"pico inválido", TimeMapping{StartClock: "08:00", Scale: 60}, "9h", true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.TimeMapping = tt.mapping
+            config.DailyPeak = tt.dailyPeak
+            if err := config.Validate(); (err != nil) != tt.wantErr {
+                t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+            }
+        })
+    }
+}
+
+func TestDailyPhasePutsPeakAtClock(t *testing.T) {
+    day := 24 * time.Minute
+    tests := []struct {
+        name      string
+        mapping   TimeMapping
+        dailyPeak string
+        wantPeak  time.Duration
+    }{
+        // Con el pico a las 09:00 y el reloj desde las 08:00 a 60x, el pico
+        // cae a 1 min real del inicio.
+        {"pico a las 09:00", TimeMapping{StartClock: "08:00", Scale: 60}, "09:00", time.Minute},
+        {"pico antes del inicio", TimeMapping{StartClock: "08:00", Scale: 60}, "07:00", 23 * time.Minute},
+        {"identidad", TimeMapping{}, "00:06", 6 * time.Minute},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultConfig()
+            config.TimeMapping = tt.mapping
+            config.DailyPeak = tt.dailyPeak
+            phase := dailyPhase(config, day)
+            // El seno llega a su máximo cuando 2π·t/día - fase = π/2.
+            peak := time.Duration((math.Pi/2 + phase) / (2 * math.Pi) * float64(day))
+            if diff := (peak - tt.wantPeak).Abs(); diff > time.Millisecond {
+                t.Errorf("pico en %v, want %v", peak, tt.wantPeak)
+            }
+        })
+    }
+    if got := dailyPhase(DefaultConfig(), day); got != utils.DEFAULT_DAILY_PHASE {
+        t.Errorf("sin DailyPeak la fase es %v, want %v", got, utils.DEFAULT_DAILY_PHASE)
+    }
+}
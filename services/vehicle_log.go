@@ -0,0 +1,135 @@
+package services
+
+import (
+    "encoding/csv"
+    "fmt"
+    "os"
+    "strconv"
+    "sync"
+    "time"
+    "holafyne/models"
+)
+
+// VEHICLE_LOG_FLUSH_INTERVAL es cada cuánto se vuelcan a disco las filas del
+// registro de vehículos.
+const VEHICLE_LOG_FLUSH_INTERVAL = time.Second
+
+const (
+    OUTCOME_EXITED    = "salida"
+    OUTCOME_REJECTED  = "rechazo"
+    OUTCOME_ABANDONED = "abandono"
+    OUTCOME_CANCELLED = "cancelado"
+    OUTCOME_SITE_FULL = "sitio lleno"
+)
+
+// vehicleLog escribe una fila CSV por cada vehículo que termina su recorrido,
+// sin guardar nada en memoria. Un *vehicleLog nil no registra nada.
+type vehicleLog struct {
+    mu     sync.Mutex
+    file   *os.File
+    writer *csv.Writer
+    done   chan struct{}
+    closed bool
+}
+
+// openVehicleLog abre (o crea) el archivo en modo de anexado y escribe el
+// encabezado solo si está vacío.
+func openVehicleLog(path string) (*vehicleLog, error) {
+    file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+    if err != nil {
+        return nil, err
+    }
+    info, err := file.Stat()
+    if err != nil {
+        file.Close()
+        return nil, err
+    }
+
+    vl := &vehicleLog{file: file, writer: csv.NewWriter(file), done: make(chan struct{})}
+    if info.Size() == 0 {
+        vl.writer.Write([]string{
+            "vehicleID", "outcome", "waitSeconds", "staySeconds", "time",
+            "waitingSeconds", "enteringSeconds", "parkedSeconds", "exitingSeconds",
+        })
+    }
+    go vl.flushLoop()
+    return vl, nil
+}
+
+func (vl *vehicleLog) flushLoop() {
+    ticker := time.NewTicker(VEHICLE_LOG_FLUSH_INTERVAL)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-vl.done:
+            return
+        case <-ticker.C:
+            vl.mu.Lock()
+            vl.writer.Flush()
+            vl.mu.Unlock()
+        }
+    }
+}
+
+func (vl *vehicleLog) record(vehicle *models.Vehicle, outcome string, stay time.Duration, times map[models.VehicleState]time.Duration) {
+    if vl == nil {
+        return
+    }
+    vl.mu.Lock()
+    defer vl.mu.Unlock()
+    if vl.closed {
+        return
+    }
+    row := []string{
+        strconv.Itoa(vehicle.ID),
+        outcome,
+        strconv.FormatFloat(vehicle.GetWaitDuration().Seconds(), 'f', 3, 64),
+        strconv.FormatFloat(stay.Seconds(), 'f', 3, 64),
+        time.Now().Format(time.RFC3339),
+    }
+    for _, state := range models.VehicleStates {
+        row = append(row, strconv.FormatFloat(times[state].Seconds(), 'f', 3, 64))
+    }
+    vl.writer.Write(row)
+}
+
+func (vl *vehicleLog) close() error {
+    if vl == nil {
+        return nil
+    }
+    vl.mu.Lock()
+    defer vl.mu.Unlock()
+    if vl.closed {
+        return nil
+    }
+    vl.closed = true
+    close(vl.done)
+    vl.writer.Flush()
+    if err := vl.writer.Error(); err != nil {
+        vl.file.Close()
+        return err
+    }
+    return vl.file.Close()
+}
+
+// startVehicleLog abre el registro configurado en VehicleLogPath, si hay uno.
+// Si no se puede abrir, la simulación sigue sin registro y se avisa en el log.
+func (s *Simulation) startVehicleLog() {
+    if s.config.VehicleLogPath == "" {
+        return
+    }
+    vl, err := openVehicleLog(s.config.VehicleLogPath)
+    if err != nil {
+        if s.updateUI != nil {
+            s.updateUI(int(s.parking.GetAvailableSpaces()), fmt.Sprintf("No se pudo abrir el registro de vehículos: %v", err))
+        }
+        return
+    }
+    s.vehicleLog = vl
+}
+
+func (s *Simulation) stopVehicleLog() {
+    if err := s.vehicleLog.close(); err != nil && s.updateUI != nil {
+        s.updateUI(int(s.parking.GetAvailableSpaces()), fmt.Sprintf("Error al cerrar el registro de vehículos: %v", err))
+    }
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+    "encoding/csv"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+// readVehicleLog devuelve las filas del registro sin el encabezado. Antes del
+// primer volcado el archivo puede estar vacío.
+func readVehicleLog(t *testing.T, path string) [][]string {
+    t.Helper()
+    file, err := os.Open(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer file.Close()
+    rows, err := csv.NewReader(file).ReadAll()
+    if err != nil {
+        t.Fatalf("el registro no es un CSV válido: %v", err)
+    }
+    if len(rows) == 0 {
+        return nil
+    }
+    if rows[0][0] != "vehicleID" {
+        t.Fatalf("falta el encabezado: %v", rows)
+    }
+    return rows[1:]
+}
+
+// waitForRows espera hasta que el registro tenga más de n filas.
+func waitForRows(t *testing.T, path string, n int) int {
+    t.Helper()
+    deadline := time.Now().Add(3 * VEHICLE_LOG_FLUSH_INTERVAL)
+    for time.Now().Before(deadline) {
+        if rows := len(readVehicleLog(t, path)); rows > n {
+            return rows
+        }
+        time.Sleep(50 * time.Millisecond)
+    }
+    t.Fatalf("el registro no pasó de %d filas", n)
+    return 0
+}
+
+func TestVehicleLogStreamsRowsAndSurvivesStop(t *testing.T) {
+    tests := []struct {
+        name             string
+        minPark, maxPark float64
+    }{
+        {"estancias cortas", 0.05, 0.1},
+        {"estancias que Stop interrumpe", 30, 60},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            path := filepath.Join(t.TempDir(), "vehiculos.csv")
+            config := drainConfig(tt.minPark, tt.maxPark)
+            config.MaxVehicles = 1000
+            config.VehicleLogPath = path
+            sim := NewSimulationWithConfig(config, func(int, string) {})
+            if err := sim.Start(); err != nil {
+                t.Fatal(err)
+            }
+
+            first := waitForRows(t, path, 0)
+            waitForRows(t, path, first)
+            if !sim.IsRunning() {
+                t.Fatal("la simulación terminó antes de tiempo")
+            }
+            sim.Stop()
+
+            rows := readVehicleLog(t, path)
+            metrics := sim.GetMetrics()
+            want := metrics.TotalExited + metrics.TotalRejected + metrics.TotalAbandoned + metrics.TotalCancelled
+            if int64(len(rows)) != want {
+                t.Errorf("filas = %d, want %d (%+v)", len(rows), want, metrics)
+            }
+            seen := map[string]bool{}
+            for _, row := range rows {
+                if len(row) != 9 {
+                    t.Fatalf("fila con %d columnas: %v", len(row), row)
+                }
+                if seen[row[0]] {
+                    t.Errorf("vehículo %s registrado dos veces", row[0])
+                }
+                seen[row[0]] = true
+            }
+        })
+    }
+}
+
+func TestVehicleLogAppendsToExistingFile(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "vehiculos.csv")
+    config := drainConfig(0.05, 0.1)
+    config.VehicleLogPath = path
+    total := 0
+    for run := 0; run < 2; run++ {
+        sim := NewSimulationWithConfig(config, func(int, string) {})
+        if err := sim.SetArrivalSource(&burstArrivals{n: 3}); err != nil {
+            t.Fatal(err)
+        }
+        if err := sim.Start(); err != nil {
+            t.Fatal(err)
+        }
+        waitForCounter(&sim.metrics.TotalExited, 3)
+        sim.Stop()
+        total += 3
+        if rows := readVehicleLog(t, path); len(rows) != total {
+            t.Errorf("corrida %d: filas = %d, want %d", run+1, len(rows), total)
+        }
+    }
+}
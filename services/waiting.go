@@ -0,0 +1,167 @@
+package services
+
+import (
+    "sync/atomic"
+    "time"
+    "holafyne/models"
+    "holafyne/utils"
+)
+
+type VehicleInfo struct {
+    ID          int
+    Visit       int
+    ArrivalTime time.Time
+    Position    int
+}
+
+// LongestWaiting devuelve el vehículo de la cola que lleva más tiempo
+// esperando y cuánto lleva. Position es su índice en la cola, empezando en 0.
+func (s *Simulation) LongestWaiting() (VehicleInfo, time.Duration, bool) {
+    s.queueMutex.RLock()
+    defer s.queueMutex.RUnlock()
+
+    var info VehicleInfo
+    var longest time.Duration
+    found := false
+    for i, vehicle := range s.queue {
+        wait := vehicle.GetWaitDuration()
+        if !found || wait > longest {
+            info = VehicleInfo{
+                ID:          vehicle.ID,
+                Visit:       vehicle.Visit,
+                ArrivalTime: vehicle.ArrivalTime,
+                Position:    i,
+            }
+            longest = wait
+            found = true
+        }
+    }
+    return info, longest, found
+}
+
+// GetWorstWait devuelve la espera más larga registrada desde el último
+// reinicio de estadísticas, contando vehículos que entraron o abandonaron.
+func (s *Simulation) GetWorstWait() time.Duration {
+    return time.Duration(atomic.LoadInt64(&s.worstWait))
+}
+
+func (s *Simulation) recordWait(wait time.Duration) {
+    for {
+        current := atomic.LoadInt64(&s.worstWait)
+        if int64(wait) <= current {
+            return
+        }
+        if atomic.CompareAndSwapInt64(&s.worstWait, current, int64(wait)) {
+            return
+        }
+    }
+}
+
+// PeekNextInQueue devuelve, sin sacarlo, el vehículo que entrará cuando se
+// libere un espacio. El criterio de desempate ya ordenó la cola al insertar,
+// así que siempre es el primero.
+func (s *Simulation) PeekNextInQueue() (*models.Vehicle, bool) {
+    s.queueMutex.RLock()
+    defer s.queueMutex.RUnlock()
+    if len(s.queue) == 0 {
+        return nil, false
+    }
+    return s.queue[0], true
+}
+
+// GetQueuedVehicles devuelve los vehículos de la cola en orden de entrada.
+func (s *Simulation) GetQueuedVehicles() []*models.Vehicle {
+    s.queueMutex.RLock()
+    defer s.queueMutex.RUnlock()
+    return append([]*models.Vehicle(nil), s.queue...)
+}
+
+// GetQueueAgeDistribution devuelve cuánto lleva en la cola cada vehículo.
+func (s *Simulation) GetQueueAgeDistribution() map[int]time.Duration {
+    s.queueMutex.RLock()
+    defer s.queueMutex.RUnlock()
+
+    ages := make(map[int]time.Duration, len(s.queue))
+    now := time.Now()
+    for _, vehicle := range s.queue {
+        ages[vehicle.ID] = now.Sub(vehicle.QueuedAt)
+    }
+    return ages
+}
+
+// GetMaxQueueAge devuelve el vehículo que lleva más tiempo en la cola. Con
+// la cola vacía devuelve (-1, 0).
+func (s *Simulation) GetMaxQueueAge() (vehicleID int, age time.Duration) {
+    s.queueMutex.RLock()
+    defer s.queueMutex.RUnlock()
+
+    index := s.oldestQueued()
+    if index < 0 {
+        return -1, 0
+    }
+    return s.queue[index].ID, time.Since(s.queue[index].QueuedAt)
+}
+
+// EvictOldestInQueue saca de la cola al vehículo que más ha esperado.
+func (s *Simulation) EvictOldestInQueue() (*models.Vehicle, bool) {
+    s.queueMutex.Lock()
+    index := s.oldestQueued()
+    if index < 0 {
+        s.queueMutex.Unlock()
+        return nil, false
+    }
+    vehicle := s.removeQueuedAt(index)
+    s.queueMutex.Unlock()
+
+    s.notifyDeparture(vehicle)
+    return vehicle, true
+}
+
+// CancelWaiting saca de la cola al vehículo indicado, como si el conductor
+// desistiera.
+func (s *Simulation) CancelWaiting(vehicleID int) (*models.Vehicle, bool) {
+    s.queueMutex.Lock()
+    index := -1
+    for i, vehicle := range s.queue {
+        if vehicle.ID == vehicleID {
+            index = i
+            break
+        }
+    }
+    if index < 0 {
+        s.queueMutex.Unlock()
+        return nil, false
+    }
+    vehicle := s.removeQueuedAt(index)
+    s.queueMutex.Unlock()
+
+    s.notifyDeparture(vehicle)
+    return vehicle, true
+}
+
+// oldestQueued y removeQueuedAt deben llamarse con queueMutex tomado.
+func (s *Simulation) oldestQueued() int {
+    index := -1
+    for i, vehicle := range s.queue {
+        if index < 0 || vehicle.QueuedAt.Before(s.queue[index].QueuedAt) {
+            index = i
+        }
+    }
+    return index
+}
+
+func (s *Simulation) removeQueuedAt(index int) *models.Vehicle {
+    vehicle := s.queue[index]
+    previousLen := len(s.queue)
+    s.queue = append(s.queue[:index], s.queue[index+1:]...)
+    atomic.AddInt64(&s.metrics.TotalCancelled, 1)
+    s.recordOutcome(vehicle, OUTCOME_CANCELLED, 0)
+    s.notifyQueueChange(Cancelled, vehicle, previousLen)
+    return vehicle
+}
+
+// GetFairnessIndex calcula el índice de Jain sobre las esperas del
+// reservorio: 1 si todos esperaron lo mismo. Sin muestras devuelve 0.
+func (s *Simulation) GetFairnessIndex() float64 {
+    return utils.JainIndex(s.samples.wait.Samples())
+}
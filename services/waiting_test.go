@@ -0,0 +1,27 @@
+package services
+
+import "testing"
+
+func TestGetFairnessIndexOverWaitReservoir(t *testing.T) {
+    tests := []struct {
+        name    string
+        waits   []float64
+        wantMin float64
+        wantMax float64
+    }{
+        {"esperas idénticas", []float64{3, 3, 3, 3, 3, 3}, 1 - 1e-9, 1},
+        {"esperas heterogéneas", []float64{0.5, 1, 2, 4, 8, 16}, 0, 0.99},
+        {"una espera larga entre cortas", []float64{1, 1, 1, 1, 30}, 0, 0.5},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sim := NewSimulationWithConfig(DefaultConfig(), func(int, string) {})
+            for _, wait := range tt.waits {
+                sim.samples.wait.Add(wait)
+            }
+            if got := sim.GetFairnessIndex(); got < tt.wantMin || got > tt.wantMax {
+                t.Errorf("GetFairnessIndex = %v, want entre %v y %v", got, tt.wantMin, tt.wantMax)
+            }
+        })
+    }
+}
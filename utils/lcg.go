@@ -0,0 +1,84 @@
+package utils
+
+import (
+    "math"
+    "sync"
+    "time"
+)
+
+const (
+    RNG_BACKEND_STDLIB = "stdlib"
+    RNG_BACKEND_LCG    = "lcg"
+)
+
+// Parámetros MMIX de Knuth.
+const (
+    LCG_MULTIPLIER = 6364136223846793005
+    LCG_INCREMENT  = 1442695040888963407
+)
+
+// DurationGenerator produce intervalos entre eventos.
+type DurationGenerator interface {
+    NextInterval() time.Duration
+}
+
+var (
+    _ DurationGenerator = (*PoissonGenerator)(nil)
+    _ DurationGenerator = (*LCGGenerator)(nil)
+)
+
+// randomSource es lo que PoissonGenerator necesita de su fuente aleatoria;
+// lo cumplen tanto *rand.Rand como *LCGGenerator.
+type randomSource interface {
+    Float64() float64
+    Intn(n int) int
+}
+
+// LCGGenerator es un generador congruencial lineal. A diferencia de
+// math/rand, la secuencia para una semilla no depende de la versión de Go.
+type LCGGenerator struct {
+    state   uint64
+    lambda  float64
+    minTime float64
+    maxTime float64
+    mu      sync.Mutex
+}
+
+func NewLCGGenerator(config PoissonConfig) *LCGGenerator {
+    return &LCGGenerator{
+        state:   uint64(config.RandomSeed),
+        lambda:  config.Lambda,
+        minTime: config.MinTime,
+        maxTime: config.MaxTime,
+    }
+}
+
+// next debe llamarse con mu tomado.
+func (g *LCGGenerator) next() uint64 {
+    g.state = g.state*LCG_MULTIPLIER + LCG_INCREMENT
+    return g.state
+}
+
+// Float64 devuelve un valor uniforme en [0, 1) con los 53 bits altos del
+// estado, que son los de mejor calidad en un LCG módulo 2^64.
+func (g *LCGGenerator) Float64() float64 {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    return float64(g.next()>>11) / (1 << 53)
+}
+
+func (g *LCGGenerator) Intn(n int) int {
+    if n <= 0 {
+        panic("utils: Intn con n <= 0")
+    }
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    return int((g.next() >> 11) % uint64(n))
+}
+
+func (g *LCGGenerator) NextInterval() time.Duration {
+    u := g.Float64()
+    x := -math.Log(1.0-u) / g.lambda
+    x = math.Max(g.minTime, math.Min(g.maxTime, x))
+    return time.Duration(x * float64(time.Second))
+}
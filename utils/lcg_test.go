@@ -0,0 +1,69 @@
+package utils
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestLCGGeneratorIsReproducible(t *testing.T) {
+    tests := []struct {
+        name string
+        seed int64
+        // want fija la secuencia: no debe cambiar con la versión de Go.
+        want []float64
+    }{
+        {"semilla 42", 42, []float64{0.5682303266439076, 0.2254634289477513, 0.41283831882951183}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            config := DefaultPoissonConfig()
+            config.RandomSeed = tt.seed
+            g := NewLCGGenerator(config)
+            for i, want := range tt.want {
+                if got := g.Float64(); got != want {
+                    t.Errorf("Float64 #%d = %v, want %v", i, got, want)
+                }
+            }
+        })
+    }
+}
+
+func TestPoissonBackendsRepeatWithSeed(t *testing.T) {
+    tests := []struct {
+        name    string
+        backend string
+    }{
+        {"stdlib", RNG_BACKEND_STDLIB},
+        {"lcg", RNG_BACKEND_LCG},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            first := seededGenerator(2, 7, tt.backend).NextN(100)
+            second := seededGenerator(2, 7, tt.backend).NextN(100)
+            if !reflect.DeepEqual(first, second) {
+                t.Error("la misma semilla dio secuencias distintas")
+            }
+            if other := seededGenerator(2, 8, tt.backend).NextN(100); reflect.DeepEqual(first, other) {
+                t.Error("semillas distintas dieron la misma secuencia")
+            }
+        })
+    }
+}
+
+func BenchmarkNextInterval(b *testing.B) {
+    backends := []struct {
+        name    string
+        backend string
+    }{
+        {"stdlib", RNG_BACKEND_STDLIB},
+        {"lcg", RNG_BACKEND_LCG},
+    }
+    for _, bb := range backends {
+        b.Run(bb.name, func(b *testing.B) {
+            generator := seededGenerator(2, 1, bb.backend)
+            for i := 0; i < b.N; i++ {
+                generator.NextInterval()
+            }
+        })
+    }
+}
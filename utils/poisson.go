@@ -1,121 +1,274 @@
-package utils
-
-import (
-    "math"
-    "math/rand"
-    "sync"
-    "time"
-)
-
-type PoissonGenerator struct {
-    lambda     float64    
-    minTime    float64   
-    maxTime    float64    
-    rng        *rand.Rand 
-    mu         sync.Mutex 
-}
-
-type PoissonConfig struct {
-    Lambda     float64 
-    MinTime    float64 
-    MaxTime    float64 
-    RandomSeed int64   
-}
-
-func DefaultPoissonConfig() PoissonConfig {
-    return PoissonConfig{
-        Lambda:     2.0,   
-        MinTime:    0.1,   
-        MaxTime:    10.0,  
-        RandomSeed: time.Now().UnixNano(),
-    }
-}
-
-func NewPoissonGenerator(config PoissonConfig) *PoissonGenerator {
-    return &PoissonGenerator{
-        lambda:     config.Lambda,
-        minTime:    config.MinTime,
-        maxTime:    config.MaxTime,
-        rng:        rand.New(rand.NewSource(config.RandomSeed)),
-    }
-}
-
-func NewPoissonGeneratorWithLambda(lambda float64) *PoissonGenerator {
-    config := DefaultPoissonConfig()
-    config.Lambda = lambda
-    return NewPoissonGenerator(config)
-}
-
-func (pg *PoissonGenerator) NextInterval() time.Duration {
-    pg.mu.Lock()
-    defer pg.mu.Unlock()
-
-    u := pg.rng.Float64()
-    x := -math.Log(1.0-u) / pg.lambda
-
-    x = math.Max(pg.minTime, math.Min(pg.maxTime, x))
-
-    return time.Duration(x * float64(time.Second))
-}
-
-func (pg *PoissonGenerator) NextEvents(duration time.Duration) int {
-    pg.mu.Lock()
-    defer pg.mu.Unlock()
-
-    adjustedLambda := pg.lambda * duration.Seconds()
-    
-    L := math.Exp(-adjustedLambda)
-    k := 0
-    p := 1.0
-
-    for {
-        k++
-        p *= pg.rng.Float64()
-        if p < L {
-            return k - 1
-        }
-    }
-}
-
-func (pg *PoissonGenerator) GenerateEventTimes(duration time.Duration) []time.Duration {
-    pg.mu.Lock()
-    defer pg.mu.Unlock()
-
-    var times []time.Duration
-    currentTime := time.Duration(0)
-
-    for currentTime < duration {
-        interval := pg.NextInterval()
-        currentTime += interval
-        if currentTime < duration {
-            times = append(times, currentTime)
-        }
-    }
-
-    return times
-}
-
-func (pg *PoissonGenerator) SetLambda(lambda float64) {
-    pg.mu.Lock()
-    defer pg.mu.Unlock()
-    pg.lambda = lambda
-}
-
-func (pg *PoissonGenerator) SetTimeConstraints(minTime, maxTime float64) {
-    pg.mu.Lock()
-    defer pg.mu.Unlock()
-    pg.minTime = minTime
-    pg.maxTime = maxTime
-}
-
-func (pg *PoissonGenerator) GetLambda() float64 {
-    pg.mu.Lock()
-    defer pg.mu.Unlock()
-    return pg.lambda
-}
-
-func (pg *PoissonGenerator) GetTimeConstraints() (float64, float64) {
-    pg.mu.Lock()
-    defer pg.mu.Unlock()
-    return pg.minTime, pg.maxTime
+package utils
+
+import (
+    "math"
+    "math/rand"
+    "sync"
+    "time"
+)
+
+const MAX_RECORDED_SAMPLES = 10000
+
+// DEFAULT_DAILY_PHASE es la fase en radianes de GenerateDailyPattern que pone
+// el pico de llegadas a las 8 h de un día de 24 h: 2π·8/24 − φ = π/2.
+const DEFAULT_DAILY_PHASE = math.Pi / 6
+
+type PoissonGenerator struct {
+    lambda     float64    
+    minTime    float64   
+    maxTime    float64    
+    rng        randomSource
+    mu         sync.Mutex 
+    samples    []float64
+    original   PoissonConfig
+    amplitude  float64
+    phase      float64
+}
+
+type PoissonConfig struct {
+    Lambda     float64 
+    MinTime    float64 
+    MaxTime    float64 
+    RandomSeed int64   
+    RNGBackend string  
+}
+
+func DefaultPoissonConfig() PoissonConfig {
+    return PoissonConfig{
+        Lambda:     2.0,   
+        MinTime:    0.1,   
+        MaxTime:    10.0,  
+        RandomSeed: time.Now().UnixNano(),
+        RNGBackend: RNG_BACKEND_STDLIB,
+    }
+}
+
+func NewPoissonGenerator(config PoissonConfig) *PoissonGenerator {
+    return &PoissonGenerator{
+        lambda:     config.Lambda,
+        minTime:    config.MinTime,
+        maxTime:    config.MaxTime,
+        rng:        newRandomSource(config),
+        original:   config,
+        phase:      DEFAULT_DAILY_PHASE,
+    }
+}
+
+func newRandomSource(config PoissonConfig) randomSource {
+    if config.RNGBackend == RNG_BACKEND_LCG {
+        return NewLCGGenerator(config)
+    }
+    return rand.New(rand.NewSource(config.RandomSeed))
+}
+
+// Reset devuelve el generador al estado en que se construyó: misma semilla,
+// mismos parámetros y sin muestras registradas, para repetir una corrida.
+func (pg *PoissonGenerator) Reset() {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+    pg.lambda = pg.original.Lambda
+    pg.minTime = pg.original.MinTime
+    pg.maxTime = pg.original.MaxTime
+    pg.rng = newRandomSource(pg.original)
+    pg.samples = nil
+}
+
+func NewPoissonGeneratorWithLambda(lambda float64) *PoissonGenerator {
+    config := DefaultPoissonConfig()
+    config.Lambda = lambda
+    return NewPoissonGenerator(config)
+}
+
+func (pg *PoissonGenerator) NextInterval() time.Duration {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+
+    u := pg.rng.Float64()
+    x := -math.Log(1.0-u) / pg.lambda
+
+    x = math.Max(pg.minTime, math.Min(pg.maxTime, x))
+    pg.recordSample(x)
+
+    return time.Duration(x * float64(time.Second))
+}
+
+// NextN devuelve los siguientes n intervalos.
+func (pg *PoissonGenerator) NextN(n int) []time.Duration {
+    intervals := make([]time.Duration, 0, n)
+    for i := 0; i < n; i++ {
+        intervals = append(intervals, pg.NextInterval())
+    }
+    return intervals
+}
+
+func (pg *PoissonGenerator) recordSample(x float64) {
+    pg.samples = append(pg.samples, x)
+    if len(pg.samples) > MAX_RECORDED_SAMPLES {
+        pg.samples = pg.samples[len(pg.samples)-MAX_RECORDED_SAMPLES:]
+    }
+}
+
+// RecordSamples agrega intervalos observados (en segundos) a las muestras
+// que usa ParametricBootstrap, p. ej. datos reales de llegadas.
+func (pg *PoissonGenerator) RecordSamples(samples []float64) {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+    for _, x := range samples {
+        pg.recordSample(x)
+    }
+}
+
+func (pg *PoissonGenerator) GetRecordedSamples() []float64 {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+    return append([]float64(nil), pg.samples...)
+}
+
+// EstimateFromSamples devuelve el estimador de máxima verosimilitud de lambda
+// para intervalos exponenciales: n / suma de los intervalos.
+func (pg *PoissonGenerator) EstimateFromSamples(samples []float64) float64 {
+    sum := 0.0
+    for _, x := range samples {
+        sum += x
+    }
+    if sum <= 0 {
+        return 0
+    }
+    return float64(len(samples)) / sum
+}
+
+// ParametricBootstrap remuestrea con reemplazo los intervalos registrados n
+// veces y devuelve la estimación de lambda de cada remuestreo.
+func (pg *PoissonGenerator) ParametricBootstrap(n int) []float64 {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+
+    if len(pg.samples) == 0 || n <= 0 {
+        return nil
+    }
+
+    estimates := make([]float64, n)
+    resample := make([]float64, len(pg.samples))
+    for i := range estimates {
+        for j := range resample {
+            resample[j] = pg.samples[pg.rng.Intn(len(pg.samples))]
+        }
+        estimates[i] = pg.EstimateFromSamples(resample)
+    }
+    return estimates
+}
+
+func (pg *PoissonGenerator) NextEvents(duration time.Duration) int {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+
+    adjustedLambda := pg.lambda * duration.Seconds()
+    
+    L := math.Exp(-adjustedLambda)
+    k := 0
+    p := 1.0
+
+    for {
+        k++
+        p *= pg.rng.Float64()
+        if p < L {
+            return k - 1
+        }
+    }
+}
+
+// ExpectedArrivalCount es el número esperado de llegadas en d: λ·d.
+func (pg *PoissonGenerator) ExpectedArrivalCount(d time.Duration) float64 {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+    return pg.lambda * d.Seconds()
+}
+
+func (pg *PoissonGenerator) GenerateEventTimes(duration time.Duration) []time.Duration {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+
+    var times []time.Duration
+    currentTime := time.Duration(0)
+
+    for currentTime < duration {
+        interval := pg.NextInterval()
+        currentTime += interval
+        if currentTime < duration {
+            times = append(times, currentTime)
+        }
+    }
+
+    return times
+}
+
+// SetDailyPattern define la amplitud A y la fase phi (en radianes) de la
+// intensidad λ(t) = λ · (1 + A·sin(2πt/día − phi)) de GenerateDailyPattern.
+func (pg *PoissonGenerator) SetDailyPattern(A, phi float64) {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+    pg.amplitude = A
+    pg.phase = phi
+}
+
+// GenerateDailyPattern genera un proceso de Poisson no homogéneo durante
+// dayDuration por adelgazamiento: se proponen llegadas con la intensidad
+// máxima y cada una se acepta con probabilidad λ(t)/λmax. Devuelve los
+// instantes de llegada desde el inicio del día. Los intervalos no se acotan
+// con SetTimeConstraints, porque eso deformaría la intensidad.
+func (pg *PoissonGenerator) GenerateDailyPattern(dayDuration time.Duration) []time.Duration {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+
+    day := dayDuration.Seconds()
+    maxLambda := pg.lambda * (1 + math.Abs(pg.amplitude))
+    if day <= 0 || maxLambda <= 0 {
+        return nil
+    }
+
+    var times []time.Duration
+    t := 0.0
+    for {
+        t += -math.Log(1.0-pg.rng.Float64()) / maxLambda
+        if t >= day {
+            return times
+        }
+        intensity := pg.lambda * (1 + pg.amplitude*math.Sin(2*math.Pi*t/day-pg.phase))
+        if pg.rng.Float64()*maxLambda < intensity {
+            times = append(times, time.Duration(t*float64(time.Second)))
+        }
+    }
+}
+
+func (pg *PoissonGenerator) SetLambda(lambda float64) {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+    pg.lambda = lambda
+}
+
+func (pg *PoissonGenerator) SetTimeConstraints(minTime, maxTime float64) {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+    pg.minTime = minTime
+    pg.maxTime = maxTime
+}
+
+func (pg *PoissonGenerator) GetLambda() float64 {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+    return pg.lambda
+}
+
+// GetOriginalConfig devuelve la configuración con que se construyó, incluida
+// la semilla, para poder repetir la corrida.
+func (pg *PoissonGenerator) GetOriginalConfig() PoissonConfig {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+    return pg.original
+}
+
+func (pg *PoissonGenerator) GetTimeConstraints() (float64, float64) {
+    pg.mu.Lock()
+    defer pg.mu.Unlock()
+    return pg.minTime, pg.maxTime
 }
\ No newline at end of file
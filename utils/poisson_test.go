@@ -0,0 +1,158 @@
+package utils
+
+import (
+    "math"
+    "math/rand"
+    "reflect"
+    "testing"
+    "time"
+)
+
+func seededGenerator(lambda float64, seed int64, backend string) *PoissonGenerator {
+    config := DefaultPoissonConfig()
+    config.Lambda = lambda
+    config.RandomSeed = seed
+    config.RNGBackend = backend
+    return NewPoissonGenerator(config)
+}
+
+func TestParametricBootstrapCIContainsLambda(t *testing.T) {
+    const (
+        samples    = 1000
+        bootstraps = 2000
+    )
+    tests := []struct {
+        name   string
+        lambda float64
+        seed   int64
+    }{
+        {"lambda 2", 2.0, 1},
+        {"lambda 0.5", 0.5, 4},
+        {"lambda 5", 5.0, 3},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            rng := rand.New(rand.NewSource(tt.seed))
+            intervals := make([]float64, samples)
+            for i := range intervals {
+                intervals[i] = rng.ExpFloat64() / tt.lambda
+            }
+            generator := seededGenerator(tt.lambda, tt.seed, RNG_BACKEND_STDLIB)
+            generator.RecordSamples(intervals)
+
+            estimates := generator.ParametricBootstrap(bootstraps)
+            if len(estimates) != bootstraps {
+                t.Fatalf("estimaciones = %d, want %d", len(estimates), bootstraps)
+            }
+            mean, lower, upper := MeanAndCI(estimates, 0.05)
+            if lower > tt.lambda || upper < tt.lambda {
+                t.Errorf("IC 95%% = [%.3f, %.3f], want que contenga %.1f", lower, upper, tt.lambda)
+            }
+            if mean < lower || mean > upper {
+                t.Errorf("media %.3f fuera de [%.3f, %.3f]", mean, lower, upper)
+            }
+        })
+    }
+}
+
+func TestParametricBootstrapWithoutSamples(t *testing.T) {
+    tests := []struct {
+        name    string
+        samples []float64
+        n       int
+    }{
+        {"sin muestras", nil, 100},
+        {"n cero", []float64{0.5, 1}, 0},
+        {"n negativo", []float64{0.5, 1}, -1},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            generator := seededGenerator(2, 1, RNG_BACKEND_STDLIB)
+            generator.RecordSamples(tt.samples)
+            if estimates := generator.ParametricBootstrap(tt.n); estimates != nil {
+                t.Errorf("ParametricBootstrap = %v, want nil", estimates)
+            }
+        })
+    }
+}
+
+func TestResetRepeatsSequence(t *testing.T) {
+    tests := []struct {
+        name    string
+        backend string
+        mutate  func(pg *PoissonGenerator)
+    }{
+        {"stdlib", RNG_BACKEND_STDLIB, func(pg *PoissonGenerator) {}},
+        {"lcg", RNG_BACKEND_LCG, func(pg *PoissonGenerator) {}},
+        {"restaura lambda y límites", RNG_BACKEND_STDLIB, func(pg *PoissonGenerator) {
+            pg.SetLambda(9)
+            pg.SetTimeConstraints(0.5, 1)
+        }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            pg := seededGenerator(2, 11, tt.backend)
+            first := pg.NextN(100)
+            tt.mutate(pg)
+            pg.Reset()
+            if samples := pg.GetRecordedSamples(); len(samples) != 0 {
+                t.Errorf("muestras después de Reset = %d, want 0", len(samples))
+            }
+            if second := pg.NextN(100); !reflect.DeepEqual(first, second) {
+                t.Error("NextN(100) después de Reset no repite la secuencia")
+            }
+            if lambda := pg.GetLambda(); lambda != 2 {
+                t.Errorf("lambda = %v, want 2", lambda)
+            }
+        })
+    }
+}
+
+func TestGenerateDailyPatternPeaksWhereExpected(t *testing.T) {
+    const day = 24 * time.Hour
+    tests := []struct {
+        name      string
+        amplitude float64
+        phase     float64
+        peak      time.Duration
+    }{
+        {"fase por defecto: pico a las 8 h", 0.8, DEFAULT_DAILY_PHASE, 8 * time.Hour},
+        {"amplitud menor", 0.5, DEFAULT_DAILY_PHASE, 8 * time.Hour},
+        // Con phi = -π/2 el pico cae al inicio del día.
+        {"pico a medianoche", 0.8, -math.Pi / 2, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            pg := seededGenerator(0.02, 5, RNG_BACKEND_STDLIB)
+            pg.SetDailyPattern(tt.amplitude, tt.phase)
+            times := pg.GenerateDailyPattern(day)
+
+            // Cuenta las llegadas en las dos horas alrededor del pico y
+            // alrededor del valle, doce horas después.
+            trough := (tt.peak + 12*time.Hour) % day
+            near := func(at, center time.Duration) bool {
+                d := (at - center + day) % day
+                return d < time.Hour || d > day-time.Hour
+            }
+            peakCount, troughCount := 0, 0
+            for i, at := range times {
+                if at < 0 || at >= day || (i > 0 && at < times[i-1]) {
+                    t.Fatalf("llegada %d en %v fuera de orden o del día", i, at)
+                }
+                if near(at, tt.peak) {
+                    peakCount++
+                }
+                if near(at, trough) {
+                    troughCount++
+                }
+            }
+            // En dos horas se esperan unas 144·(1 ± A) llegadas.
+            if peakCount <= 2*troughCount {
+                t.Errorf("llegadas en el pico = %d, en el valle = %d: want bastante más en el pico", peakCount, troughCount)
+            }
+            if mean := float64(len(times)) / day.Seconds(); math.Abs(mean-0.02) > 0.002 {
+                t.Errorf("tasa media = %.4f/s, want 0.02", mean)
+            }
+        })
+    }
+}
@@ -0,0 +1,36 @@
+package utils
+
+// MMCK_RESCALE_LIMIT es el tamaño de la suma a partir del cual
+// MMCKBlockingProbability reescala sus términos.
+const MMCK_RESCALE_LIMIT = 1e100
+
+// MMCKBlockingProbability devuelve la probabilidad de que un cliente que
+// llega a una M/M/c/K encuentre el sistema lleno (K clientes entre servidores
+// y cola). Los términos a^n/n! se calculan de forma incremental y, cuando la
+// suma crece demasiado, se dividen todos por ella: el cociente no cambia y
+// con K grandes y carga mayor que c no se llega a Inf/Inf.
+func MMCKBlockingProbability(lambda, mu float64, c, k int) float64 {
+    if c <= 0 || k < c {
+        return 1
+    }
+    if lambda <= 0 || mu <= 0 {
+        return 0
+    }
+
+    load := lambda / mu
+    term := 1.0
+    sum := term
+    for n := 1; n <= k; n++ {
+        if n <= c {
+            term *= load / float64(n)
+        } else {
+            term *= load / float64(c)
+        }
+        sum += term
+        if sum > MMCK_RESCALE_LIMIT {
+            term /= sum
+            sum = 1
+        }
+    }
+    return term / sum
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+    "math"
+    "testing"
+)
+
+// mm1kBlocking es la fórmula cerrada de la M/M/1/K.
+func mm1kBlocking(rho float64, k int) float64 {
+    if rho == 1 {
+        return 1 / float64(k+1)
+    }
+    return (1 - rho) * math.Pow(rho, float64(k)) / (1 - math.Pow(rho, float64(k+1)))
+}
+
+func TestMMCKBlockingProbability(t *testing.T) {
+    tests := []struct {
+        name   string
+        lambda float64
+        mu     float64
+        c, k   int
+        want   float64
+    }{
+        {"M/M/1/1 es Erlang B", 2, 1, 1, 1, 2.0 / 3},
+        {"M/M/1/5 con ρ < 1", 0.5, 1, 1, 5, mm1kBlocking(0.5, 5)},
+        {"M/M/1/5 con ρ = 1", 1, 1, 1, 5, mm1kBlocking(1, 5)},
+        {"M/M/1/5 con ρ > 1", 3, 1, 1, 5, mm1kBlocking(3, 5)},
+        {"Erlang B con c = 2", 1, 1, 2, 2, 0.2},
+        {"K grande y carga mayor que c", 20, 1, 10, 5000, 0.5},
+        {"K enorme con ρ > 1", 300, 1, 100, 100000, 2.0 / 3},
+        {"K grande con ρ < 1", 5, 1, 10, 5000, 0},
+        {"sin servidores", 1, 1, 0, 5, 1},
+        {"K menor que c", 1, 1, 3, 2, 1},
+        {"sin llegadas", 0, 1, 2, 4, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := MMCKBlockingProbability(tt.lambda, tt.mu, tt.c, tt.k)
+            if math.IsNaN(got) || got < 0 || got > 1 {
+                t.Fatalf("P = %v, want una probabilidad", got)
+            }
+            if math.Abs(got-tt.want) > 1e-9 {
+                t.Errorf("P = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
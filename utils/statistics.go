@@ -0,0 +1,205 @@
+package utils
+
+import (
+    "math"
+    "math/rand"
+    "sort"
+    "sync"
+)
+
+var tDistAlphas = []float64{0.10, 0.05, 0.02, 0.01}
+
+var tDistDegrees = []int{
+    1, 2, 3, 4, 5, 6, 7, 8, 9, 10,
+    11, 12, 13, 14, 15, 16, 17, 18, 19, 20,
+    21, 22, 23, 24, 25, 26, 27, 28, 29, 30,
+    40, 60, 120,
+}
+
+var tDistTable = [][]float64{
+    {6.314, 12.706, 31.821, 63.657},
+    {2.920, 4.303, 6.965, 9.925},
+    {2.353, 3.182, 4.541, 5.841},
+    {2.132, 2.776, 3.747, 4.604},
+    {2.015, 2.571, 3.365, 4.032},
+    {1.943, 2.447, 3.143, 3.707},
+    {1.895, 2.365, 2.998, 3.499},
+    {1.860, 2.306, 2.896, 3.355},
+    {1.833, 2.262, 2.821, 3.250},
+    {1.812, 2.228, 2.764, 3.169},
+    {1.796, 2.201, 2.718, 3.106},
+    {1.782, 2.179, 2.681, 3.055},
+    {1.771, 2.160, 2.650, 3.012},
+    {1.761, 2.145, 2.624, 2.977},
+    {1.753, 2.131, 2.602, 2.947},
+    {1.746, 2.120, 2.583, 2.921},
+    {1.740, 2.110, 2.567, 2.898},
+    {1.734, 2.101, 2.552, 2.878},
+    {1.729, 2.093, 2.539, 2.861},
+    {1.725, 2.086, 2.528, 2.845},
+    {1.721, 2.080, 2.518, 2.831},
+    {1.717, 2.074, 2.508, 2.819},
+    {1.714, 2.069, 2.500, 2.807},
+    {1.711, 2.064, 2.492, 2.797},
+    {1.708, 2.060, 2.485, 2.787},
+    {1.706, 2.056, 2.479, 2.779},
+    {1.703, 2.052, 2.473, 2.771},
+    {1.701, 2.048, 2.467, 2.763},
+    {1.699, 2.045, 2.462, 2.756},
+    {1.697, 2.042, 2.457, 2.750},
+    {1.684, 2.021, 2.423, 2.704},
+    {1.671, 2.000, 2.390, 2.660},
+    {1.658, 1.980, 2.358, 2.617},
+}
+
+var normalQuantiles = []float64{1.645, 1.960, 2.326, 2.576}
+
+// TDistQuantile devuelve el valor crítico bilateral t tal que
+// P(|T| > t) = alpha para una t de Student con df grados de libertad.
+// Solo se tabulan alpha 0.10, 0.05, 0.02 y 0.01; otros valores usan la
+// columna más cercana. Para df fuera de la tabla se usa la fila inferior
+// más próxima, lo que da un intervalo algo más conservador.
+func TDistQuantile(df int, alpha float64) float64 {
+    column := 0
+    for i, a := range tDistAlphas {
+        if math.Abs(a-alpha) < math.Abs(tDistAlphas[column]-alpha) {
+            column = i
+        }
+    }
+
+    if df < 1 {
+        df = 1
+    }
+    if df > tDistDegrees[len(tDistDegrees)-1]*10 {
+        return normalQuantiles[column]
+    }
+
+    row := 0
+    for i, d := range tDistDegrees {
+        if d <= df {
+            row = i
+        }
+    }
+    return tDistTable[row][column]
+}
+
+func Mean(samples []float64) float64 {
+    if len(samples) == 0 {
+        return 0
+    }
+    sum := 0.0
+    for _, x := range samples {
+        sum += x
+    }
+    return sum / float64(len(samples))
+}
+
+func StdDev(samples []float64) float64 {
+    if len(samples) < 2 {
+        return 0
+    }
+    mean := Mean(samples)
+    sum := 0.0
+    for _, x := range samples {
+        sum += (x - mean) * (x - mean)
+    }
+    return math.Sqrt(sum / float64(len(samples)-1))
+}
+
+// Percentile devuelve el percentil p (entre 0 y 1) por interpolación lineal.
+func Percentile(samples []float64, p float64) float64 {
+    if len(samples) == 0 {
+        return 0
+    }
+    sorted := append([]float64(nil), samples...)
+    sort.Float64s(sorted)
+
+    pos := p * float64(len(sorted)-1)
+    lower := int(math.Floor(pos))
+    upper := int(math.Ceil(pos))
+    if lower < 0 {
+        return sorted[0]
+    }
+    if upper >= len(sorted) {
+        return sorted[len(sorted)-1]
+    }
+    frac := pos - float64(lower)
+    return sorted[lower] + frac*(sorted[upper]-sorted[lower])
+}
+
+// JainIndex devuelve el índice de equidad de Jain, (Σx)² / (n·Σx²), entre
+// 1/n y 1. Si todos los valores son cero se consideran iguales y devuelve 1.
+func JainIndex(samples []float64) float64 {
+    if len(samples) == 0 {
+        return 0
+    }
+    sum, sumSquares := 0.0, 0.0
+    for _, x := range samples {
+        sum += x
+        sumSquares += x * x
+    }
+    if sumSquares == 0 {
+        return 1
+    }
+    return sum * sum / (float64(len(samples)) * sumSquares)
+}
+
+// MeanAndCI devuelve la media de las muestras y el intervalo percentil
+// [alpha/2, 1-alpha/2], pensado para muestras bootstrap.
+func MeanAndCI(samples []float64, alpha float64) (mean, lower, upper float64) {
+    if len(samples) == 0 {
+        return 0, 0, 0
+    }
+    return Mean(samples), Percentile(samples, alpha/2), Percentile(samples, 1-alpha/2)
+}
+
+// Reservoir conserva una muestra uniforme de tamaño fijo de un flujo de
+// valores (algoritmo R), para poder estimar dispersión sin guardar todo.
+type Reservoir struct {
+    samples []float64
+    size    int
+    seen    int64
+    rng     *rand.Rand
+    mu      sync.Mutex
+}
+
+func NewReservoir(size int, seed int64) *Reservoir {
+    return &Reservoir{
+        samples: make([]float64, 0, size),
+        size:    size,
+        rng:     rand.New(rand.NewSource(seed)),
+    }
+}
+
+func (r *Reservoir) Add(x float64) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    r.seen++
+    if len(r.samples) < r.size {
+        r.samples = append(r.samples, x)
+        return
+    }
+    if j := r.rng.Int63n(r.seen); j < int64(r.size) {
+        r.samples[j] = x
+    }
+}
+
+func (r *Reservoir) Samples() []float64 {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return append([]float64(nil), r.samples...)
+}
+
+func (r *Reservoir) Seen() int64 {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return r.seen
+}
+
+func (r *Reservoir) Reset() {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.samples = r.samples[:0]
+    r.seen = 0
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+    "math"
+    "testing"
+)
+
+func TestJainIndex(t *testing.T) {
+    tests := []struct {
+        name    string
+        samples []float64
+        want    float64
+    }{
+        {"sin muestras", nil, 0},
+        {"una sola espera", []float64{4}, 1},
+        {"esperas idénticas", []float64{2.5, 2.5, 2.5, 2.5}, 1},
+        {"todas en cero", []float64{0, 0, 0}, 1},
+        {"dos esperas distintas", []float64{1, 3}, 16.0 / 20},
+        {"uno solo esperó", []float64{0, 0, 0, 9}, 0.25},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := JainIndex(tt.samples); math.Abs(got-tt.want) > 1e-12 {
+                t.Errorf("JainIndex = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
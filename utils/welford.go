@@ -0,0 +1,63 @@
+package utils
+
+import (
+    "math"
+    "sync"
+)
+
+// WelfordOnlineStats calcula media y varianza en una sola pasada, sin
+// guardar las muestras (algoritmo de Welford).
+type WelfordOnlineStats struct {
+    mu    sync.Mutex
+    count int64
+    mean  float64
+    m2    float64
+}
+
+func NewWelfordOnlineStats() *WelfordOnlineStats {
+    return &WelfordOnlineStats{}
+}
+
+func (w *WelfordOnlineStats) Update(x float64) {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    w.count++
+    delta := x - w.mean
+    w.mean += delta / float64(w.count)
+    w.m2 += delta * (x - w.mean)
+}
+
+func (w *WelfordOnlineStats) Mean() float64 {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    return w.mean
+}
+
+// Variance devuelve la varianza muestral (dividida entre n-1). Con menos de
+// dos muestras devuelve 0.
+func (w *WelfordOnlineStats) Variance() float64 {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    if w.count < 2 {
+        return 0
+    }
+    return w.m2 / float64(w.count-1)
+}
+
+func (w *WelfordOnlineStats) StdDev() float64 {
+    return math.Sqrt(w.Variance())
+}
+
+func (w *WelfordOnlineStats) Count() int64 {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    return w.count
+}
+
+func (w *WelfordOnlineStats) Reset() {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    w.count = 0
+    w.mean = 0
+    w.m2 = 0
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+    "math"
+    "math/rand"
+    "testing"
+)
+
+func TestWelfordConvergesOnUniform(t *testing.T) {
+    tests := []struct {
+        name   string
+        a, b   float64
+        n      int
+        relTol float64
+    }{
+        {"U(0, 1)", 0, 1, 100000, 0.02},
+        {"U(10, 20)", 10, 20, 100000, 0.02},
+        {"U(-5, 5)", -5, 5, 100000, 0.02},
+        {"estancias de 30 a 120 s", 30, 120, 100000, 0.02},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            rng := rand.New(rand.NewSource(1))
+            stats := NewWelfordOnlineStats()
+            for i := 0; i < tt.n; i++ {
+                stats.Update(tt.a + (tt.b-tt.a)*rng.Float64())
+            }
+
+            wantMean := (tt.a + tt.b) / 2
+            wantVariance := (tt.b - tt.a) * (tt.b - tt.a) / 12
+            if got := stats.Count(); got != int64(tt.n) {
+                t.Errorf("Count = %d, want %d", got, tt.n)
+            }
+            if got := stats.Mean(); math.Abs(got-wantMean) > tt.relTol*(tt.b-tt.a) {
+                t.Errorf("Mean = %.4f, want %.4f", got, wantMean)
+            }
+            if got := stats.Variance(); math.Abs(got-wantVariance) > tt.relTol*wantVariance {
+                t.Errorf("Variance = %.4f, want %.4f", got, wantVariance)
+            }
+            if got := stats.StdDev(); math.Abs(got-math.Sqrt(wantVariance)) > tt.relTol*math.Sqrt(wantVariance) {
+                t.Errorf("StdDev = %.4f, want %.4f", got, math.Sqrt(wantVariance))
+            }
+        })
+    }
+}
+
+func TestWelfordMatchesTwoPass(t *testing.T) {
+    tests := []struct {
+        name     string
+        samples  []float64
+        mean     float64
+        variance float64
+    }{
+        {"sin muestras", nil, 0, 0},
+        {"una muestra", []float64{7}, 7, 0},
+        {"dos muestras", []float64{1, 3}, 2, 2},
+        {"valores grandes con poca dispersión", []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}, 1e9 + 10, 30},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            stats := NewWelfordOnlineStats()
+            for _, x := range tt.samples {
+                stats.Update(x)
+            }
+            if got := stats.Mean(); math.Abs(got-tt.mean) > 1e-6 {
+                t.Errorf("Mean = %v, want %v", got, tt.mean)
+            }
+            if got := stats.Variance(); math.Abs(got-tt.variance) > 1e-6 {
+                t.Errorf("Variance = %v, want %v", got, tt.variance)
+            }
+
+            stats.Reset()
+            if stats.Count() != 0 || stats.Mean() != 0 || stats.Variance() != 0 {
+                t.Errorf("después de Reset: n = %d, media = %v, varianza = %v", stats.Count(), stats.Mean(), stats.Variance())
+            }
+        })
+    }
+}